@@ -58,4 +58,14 @@ func main() {
 	defer logger.Close()
 
 	logger.Info("logging from config", "conf", conf)
+
+	// Want to ship platform-wide defaults in one file and override them in another file? Try "include".
+	// LoadConfig loads all configs included and merges them in order, then the config including them overrides them.
+	// You can also merge two configs by MergeConfig.
+	conf, err = config.LoadConfig("config.json", json.Unmarshal)
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Info("loading config with includes", "conf", conf)
 }
@@ -40,4 +40,10 @@ func main() {
 	// Then you can get the logger from context.
 	logger = logit.FromContext(ctx)
 	logger.Debug("logger from context debug", "key", "value")
+
+	// If you don't have a tracer, use StartSpan and WithTraceAttrs to correlate logs across services.
+	// Set TraceParent(ctx) to the traceparent header of outgoing requests, and use ContextWithTraceParent to parse it.
+	logger = logit.NewLogger(logit.WithLevel(logit.LevelTrace), logit.WithTraceAttrs())
+	ctx = logit.StartSpan(ctx)
+	logger.TraceContext(ctx, "log with trace id and span id", "traceparent", logit.TraceParent(ctx))
 }
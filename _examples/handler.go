@@ -32,6 +32,10 @@ func main() {
 	logger = logit.NewLogger(logit.WithJsonHandler())
 	logger.Info("using json handler")
 
+	// In development, console handler is more human-friendly and its levels are colored in terminals.
+	logger = logit.NewLogger(logit.WithConsoleHandler(), logit.WithSource())
+	logger.Info("using console handler", "user_id", 123, "stack", "line1\nline2")
+
 	// Or you want to use customized handlers, try Register.
 	newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
 		return slog.NewTextHandler(w, opts)
@@ -15,7 +15,9 @@
 package main
 
 import (
+	"fmt"
 	"io"
+	"log/slog"
 
 	"github.com/FishGoddess/logit"
 )
@@ -52,6 +54,22 @@ func main() {
 	logger.Print("print log")
 	logger.Println("println log")
 
+	// Named adds a "logger" attr to logs, which is useful for naming components.
+	logger.Named("app").Named("db").Info("named log")
+
+	// Some libraries log through slog, and you can let their logs flow through logit by Slog().
+	// Also, SetAsSlogDefault sets a logger as the default logger of slog.
+	slogLogger := logger.Slog()
+	slogLogger.Info("log from slog")
+
+	logit.SetAsSlogDefault(logger)
+
+	// Writer returns a writer which logs each line written to it, like outputs of subprocesses.
+	// Close it after writing, so the last line without a line break will be logged.
+	writer := logger.Writer(slog.LevelInfo)
+	fmt.Fprint(writer, "line from writer")
+	writer.Close()
+
 	// Some useful method:
 	logger.Sync()
 	logger.Close()
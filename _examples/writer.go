@@ -16,8 +16,10 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/extension/httpwriter"
 )
 
 func main() {
@@ -40,4 +42,11 @@ func main() {
 
 	logger = logit.NewLogger(logit.WithRotateFile("logit.log"))
 	logger.Debug("log to rotate file")
+
+	// Want to post logs to a http api? Try httpwriter with json handler.
+	// Logs are posted in batches, so use WithSyncTimer to post them periodically.
+	writer := httpwriter.New("http://localhost:8080/logs", httpwriter.WithGzip(), httpwriter.WithRetry(3, time.Second))
+	logger = logit.NewLogger(logit.WithWriter(writer), logit.WithJsonHandler(), logit.WithSyncTimer(time.Second))
+	logger.Debug("log to http api")
+	logger.Close()
 }
@@ -0,0 +1,236 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// DropPolicy is the policy of handling records when the queue of async handler is full, see WithAsync.
+type DropPolicy string
+
+const (
+	// DropPolicyBlock blocks logging until the queue has space, so no records will be dropped.
+	DropPolicyBlock DropPolicy = "block"
+
+	// DropPolicyOldest drops the oldest record in the queue to make space for the new one.
+	DropPolicyOldest DropPolicy = "oldest"
+
+	// DropPolicyNewest drops the new record, so records in the queue are kept.
+	DropPolicyNewest DropPolicy = "newest"
+)
+
+// ParseDropPolicy parses a drop policy from name and returns an error if failed.
+func ParseDropPolicy(name string) (DropPolicy, error) {
+	switch policy := DropPolicy(name); policy {
+	case DropPolicyBlock, DropPolicyOldest, DropPolicyNewest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("logit: drop policy %s unknown", name)
+	}
+}
+
+type asyncRecord struct {
+	handler slog.Handler
+	ctx     context.Context
+	record  slog.Record
+
+	// synced is closed by the worker when it's reached, which means all records before it have been handled.
+	// The record is only a marker of syncing if synced isn't nil.
+	synced chan struct{}
+}
+
+// asyncQueue handles records in a background worker with a bounded queue.
+type asyncQueue struct {
+	queue   chan asyncRecord
+	policy  DropPolicy
+	ttl     time.Duration
+	dropped atomic.Uint64
+	stats   *recordStats
+	done    chan struct{}
+	closed  bool
+	lock    sync.RWMutex
+}
+
+func newAsyncQueue(size int, policy DropPolicy, ttl time.Duration, stats *recordStats) *asyncQueue {
+	aq := &asyncQueue{
+		queue:  make(chan asyncRecord, max(size, 1)),
+		policy: policy,
+		ttl:    ttl,
+		stats:  stats,
+		done:   make(chan struct{}),
+	}
+
+	go aq.work()
+	return aq
+}
+
+func (aq *asyncQueue) work() {
+	defer close(aq.done)
+
+	for ar := range aq.queue {
+		if ar.synced != nil {
+			close(ar.synced)
+			continue
+		}
+
+		if aq.stale(ar.record) {
+			aq.drop()
+			continue
+		}
+
+		if err := ar.handler.Handle(ar.ctx, ar.record); err != nil {
+			defaults.HandleError("asyncQueue.handle", err)
+		}
+	}
+}
+
+// stale reports whether record has been in the queue longer than ttl and can be dropped.
+// Records in warn level or higher are never stale, so they're always delivered.
+func (aq *asyncQueue) stale(record slog.Record) bool {
+	if aq.ttl <= 0 || record.Level >= slog.LevelWarn || record.Time.IsZero() {
+		return false
+	}
+
+	return defaults.CurrentTime().Sub(record.Time) > aq.ttl
+}
+
+func (aq *asyncQueue) drop() {
+	aq.dropped.Add(1)
+	aq.stats.recordDropped()
+}
+
+// push pushes ar to the queue in policy and reports whether it's pushed.
+// It should be called with read lock held, so the queue won't be closed.
+func (aq *asyncQueue) push(ar asyncRecord, policy DropPolicy) bool {
+	switch policy {
+	case DropPolicyNewest:
+		select {
+		case aq.queue <- ar:
+			return true
+		default:
+			aq.drop()
+			return false
+		}
+	case DropPolicyOldest:
+		for {
+			select {
+			case aq.queue <- ar:
+				return true
+			default:
+			}
+
+			// The worker may take the oldest record at the same time, so it's fine to drop nothing.
+			select {
+			case old := <-aq.queue:
+				// Records before a marker have been taken by the worker, so it's almost synced and won't be dropped.
+				if old.synced != nil {
+					close(old.synced)
+				} else {
+					aq.drop()
+				}
+			default:
+			}
+		}
+	default:
+		aq.queue <- ar
+		return true
+	}
+}
+
+func (aq *asyncQueue) handle(ctx context.Context, handler slog.Handler, record slog.Record) error {
+	aq.lock.RLock()
+	defer aq.lock.RUnlock()
+
+	if aq.closed {
+		aq.stats.recordDropped()
+		return ErrLoggerClosed
+	}
+
+	// The record is handled later, so clone it in case the caller modifies it.
+	if aq.push(asyncRecord{handler: handler, ctx: ctx, record: record.Clone()}, aq.policy) {
+		aq.stats.recordEnqueued()
+	}
+
+	return nil
+}
+
+// Sync waits until all records in the queue before calling it have been handled.
+func (aq *asyncQueue) Sync() error {
+	aq.lock.RLock()
+	if aq.closed {
+		aq.lock.RUnlock()
+		return nil
+	}
+
+	synced := make(chan struct{})
+	aq.push(asyncRecord{synced: synced}, DropPolicyBlock)
+	aq.lock.RUnlock()
+
+	<-synced
+	return nil
+}
+
+// Close stops accepting records and waits until all records in the queue have been handled.
+// The count of dropped records will be passed to defaults.HandleError if some records were dropped.
+func (aq *asyncQueue) Close() error {
+	aq.lock.Lock()
+	if aq.closed {
+		aq.lock.Unlock()
+		return nil
+	}
+
+	aq.closed = true
+	close(aq.queue)
+	aq.lock.Unlock()
+
+	<-aq.done
+
+	if dropped := aq.dropped.Load(); dropped > 0 {
+		defaults.HandleError("asyncQueue.Close", fmt.Errorf("logit: %d records dropped by async queue", dropped))
+	}
+
+	return nil
+}
+
+// asyncHandler moves handling records to the background worker of queue.
+type asyncHandler struct {
+	slog.Handler
+
+	queue *asyncQueue
+}
+
+func newAsyncHandler(handler slog.Handler, queue *asyncQueue) slog.Handler {
+	return asyncHandler{Handler: handler, queue: queue}
+}
+
+func (ah asyncHandler) Handle(ctx context.Context, record slog.Record) error {
+	return ah.queue.handle(ctx, ah.Handler, record)
+}
+
+func (ah asyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newAsyncHandler(ah.Handler.WithAttrs(attrs), ah.queue)
+}
+
+func (ah asyncHandler) WithGroup(name string) slog.Handler {
+	return newAsyncHandler(ah.Handler.WithGroup(name), ah.queue)
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// blockedHandler blocks handling records until unblock is closed.
+type blockedHandler struct {
+	slog.Handler
+
+	unblock chan struct{}
+}
+
+func (bh *blockedHandler) Handle(ctx context.Context, record slog.Record) error {
+	<-bh.unblock
+	return bh.Handler.Handle(ctx, record)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseDropPolicy$
+func TestParseDropPolicy(t *testing.T) {
+	for _, name := range []string{"block", "oldest", "newest"} {
+		policy, err := ParseDropPolicy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(policy) != name {
+			t.Fatalf("policy %s != name %s", policy, name)
+		}
+	}
+
+	if _, err := ParseDropPolicy("all"); err == nil {
+		t.Fatal("unknown drop policy should return an error")
+	}
+}
+
+func testAsyncQueue(t *testing.T, policy DropPolicy, want string, wantStats Stats) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := &blockedHandler{
+		Handler: slog.NewTextHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		}}),
+		unblock: make(chan struct{}),
+	}
+
+	stats := new(recordStats)
+	queue := newAsyncQueue(2, policy, 0, stats)
+	logger := slog.New(newAsyncHandler(handler, queue))
+
+	// The worker takes the first record and blocks, so the queue has two records at most.
+	logger.Info("1")
+	time.Sleep(10 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 2; i <= 5; i++ {
+			logger.Info(string(rune('0' + i)))
+		}
+	}()
+
+	if policy != DropPolicyBlock {
+		wg.Wait()
+	}
+
+	close(handler.unblock)
+	wg.Wait()
+
+	queue.Sync()
+	queue.Close()
+
+	got := strings.ReplaceAll(buffer.String(), "\n", ",")
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	if err := queue.handle(context.Background(), handler, slog.Record{}); err != ErrLoggerClosed {
+		t.Fatalf("err %+v != ErrLoggerClosed", err)
+	}
+
+	if got := stats.snapshot(); got != wantStats {
+		t.Fatalf("got %+v != wantStats %+v", got, wantStats)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncQueue$
+func TestAsyncQueue(t *testing.T) {
+	testAsyncQueue(t, DropPolicyBlock, "msg=1,msg=2,msg=3,msg=4,msg=5,", Stats{Enqueued: 5, Dropped: 1})
+	testAsyncQueue(t, DropPolicyNewest, "msg=1,msg=2,msg=3,", Stats{Enqueued: 3, Dropped: 3})
+	testAsyncQueue(t, DropPolicyOldest, "msg=1,msg=4,msg=5,", Stats{Enqueued: 5, Dropped: 3})
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncHandler$
+func TestAsyncHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithAsync(16, ""))
+	for i := 0; i < 10; i++ {
+		logger.With("i", i).Info("msg")
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(buffer.String(), "¦ msg ¦"); got != 10 {
+		t.Fatalf("got %d != 10", got)
+	}
+
+	logger.Info("last")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ last\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncQueueTTL$
+func TestAsyncQueueTTL(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	defaults.CurrentTime = func() time.Time { return now }
+	defer func() { defaults.CurrentTime = time.Now }()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := slog.NewTextHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}})
+
+	stats := new(recordStats)
+	queue := newAsyncQueue(16, DropPolicyBlock, time.Minute, stats)
+
+	records := []slog.Record{
+		slog.NewRecord(now.Add(-time.Hour), slog.LevelDebug, "stale", 0),
+		slog.NewRecord(now.Add(-time.Hour), slog.LevelInfo, "stale", 0),
+		slog.NewRecord(now.Add(-time.Hour), slog.LevelWarn, "warn", 0),
+		slog.NewRecord(now.Add(-time.Hour), slog.LevelError, "error", 0),
+		slog.NewRecord(now.Add(-time.Second), slog.LevelInfo, "fresh", 0),
+		slog.NewRecord(time.Time{}, slog.LevelInfo, "untimed", 0),
+	}
+
+	for _, record := range records {
+		if err := queue.handle(context.Background(), handler, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue.Close()
+
+	want := "level=WARN msg=warn,level=ERROR msg=error,level=INFO msg=fresh,level=INFO msg=untimed,"
+	if got := strings.ReplaceAll(buffer.String(), "\n", ","); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	wantStats := Stats{Enqueued: 6, Dropped: 2}
+	if got := stats.snapshot(); got != wantStats {
+		t.Fatalf("got %+v != wantStats %+v", got, wantStats)
+	}
+}
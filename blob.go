@@ -0,0 +1,206 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	keyBlobRef  = "blob_ref"
+	keyBlobHash = "blob_hash"
+	keyBlobSize = "blob_size"
+)
+
+// BlobStore stores big values of attrs out of logs, like in files or object storages, see WithBlobOffload.
+type BlobStore interface {
+	// Store stores data of the attr having key and returns the reference of data like a path or an url.
+	// The hash is the hex sha256 of data, so it can be used as the name of data.
+	Store(ctx context.Context, key string, hash string, data []byte) (ref string, err error)
+}
+
+// FileBlobStore stores big values of attrs in files of a directory, which are named by their hashes.
+// Values having the same data are stored only once.
+type FileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore returns a new blob store storing data in dir.
+// The permission bits can be specified by defaults package, see defaults.FileDirMode and defaults.FileMode.
+func NewFileBlobStore(dir string) *FileBlobStore {
+	return &FileBlobStore{dir: dir}
+}
+
+// Store stores data in a file named by hash and returns the path of the file.
+func (fbs *FileBlobStore) Store(ctx context.Context, key string, hash string, data []byte) (string, error) {
+	path := filepath.Join(fbs.dir, hash+".blob")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	if err := defaults.OpenFileDir(fbs.dir, defaults.FileDirMode); err != nil {
+		return "", err
+	}
+
+	// Data is written to a temp file and then renamed, so a file named by hash is always complete.
+	temp, err := os.CreateTemp(fbs.dir, hash+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(temp.Name())
+
+	if _, err = temp.Write(data); err != nil {
+		temp.Close()
+		return "", err
+	}
+
+	if err = temp.Close(); err != nil {
+		return "", err
+	}
+
+	if err = os.Chmod(temp.Name(), defaults.FileMode); err != nil {
+		return "", err
+	}
+
+	if err = os.Rename(temp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// blobOffloader replaces values of attrs bigger than threshold with references of them in store.
+type blobOffloader struct {
+	store     BlobStore
+	threshold int
+}
+
+func newBlobOffloader(store BlobStore, threshold int) *blobOffloader {
+	return &blobOffloader{store: store, threshold: threshold}
+}
+
+// blobData returns the data of value and reports whether it's big enough to be offloaded.
+// Only strings and bytes are offloaded, because other values are usually small and their forms depend on handlers.
+func (bo *blobOffloader) blobData(value slog.Value) ([]byte, bool) {
+	switch value.Kind() {
+	case slog.KindString:
+		if s := value.String(); len(s) > bo.threshold {
+			return []byte(s), true
+		}
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case []byte:
+			return v, len(v) > bo.threshold
+		case json.RawMessage:
+			return v, len(v) > bo.threshold
+		}
+	}
+
+	return nil, false
+}
+
+func (bo *blobOffloader) offloadAttr(ctx context.Context, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		offloadedAttrs := make([]slog.Attr, 0, len(groupAttrs))
+
+		for _, groupAttr := range groupAttrs {
+			offloadedAttrs = append(offloadedAttrs, bo.offloadAttr(ctx, groupAttr))
+		}
+
+		attr.Value = slog.GroupValue(offloadedAttrs...)
+		return attr
+	}
+
+	data, ok := bo.blobData(attr.Value)
+	if !ok {
+		return attr
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	// The value is kept if storing failed, so it won't be lost.
+	ref, err := bo.store.Store(ctx, attr.Key, hash, data)
+	if err != nil {
+		defaults.HandleError("blobOffloader.offload", err)
+		return attr
+	}
+
+	refAttrs := []slog.Attr{
+		slog.String(keyBlobRef, ref),
+		slog.String(keyBlobHash, "sha256:"+hash),
+		slog.Int(keyBlobSize, len(data)),
+	}
+
+	attr.Value = slog.GroupValue(refAttrs...)
+	return attr
+}
+
+func (bo *blobOffloader) offloadAttrs(ctx context.Context, attrs []slog.Attr) []slog.Attr {
+	offloadedAttrs := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		offloadedAttrs = append(offloadedAttrs, bo.offloadAttr(ctx, attr))
+	}
+
+	return offloadedAttrs
+}
+
+// blobHandler offloads big values of attrs before handling records.
+// Attrs added by WithAttrs are offloaded once when adding them.
+type blobHandler struct {
+	slog.Handler
+
+	offloader *blobOffloader
+}
+
+func newBlobHandler(handler slog.Handler, offloader *blobOffloader) slog.Handler {
+	return blobHandler{Handler: handler, offloader: offloader}
+}
+
+func (bh blobHandler) Handle(ctx context.Context, record slog.Record) error {
+	offloaded := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		offloaded.AddAttrs(bh.offloader.offloadAttr(ctx, attr))
+		return true
+	})
+
+	return bh.Handler.Handle(ctx, offloaded)
+}
+
+func (bh blobHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = bh.offloader.offloadAttrs(context.Background(), attrs)
+	return newBlobHandler(bh.Handler.WithAttrs(attrs), bh.offloader)
+}
+
+func (bh blobHandler) WithGroup(name string) slog.Handler {
+	return newBlobHandler(bh.Handler.WithGroup(name), bh.offloader)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testBlobStore struct {
+	blobs map[string][]byte
+	err   error
+}
+
+func (tbs *testBlobStore) Store(ctx context.Context, key string, hash string, data []byte) (string, error) {
+	if tbs.err != nil {
+		return "", tbs.err
+	}
+
+	ref := key + "/" + hash
+	tbs.blobs[ref] = bytes.Clone(data)
+
+	return ref, nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFileBlobStore$
+func TestFileBlobStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "blobs")
+	store := NewFileBlobStore(dir)
+
+	data := []byte(t.Name())
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	for i := 0; i < 2; i++ {
+		ref, err := store.Store(context.Background(), "key", hash, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := filepath.Join(dir, hash+".blob"); ref != want {
+			t.Fatalf("ref %s != want %s", ref, want)
+		}
+
+		got, err := os.ReadFile(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, data) {
+			t.Fatalf("got %s != data %s", got, data)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) %d != 1", len(entries))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBlobOffloader$
+func TestBlobOffloader(t *testing.T) {
+	store := &testBlobStore{blobs: make(map[string][]byte)}
+	offloader := newBlobOffloader(store, 4)
+
+	big := "12345"
+	sum := sha256.Sum256([]byte(big))
+	hash := hex.EncodeToString(sum[:])
+
+	attrs := offloader.offloadAttrs(context.Background(), []slog.Attr{
+		slog.String("small", "1234"),
+		slog.String("big", big),
+		slog.Any("bytes", []byte(big)),
+		slog.Any("json", json.RawMessage(big)),
+		slog.Int("int", 123456789),
+		slog.Group("group", slog.String("big", big)),
+	})
+
+	want := slog.GroupValue(
+		slog.String(keyBlobRef, "big/"+hash),
+		slog.String(keyBlobHash, "sha256:"+hash),
+		slog.Int(keyBlobSize, len(big)),
+	)
+
+	if attrs[0].Value.String() != "1234" || attrs[4].Value.Int64() != 123456789 {
+		t.Fatalf("attrs %+v is wrong", attrs)
+	}
+
+	if !attrs[1].Value.Equal(want) {
+		t.Fatalf("attrs[1] %+v != want %+v", attrs[1].Value, want)
+	}
+
+	for _, attr := range []slog.Attr{attrs[2], attrs[3], attrs[5].Value.Group()[0]} {
+		if attr.Value.Kind() != slog.KindGroup || attr.Value.Group()[1].Value.String() != "sha256:"+hash {
+			t.Fatalf("attr %+v is wrong", attr)
+		}
+	}
+
+	if len(store.blobs) != 3 || string(store.blobs["big/"+hash]) != big {
+		t.Fatalf("store.blobs %+v is wrong", store.blobs)
+	}
+
+	store.err = errors.New("store")
+
+	attr := offloader.offloadAttr(context.Background(), slog.String("big", big))
+	if attr.Value.String() != big {
+		t.Fatalf("attr %+v should be kept", attr)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBlobHandler$
+func TestBlobHandler(t *testing.T) {
+	store := &testBlobStore{blobs: make(map[string][]byte)}
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	big := strings.Repeat("x", 64)
+	logger := NewLogger(WithWriter(buffer), WithHandler("text"), WithBlobOffload(store, 32))
+	logger.With("with", big).Info("msg", "key", big, "small", "value")
+
+	got := buffer.String()
+	if strings.Contains(got, big) {
+		t.Fatalf("got %s shouldn't contain big value", got)
+	}
+
+	wants := []string{"with.blob_ref=with/", "key.blob_ref=key/", "key.blob_size=64", "small=value"}
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Fatalf("got %s doesn't contain %s", got, want)
+		}
+	}
+
+	if len(store.blobs) != 2 {
+		t.Fatalf("len(store.blobs) %d != 2", len(store.blobs))
+	}
+}
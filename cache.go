@@ -0,0 +1,179 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"container/list"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+var (
+	loggerCache = newCachedLoggers()
+)
+
+type cachedKey struct {
+	parent *Logger
+	attrs  string
+}
+
+type cachedEntry struct {
+	key    cachedKey
+	logger *Logger
+}
+
+// cachedLoggers is a lru cache of loggers derived by WithCached.
+type cachedLoggers struct {
+	entries  *list.List
+	elements map[cachedKey]*list.Element
+
+	lock sync.Mutex
+}
+
+func newCachedLoggers() *cachedLoggers {
+	cache := &cachedLoggers{
+		entries:  list.New(),
+		elements: make(map[cachedKey]*list.Element, 64),
+	}
+
+	return cache
+}
+
+func (cl *cachedLoggers) get(key cachedKey) (*Logger, bool) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	element, ok := cl.elements[key]
+	if !ok {
+		return nil, false
+	}
+
+	cl.entries.MoveToFront(element)
+	return element.Value.(*cachedEntry).logger, true
+}
+
+func (cl *cachedLoggers) set(key cachedKey, logger *Logger) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	if element, ok := cl.elements[key]; ok {
+		element.Value.(*cachedEntry).logger = logger
+		cl.entries.MoveToFront(element)
+		return
+	}
+
+	cl.elements[key] = cl.entries.PushFront(&cachedEntry{key: key, logger: logger})
+
+	for cl.entries.Len() > defaults.MaxCachedLoggers {
+		oldest := cl.entries.Back()
+		cl.entries.Remove(oldest)
+		delete(cl.elements, oldest.Value.(*cachedEntry).key)
+	}
+}
+
+// appendCachedValue appends value to bs and reports whether the value can be a part of cached key.
+func appendCachedValue(bs []byte, value slog.Value) ([]byte, bool) {
+	bs = append(bs, byte('0'+value.Kind()))
+
+	switch value.Kind() {
+	case slog.KindString:
+		return strconv.AppendQuote(bs, value.String()), true
+	case slog.KindInt64:
+		return strconv.AppendInt(bs, value.Int64(), 10), true
+	case slog.KindUint64:
+		return strconv.AppendUint(bs, value.Uint64(), 10), true
+	case slog.KindFloat64:
+		return strconv.AppendFloat(bs, value.Float64(), 'g', -1, 64), true
+	case slog.KindBool:
+		return strconv.AppendBool(bs, value.Bool()), true
+	case slog.KindDuration:
+		return strconv.AppendInt(bs, int64(value.Duration()), 10), true
+	case slog.KindGroup:
+		bs = append(bs, '{')
+
+		var ok bool
+		for _, attr := range value.Group() {
+			if bs, ok = appendCachedAttr(bs, attr); !ok {
+				return bs, false
+			}
+		}
+
+		return append(bs, '}'), true
+	default:
+		return bs, false
+	}
+}
+
+func appendCachedAttr(bs []byte, attr slog.Attr) ([]byte, bool) {
+	bs = strconv.AppendQuote(bs, attr.Key)
+	bs = append(bs, '=')
+	bs, ok := appendCachedValue(bs, attr.Value)
+	bs = append(bs, ';')
+
+	return bs, ok
+}
+
+// newCachedKey returns the key of attrs and reports whether attrs can be cached.
+// Only attrs with values of basic kinds can be cached, which can be compared exactly.
+func newCachedKey(attrs []slog.Attr) (string, bool) {
+	bs := make([]byte, 0, 64)
+
+	var ok bool
+	for _, attr := range attrs {
+		if bs, ok = appendCachedAttr(bs, attr); !ok {
+			return "", false
+		}
+	}
+
+	return string(bs), true
+}
+
+// WithCached returns a new logger with args like With, but the new logger will be cached.
+// Calling it with the same args again returns the cached logger, so handler won't rebuild its state each time.
+// It's useful for deriving loggers in every request with a small set of args.
+// Only args having values of basic kinds like string and int will be cached, others will call With directly.
+// The cache is a lru cache and its size can be specified by defaults.MaxCachedLoggers.
+func (l *Logger) WithCached(args ...any) *Logger {
+	if len(args) <= 0 || l.isNop() {
+		return l
+	}
+
+	attrs := l.newAttrs(args)
+	if len(attrs) <= 0 {
+		return l
+	}
+
+	attrsKey, cacheable := newCachedKey(attrs)
+	key := cachedKey{parent: l, attrs: attrsKey}
+
+	if cacheable {
+		if logger, ok := loggerCache.get(key); ok {
+			return logger
+		}
+	}
+
+	newLogger := l.derive()
+	newLogger.handler = l.handler.WithAttrs(attrs)
+	newLogger.recordDerivation(&derivation{attrs: attrs})
+
+	if cacheable {
+		loggerCache.set(key, newLogger)
+	}
+
+	return newLogger
+}
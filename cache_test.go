@@ -0,0 +1,147 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewCachedKey$
+func TestNewCachedKey(t *testing.T) {
+	key1, ok := newCachedKey([]slog.Attr{slog.String("a", "b;c=d"), slog.Int("n", 1)})
+	if !ok {
+		t.Fatal("attrs should be cacheable")
+	}
+
+	key2, ok := newCachedKey([]slog.Attr{slog.String("a", "b"), slog.String("c", "d"), slog.Int("n", 1)})
+	if !ok {
+		t.Fatal("attrs should be cacheable")
+	}
+
+	key3, ok := newCachedKey([]slog.Attr{slog.String("a", "b;c=d"), slog.String("n", "1")})
+	if !ok {
+		t.Fatal("attrs should be cacheable")
+	}
+
+	if key1 == key2 || key1 == key3 || key2 == key3 {
+		t.Fatalf("keys %s, %s, %s should be different", key1, key2, key3)
+	}
+
+	_, ok = newCachedKey([]slog.Attr{slog.Group("g", slog.Bool("b", true), slog.Duration("d", time.Second))})
+	if !ok {
+		t.Fatal("group should be cacheable")
+	}
+
+	notCacheable := [][]slog.Attr{
+		{slog.Any("any", []int{1})},
+		{slog.Time("time", time.Now())},
+		{slog.Group("g", slog.Any("any", struct{}{}))},
+	}
+
+	for _, attrs := range notCacheable {
+		if _, ok = newCachedKey(attrs); ok {
+			t.Fatalf("attrs %+v shouldn't be cacheable", attrs)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWithCached$
+func TestLoggerWithCached(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+
+	logger1 := logger.WithCached("route", "/a", "status", 200)
+	logger2 := logger.WithCached("route", "/a", "status", 200)
+	logger3 := logger.WithCached("route", "/b", "status", 200)
+
+	if logger1 != logger2 || logger1 == logger3 {
+		t.Fatalf("logger1 %p, logger2 %p, logger3 %p are wrong", logger1, logger2, logger3)
+	}
+
+	if logger.With("route", "/a").WithCached("k", "v") == logger.WithCached("route", "/a").WithCached("k", "v") {
+		t.Fatal("loggers derived from different parents shouldn't be the same")
+	}
+
+	if logger.WithCached("any", []int{1}) == logger.WithCached("any", []int{1}) {
+		t.Fatal("loggers with uncacheable args shouldn't be cached")
+	}
+
+	if logger.WithCached() != logger {
+		t.Fatal("logger with no args should be itself")
+	}
+
+	logger3.Info("msg")
+
+	if log := strings.TrimSpace(buffer.String()); !strings.HasSuffix(log, "route=/b ¦ status=200") {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCachedLoggers$
+func TestCachedLoggers(t *testing.T) {
+	maxCachedLoggers := defaults.MaxCachedLoggers
+	defer func() {
+		defaults.MaxCachedLoggers = maxCachedLoggers
+	}()
+
+	defaults.MaxCachedLoggers = 2
+
+	cache := newCachedLoggers()
+	loggers := []*Logger{new(Logger), new(Logger), new(Logger)}
+	keys := []cachedKey{{attrs: "1"}, {attrs: "2"}, {attrs: "3"}}
+
+	cache.set(keys[0], loggers[0])
+	cache.set(keys[1], loggers[1])
+
+	// Key 1 becomes the newest one so key 2 will be evicted.
+	if logger, ok := cache.get(keys[0]); !ok || logger != loggers[0] {
+		t.Fatalf("logger %p or ok %+v is wrong", logger, ok)
+	}
+
+	cache.set(keys[2], loggers[2])
+
+	if _, ok := cache.get(keys[1]); ok {
+		t.Fatal("key 2 should be evicted")
+	}
+
+	for _, i := range []int{0, 2} {
+		if logger, ok := cache.get(keys[i]); !ok || logger != loggers[i] {
+			t.Fatalf("logger %p or ok %+v is wrong", logger, ok)
+		}
+	}
+
+	if cache.entries.Len() != 2 || len(cache.elements) != 2 {
+		t.Fatalf("entries %d or elements %d is wrong", cache.entries.Len(), len(cache.elements))
+	}
+}
+
+// go test -v -run=^$ -bench=^BenchmarkLoggerWithCached$ -benchtime=1s
+func BenchmarkLoggerWithCached(b *testing.B) {
+	logger := NewLogger(WithWriter(io.Discard))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.WithCached("route", "/a", "status", 200)
+	}
+}
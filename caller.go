@@ -0,0 +1,108 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// maxCallerFrames is the max count of frames walked to find a caller not skipped.
+	maxCallerFrames = 32
+)
+
+var (
+	// skippedPackages stores all package path prefixes registered by SkipCallers.
+	// It's copied on writing so reading it doesn't need a lock.
+	skippedPackages atomic.Pointer[[]string]
+
+	skippedPackagesLock sync.Mutex
+)
+
+// SkipCallers registers package path prefixes like "mycorp/logwrap", so frames in these packages are skipped when getting the caller of logs.
+// It's useful if you wrap logger in helpers at varying depths, which can't be handled by a fixed skip like WithCallerDepth.
+// A prefix matches the package with the same path and all packages under it, like "mycorp/logwrap/sub".
+// Notice that prefixes are registered globally, so all loggers will skip them.
+func SkipCallers(prefixes ...string) {
+	skippedPackagesLock.Lock()
+	defer skippedPackagesLock.Unlock()
+
+	packages := make([]string, 0, 8)
+	if oldPackages := skippedPackages.Load(); oldPackages != nil {
+		packages = append(packages, *oldPackages...)
+	}
+
+	for _, prefix := range prefixes {
+		if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+			packages = append(packages, prefix)
+		}
+	}
+
+	skippedPackages.Store(&packages)
+}
+
+// functionPackage returns the package path of function, like "mycorp/logwrap" of "mycorp/logwrap.(*Logger).Info".
+// Dots in the last element of package path are escaped as "%2e" in function names, so they will be unescaped.
+func functionPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/') + 1
+
+	if dot := strings.IndexByte(function[slash:], '.'); dot >= 0 {
+		function = function[:slash+dot]
+	}
+
+	return strings.ReplaceAll(function, "%2e", ".")
+}
+
+// skippedFunction reports whether function is in one of packages.
+func skippedFunction(function string, packages []string) bool {
+	pkg := functionPackage(function)
+
+	for _, prefix := range packages {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callerPC returns the pc of caller, which skips skip frames and frames in packages registered by SkipCallers.
+// The skip is the same as runtime.Callers and the frame of callerPC is skipped.
+// The first frame after skipping will be returned if all frames walked are in packages registered.
+func callerPC(skip int) uintptr {
+	packages := skippedPackages.Load()
+	if packages == nil || len(*packages) <= 0 {
+		var pcs [1]uintptr
+		runtime.Callers(skip+1, pcs[:])
+		return pcs[0]
+	}
+
+	var pcs [maxCallerFrames]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+
+	for _, pc := range pcs[:n] {
+		frames := runtime.CallersFrames([]uintptr{pc})
+		frame, _ := frames.Next()
+
+		if !skippedFunction(frame.Function, *packages) {
+			return pc
+		}
+	}
+
+	return pcs[0]
+}
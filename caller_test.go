@@ -0,0 +1,81 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFunctionPackage$
+func TestFunctionPackage(t *testing.T) {
+	testCases := map[string]string{
+		"main.main":                              "main",
+		"mycorp/logwrap.Info":                    "mycorp/logwrap",
+		"mycorp/logwrap.(*Logger).Info":          "mycorp/logwrap",
+		"mycorp/logwrap.Info.func1":              "mycorp/logwrap",
+		"github.com/FishGoddess/logit.(*Logger)": "github.com/FishGoddess/logit",
+		"gopkg.in/yaml%2ev3.Unmarshal":           "gopkg.in/yaml.v3",
+		"unknown":                                "unknown",
+	}
+
+	for function, want := range testCases {
+		if pkg := functionPackage(function); pkg != want {
+			t.Fatalf("function %s: pkg %s != want %s", function, pkg, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSkippedFunction$
+func TestSkippedFunction(t *testing.T) {
+	packages := []string{"mycorp/logwrap"}
+
+	testCases := map[string]bool{
+		"mycorp/logwrap.Info":           true,
+		"mycorp/logwrap.(*Logger).Info": true,
+		"mycorp/logwrap/sub.Info":       true,
+		"mycorp/logwrapper.Info":        false,
+		"mycorp/app.main":               false,
+	}
+
+	for function, want := range testCases {
+		if skipped := skippedFunction(function, packages); skipped != want {
+			t.Fatalf("function %s: skipped %+v != want %+v", function, skipped, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSkipCallers$
+func TestSkipCallers(t *testing.T) {
+	defer skippedPackages.Store(nil)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	SkipCallers("", "github.com/FishGoddess/logit/")
+
+	packages := skippedPackages.Load()
+	if packages == nil || strings.Join(*packages, ",") != "github.com/FishGoddess/logit" {
+		t.Fatalf("packages %+v is wrong", packages)
+	}
+
+	// All frames in logit are skipped including this test, so the caller is the testing package.
+	logByHelper(logger, "msg")
+
+	if got := buffer.String(); !strings.Contains(got, `"function":"testing.tRunner"`) {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
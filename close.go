@@ -0,0 +1,104 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+var (
+	// ErrLoggerClosed is passed to defaults.HandleError once if a logger logs after it's closed.
+	ErrLoggerClosed = errors.New("logit: logger is closed")
+)
+
+// closeState records whether a logger is closed.
+// It's shared by all loggers derived from the same logger, so closing one of them closes all of them.
+type closeState struct {
+	closed   atomic.Bool
+	reported atomic.Bool
+
+	// done will be closed when closing, so goroutines like sync timer can exit.
+	done chan struct{}
+}
+
+func newCloseState() *closeState {
+	state := &closeState{
+		done: make(chan struct{}),
+	}
+
+	return state
+}
+
+// close marks the state closed and reports whether it's closed at the first time.
+// A nil state is always closed at the first time, so loggers without a state can still be closed.
+func (cs *closeState) close() bool {
+	if cs == nil {
+		return true
+	}
+
+	if !cs.closed.CompareAndSwap(false, true) {
+		return false
+	}
+
+	close(cs.done)
+	return true
+}
+
+// isClosed reports whether the state is closed.
+func (cs *closeState) isClosed() bool {
+	return cs != nil && cs.closed.Load()
+}
+
+// report reports ErrLoggerClosed once, so logging after closed won't flood the error handler.
+func (cs *closeState) report() {
+	if cs.reported.CompareAndSwap(false, true) {
+		defaults.HandleError("Logger.log", ErrLoggerClosed)
+	}
+}
+
+// closeHandler discards records after closing like Logger.log, so loggers returned by Logger.Slog won't write to closed writers.
+type closeHandler struct {
+	slog.Handler
+
+	closeState *closeState
+	stats      *recordStats
+}
+
+func newCloseHandler(handler slog.Handler, closeState *closeState, stats *recordStats) slog.Handler {
+	return closeHandler{Handler: handler, closeState: closeState, stats: stats}
+}
+
+func (ch closeHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ch.closeState.isClosed() {
+		ch.stats.recordDropped()
+		ch.closeState.report()
+		return nil
+	}
+
+	return ch.Handler.Handle(ctx, record)
+}
+
+func (ch closeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newCloseHandler(ch.Handler.WithAttrs(attrs), ch.closeState, ch.stats)
+}
+
+func (ch closeHandler) WithGroup(name string) slog.Handler {
+	return newCloseHandler(ch.Handler.WithGroup(name), ch.closeState, ch.stats)
+}
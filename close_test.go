@@ -0,0 +1,175 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCloseState$
+func TestCloseState(t *testing.T) {
+	var nilState *closeState
+	if nilState.isClosed() {
+		t.Fatal("nil state is closed")
+	}
+
+	if !nilState.close() {
+		t.Fatal("nil state should be closed at the first time")
+	}
+
+	state := newCloseState()
+	if state.isClosed() {
+		t.Fatal("state is closed")
+	}
+
+	if !state.close() {
+		t.Fatal("state should be closed at the first time")
+	}
+
+	if state.close() {
+		t.Fatal("state shouldn't be closed twice")
+	}
+
+	if !state.isClosed() {
+		t.Fatal("state isn't closed")
+	}
+
+	select {
+	case <-state.done:
+	default:
+		t.Fatal("state.done isn't closed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseTwice$
+func TestLoggerCloseTwice(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	var errs []error
+	defaults.HandleError = func(label string, err error) {
+		errs = append(errs, err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBuffer(1024))
+	logger.Info("before closing")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("after closing")
+	logger.With("k", "v").Info("after closing")
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); bytes.Count([]byte(got), []byte("\n")) != 1 {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrLoggerClosed) {
+		t.Fatalf("errs %+v is wrong", errs)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseConcurrently$
+func TestLoggerCloseConcurrently(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	defaults.HandleError = func(label string, err error) {}
+
+	path := t.TempDir() + "/test.log"
+	logger := NewLogger(WithFile(path), WithBuffer(1024))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				logger.Info("msg", "j", j)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			logger.Close()
+		}()
+	}
+
+	wg.Wait()
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSlogClose$
+func TestLoggerSlogClose(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	var errs []error
+	defaults.HandleError = func(label string, err error) {
+		errs = append(errs, err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithStats())
+
+	slogLogger := logger.Slog()
+	slogLogger.Info("before closing")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	slogLogger.Info("after closing")
+	slogLogger.With("k", "v").WithGroup("group").Info("after closing")
+
+	if got := buffer.String(); bytes.Count([]byte(got), []byte("\n")) != 1 {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("logger should have stats")
+	}
+
+	if stats.Written != 1 || stats.Dropped != 2 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrLoggerClosed) {
+		t.Fatalf("errs %+v is wrong", errs)
+	}
+}
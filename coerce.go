@@ -0,0 +1,161 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+type attrCoercer struct {
+	kinds map[string]slog.Kind
+}
+
+func newAttrCoercer(kinds map[string]slog.Kind) *attrCoercer {
+	coercer := &attrCoercer{
+		kinds: make(map[string]slog.Kind, len(kinds)),
+	}
+
+	for key, kind := range kinds {
+		coercer.kinds[key] = kind
+	}
+
+	return coercer
+}
+
+func coerceToInt64(value slog.Value) (slog.Value, error) {
+	switch value.Kind() {
+	case slog.KindInt64:
+		return value, nil
+	case slog.KindUint64:
+		if value.Uint64() > math.MaxInt64 {
+			return value, fmt.Errorf("logit: uint64 %d overflows int64", value.Uint64())
+		}
+
+		return slog.Int64Value(int64(value.Uint64())), nil
+	case slog.KindFloat64:
+		return slog.Int64Value(int64(value.Float64())), nil
+	case slog.KindDuration:
+		return slog.Int64Value(int64(value.Duration())), nil
+	case slog.KindBool:
+		if value.Bool() {
+			return slog.Int64Value(1), nil
+		}
+
+		return slog.Int64Value(0), nil
+	}
+
+	str := value.String()
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err == nil {
+		return slog.Int64Value(n), nil
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return value, err
+	}
+
+	return slog.Int64Value(int64(f)), nil
+}
+
+func coerceToFloat64(value slog.Value) (slog.Value, error) {
+	switch value.Kind() {
+	case slog.KindFloat64:
+		return value, nil
+	case slog.KindInt64:
+		return slog.Float64Value(float64(value.Int64())), nil
+	case slog.KindUint64:
+		return slog.Float64Value(float64(value.Uint64())), nil
+	case slog.KindDuration:
+		return slog.Float64Value(float64(value.Duration())), nil
+	case slog.KindBool:
+		if value.Bool() {
+			return slog.Float64Value(1), nil
+		}
+
+		return slog.Float64Value(0), nil
+	}
+
+	f, err := strconv.ParseFloat(value.String(), 64)
+	if err != nil {
+		return value, err
+	}
+
+	return slog.Float64Value(f), nil
+}
+
+func coerceToBool(value slog.Value) (slog.Value, error) {
+	switch value.Kind() {
+	case slog.KindBool:
+		return value, nil
+	case slog.KindInt64:
+		return slog.BoolValue(value.Int64() != 0), nil
+	case slog.KindUint64:
+		return slog.BoolValue(value.Uint64() != 0), nil
+	case slog.KindFloat64:
+		return slog.BoolValue(value.Float64() != 0), nil
+	}
+
+	b, err := strconv.ParseBool(value.String())
+	if err != nil {
+		return value, err
+	}
+
+	return slog.BoolValue(b), nil
+}
+
+// coerce coerces value to kind and returns an error if failed.
+// Durations are coerced to numbers in nanoseconds.
+func coerce(value slog.Value, kind slog.Kind) (slog.Value, error) {
+	switch kind {
+	case slog.KindString:
+		return slog.StringValue(value.String()), nil
+	case slog.KindInt64:
+		return coerceToInt64(value)
+	case slog.KindFloat64:
+		return coerceToFloat64(value)
+	case slog.KindBool:
+		return coerceToBool(value)
+	default:
+		return value, fmt.Errorf("logit: coercing to kind %s is unsupported", kind)
+	}
+}
+
+func (ac *attrCoercer) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	kind, ok := ac.kinds[attr.Key]
+	if !ok {
+		return attr
+	}
+
+	value := attr.Value.Resolve()
+	if value.Kind() == kind || value.Kind() == slog.KindGroup {
+		return attr
+	}
+
+	coerced, err := coerce(value, kind)
+	if err != nil {
+		defaults.HandleError("attrCoercer.coerce", err)
+		return attr
+	}
+
+	attr.Value = coerced
+	return attr
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCoerce$
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		value   slog.Value
+		kind    slog.Kind
+		want    slog.Value
+		wantErr bool
+	}{
+		{value: slog.IntValue(200), kind: slog.KindString, want: slog.StringValue("200")},
+		{value: slog.StringValue("200"), kind: slog.KindInt64, want: slog.Int64Value(200)},
+		{value: slog.StringValue("2.5"), kind: slog.KindInt64, want: slog.Int64Value(2)},
+		{value: slog.Uint64Value(7), kind: slog.KindInt64, want: slog.Int64Value(7)},
+		{value: slog.Uint64Value(math.MaxUint64), kind: slog.KindInt64, wantErr: true},
+		{value: slog.Float64Value(3.9), kind: slog.KindInt64, want: slog.Int64Value(3)},
+		{value: slog.BoolValue(true), kind: slog.KindInt64, want: slog.Int64Value(1)},
+		{value: slog.DurationValue(time.Second), kind: slog.KindInt64, want: slog.Int64Value(int64(time.Second))},
+		{value: slog.StringValue("abc"), kind: slog.KindInt64, wantErr: true},
+		{value: slog.IntValue(12), kind: slog.KindFloat64, want: slog.Float64Value(12)},
+		{value: slog.StringValue("1.5"), kind: slog.KindFloat64, want: slog.Float64Value(1.5)},
+		{value: slog.BoolValue(false), kind: slog.KindFloat64, want: slog.Float64Value(0)},
+		{value: slog.StringValue("abc"), kind: slog.KindFloat64, wantErr: true},
+		{value: slog.StringValue("true"), kind: slog.KindBool, want: slog.BoolValue(true)},
+		{value: slog.IntValue(0), kind: slog.KindBool, want: slog.BoolValue(false)},
+		{value: slog.StringValue("abc"), kind: slog.KindBool, wantErr: true},
+		{value: slog.IntValue(1), kind: slog.KindTime, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := coerce(tt.value, tt.kind)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("coerce %v to %s err %v but wantErr %v", tt.value, tt.kind, err, tt.wantErr)
+		}
+
+		if err == nil && !got.Equal(tt.want) {
+			t.Fatalf("coerce %v to %s got %v != want %v", tt.value, tt.kind, got, tt.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithCoercedAttrs$
+func TestWithCoercedAttrs(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	errs := 0
+	defaults.HandleError = func(label string, err error) {
+		errs++
+	}
+
+	kinds := map[string]slog.Kind{"status": slog.KindInt64, "duration_ms": slog.KindFloat64}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithCoercedAttrs(kinds))
+
+	logger.Info("msg", "status", "200", "duration_ms", 12)
+	logger.Info("msg", "status", 404, "duration_ms", "1.5")
+	logger.Info("msg", "status", "unknown")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	wants := []string{`"status":200,"duration_ms":12}`, `"status":404,"duration_ms":1.5}`, `"status":"unknown"}`}
+
+	for i, want := range wants {
+		if !strings.HasSuffix(logs[i], want) {
+			t.Fatalf("logs[%d] %s is wrong", i, logs[i])
+		}
+	}
+
+	if errs != 1 {
+		t.Fatalf("errs %d != 1", errs)
+	}
+}
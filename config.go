@@ -15,12 +15,14 @@
 package logit
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"os"
 	"time"
 
 	"github.com/FishGoddess/logit/handler"
+	"github.com/FishGoddess/logit/writer"
 )
 
 type nilSyncer struct{}
@@ -29,24 +31,182 @@ func (nilSyncer) Sync() error {
 	return nil
 }
 
+// multiSyncer syncs all syncers even if some of them fail, and returns the joined errors of them.
+type multiSyncer []Syncer
+
+func (ms multiSyncer) Sync() error {
+	var errs []error
+	for _, syncer := range ms {
+		if err := syncer.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ms multiSyncer) SyncWithReason(reason writer.FlushReason) error {
+	var errs []error
+	for _, syncer := range ms {
+		if err := syncWithReason(syncer, reason); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 type nilCloser struct{}
 
 func (nilCloser) Close() error {
 	return nil
 }
 
+// multiCloser closes all closers even if some of them fail, and returns the joined errors of them.
+type multiCloser []io.Closer
+
+func (mc multiCloser) Close() error {
+	var errs []error
+	for _, closer := range mc {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 type config struct {
 	level   slog.Level
 	handler string
 
+	// leveler is the dynamic level which takes precedence over level, see WithLeveler.
+	leveler slog.Leveler
+
+	// levelState is the level which can be elevated temporarily, and level is used if it's nil.
+	levelState *levelState
+
+	// stats counts records handled by the handler and it's nil if stats isn't enabled, see WithStats.
+	stats *recordStats
+
+	// csvColumns is the columns of csv handler, see WithCSVHandler.
+	csvColumns []string
+
+	// color is true if levels and keys should be colored, see WithColor.
+	color bool
+
+	// escape is the mode of escaping strings in tape handler, see WithEscapeMode.
+	escape handler.EscapeMode
+
+	// internSize is the max count of strings interned in tape handler, see WithInterning.
+	internSize int
+
+	// consoleColors and consoleIcons customize levels in console handler, see WithConsoleLevelColors and WithConsoleLevelIcons.
+	consoleColors map[slog.Level]handler.Color
+	consoleIcons  map[slog.Level]string
+
+	// consoleLevelWidth, consoleLoggerWidth and consoleMessageWidth are the widths of columns in console handler, see WithConsoleColumns.
+	consoleLevelWidth   int
+	consoleLoggerWidth  int
+	consoleMessageWidth int
+
 	newWriter  func() (io.Writer, error)
 	wrapWriter func(io.Writer) io.Writer
 
+	// errorWriter is the writer of records not lower than warn level, see WithErrorWriter.
+	errorWriter io.Writer
+
+	// levelWriters create writers of levels in ascending order, which replace the writer and the error writer, see WithLevelFiles.
+	levelWriters []levelWriter
+
+	// atomicWriter wraps writer before frameWriter, so each write to writer is atomic.
+	atomicWriter func(io.Writer) io.Writer
+
+	// frameWriter wraps writer before wrapWriter, so logs are framed before buffering.
+	frameWriter func(io.Writer) io.Writer
+
 	replaceAttr func(groups []string, attr slog.Attr) slog.Attr
 
+	// timeFormat is the format of the time of records and will be ignored if empty.
+	timeFormat string
+
+	// utc is true if the time of records should be converted to UTC, see WithUTC.
+	utc bool
+
+	// sourceRoots and sourceSegments trim the file of sources, see WithSourceRoot and WithSourceSegments.
+	sourceRoots    []string
+	sourceSegments int
+
+	// staticAttrs are attached to all records, see WithStaticAttrs.
+	staticAttrs []slog.Attr
+
+	// attrReplacers are added by options like WithEncryptedAttrs.
+	// They will be called in order before replaceAttr.
+	attrReplacers []func(groups []string, attr slog.Attr) slog.Attr
+
 	withSource bool
 	withPID    bool
 
+	// callerSkip is the count of frames skipped additionally when getting the caller of logs.
+	callerSkip int
+
+	// withTrace adds the trace id and span id in context to logs.
+	withTrace bool
+
+	// contextExtractors extract attrs from context to logs, see WithContextExtractor.
+	contextExtractors []ContextExtractor
+
+	// filterRules suppress records matching them, see WithFilters.
+	filterRules []FilterRule
+
+	// maskRules are keys and patterns of sensitive data, see WithMasking.
+	maskRules []string
+
+	// blobStore and blobThreshold offload big values of attrs, see WithBlobOffload.
+	blobStore     BlobStore
+	blobThreshold int
+
+	// hooks are called around handling records, see WithHooks.
+	hooks []Hook
+
+	// sampleInitial, sampleThereafter and sampleInterval sample records in each level, see WithSampling.
+	sampleInitial    int
+	sampleThereafter int
+	sampleInterval   time.Duration
+
+	// rateLimit and rateBurst limit the rate of records, see WithRateLimit.
+	rateLimit int
+	rateBurst int
+
+	// asyncSize and asyncPolicy move handling records to a background worker, see WithAsync.
+	asyncSize   int
+	asyncPolicy DropPolicy
+
+	// asyncTTL is the max time records below warn level can wait in the async queue, see WithAsyncTTL.
+	asyncTTL time.Duration
+
+	// dedupWindow and dedupFile suppress duplicated records, see WithDedup.
+	dedupWindow time.Duration
+	dedupFile   string
+
+	// mergeContext merges loggers in contexts instead of replacing them in NewContext.
+	mergeContext bool
+
+	// maxDepth is the max depth of loggers derived by With and WithGroup before warning.
+	maxDepth int
+
+	// writeStats enables stats of the buffer or batch writer.
+	writeStats bool
+
+	// handleTimeStats measures the time of handling records in stats, see WithHandleTimeStats.
+	handleTimeStats bool
+
+	// strictOrder holds records and handles them in order of logging when syncing, see WithStrictOrder.
+	strictOrder bool
+
+	// dryRun closes the logger after creating it, see WithDryRun.
+	dryRun bool
+
 	syncTimer time.Duration
 }
 
@@ -69,59 +229,358 @@ func newDefaultConfig() *config {
 	return conf
 }
 
+// keepStdOpen hides Sync and Close of stdout and stderr like FanoutWriter, so the logger won't close them.
+// Standard streams are shared by the process, and other writers are returned as they are.
+func keepStdOpen(writer io.Writer) io.Writer {
+	if writer == os.Stdout || writer == os.Stderr {
+		return struct{ io.Writer }{writer}
+	}
+
+	return writer
+}
+
+// setupWriter wraps writer in the modes of config like atomic writes, frames and buffers.
+func (c *config) setupWriter(writer io.Writer) io.Writer {
+	// Nothing should be closed in dry run except resources created by the logger.
+	if c.dryRun {
+		writer = keepStdOpen(writer)
+	}
+
+	if c.atomicWriter != nil {
+		writer = c.atomicWriter(writer)
+	}
+
+	if c.frameWriter != nil {
+		writer = c.frameWriter(writer)
+	}
+
+	if c.wrapWriter != nil {
+		writer = c.wrapWriter(writer)
+	}
+
+	if sw, ok := writer.(interface{ EnableStats() }); ok && c.writeStats {
+		sw.EnableStats()
+	}
+
+	return writer
+}
+
+// newRoutes creates writers of records and returns them as routes in ascending order of levels.
+// The level of the first route is ignored because it also writes records lower than all levels.
+func (c *config) newRoutes() ([]route, error) {
+	if len(c.levelWriters) == 0 {
+		writer, err := c.newWriter()
+		if err != nil {
+			return nil, err
+		}
+
+		routes := []route{{writer: writer}}
+		if c.errorWriter != nil {
+			// The error writer is passed by users like writers in WithWriters, so stdout and stderr are kept open.
+			routes = append(routes, route{level: slog.LevelWarn, writer: keepStdOpen(c.errorWriter)})
+		}
+
+		return routes, nil
+	}
+
+	routes := make([]route, 0, len(c.levelWriters))
+	for _, lw := range c.levelWriters {
+		writer, err := lw.newWriter()
+		if err != nil {
+			// Close writers created so they won't be leaked.
+			for _, route := range routes {
+				if closer, ok := route.writer.(io.Closer); ok {
+					closer.Close()
+				}
+			}
+
+			return nil, err
+		}
+
+		routes = append(routes, route{level: lw.level, writer: writer})
+	}
+
+	return routes, nil
+}
+
 func (c *config) newSyncer(handler slog.Handler, writer io.Writer) Syncer {
+	var syncers multiSyncer
 	if syncer, ok := handler.(Syncer); ok {
-		return syncer
+		syncers = append(syncers, syncer)
 	}
 
 	if syncer, ok := writer.(Syncer); ok {
-		return syncer
+		syncers = append(syncers, syncer)
 	}
 
-	return nilSyncer{}
+	switch len(syncers) {
+	case 0:
+		return nilSyncer{}
+	case 1:
+		return syncers[0]
+	default:
+		return syncers
+	}
 }
 
 func (c *config) newCloser(handler slog.Handler, writer io.Writer) io.Closer {
+	var closers multiCloser
 	if closer, ok := handler.(io.Closer); ok {
-		return closer
+		closers = append(closers, closer)
 	}
 
 	if closer, ok := writer.(io.Closer); ok {
-		return closer
+		closers = append(closers, closer)
 	}
 
-	return nilCloser{}
+	switch len(closers) {
+	case 0:
+		return nilCloser{}
+	case 1:
+		return closers[0]
+	default:
+		return closers
+	}
+}
+
+func (c *config) newReplaceAttr() func(groups []string, attr slog.Attr) slog.Attr {
+	trimSource := len(c.sourceRoots) > 0 || c.sourceSegments > 0
+	if c.timeFormat == "" && !c.utc && !trimSource && len(c.attrReplacers) == 0 {
+		return c.replaceAttr
+	}
+
+	replacers := make([]func(groups []string, attr slog.Attr) slog.Attr, 0, len(c.attrReplacers)+3)
+	if c.timeFormat != "" || c.utc {
+		replacers = append(replacers, newTimeReplacer(c.timeFormat, c.utc))
+	}
+
+	if trimSource {
+		replacers = append(replacers, newSourceTrimmer(c.sourceRoots, c.sourceSegments))
+	}
+
+	replacers = append(replacers, c.attrReplacers...)
+	if c.replaceAttr != nil {
+		replacers = append(replacers, c.replaceAttr)
+	}
+
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		for _, replaceAttr := range replacers {
+			attr = replaceAttr(groups, attr)
+
+			// An attr with empty key will be discarded so there is no need to replace it.
+			if attr.Key == "" {
+				return attr
+			}
+		}
+
+		return attr
+	}
 }
 
 func (c *config) newHandlerOptions() *slog.HandlerOptions {
+	var level slog.Leveler = c.level
+	if c.levelState != nil {
+		level = c.levelState
+	}
+
 	opts := &slog.HandlerOptions{
-		Level:       c.level,
+		Level:       level,
 		AddSource:   c.withSource,
-		ReplaceAttr: c.replaceAttr,
+		ReplaceAttr: c.newReplaceAttr(),
 	}
 
 	return opts
 }
 
+func (c *config) getNewHandler(name string) (handler.NewHandlerFunc, error) {
+	if name == handler.CSV && len(c.csvColumns) > 0 {
+		newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return handler.NewCSVHandler(w, opts, c.csvColumns...)
+		}
+
+		return newHandler, nil
+	}
+
+	if name == handler.Tape && (c.color || c.escape != "" || c.internSize > 0) {
+		var tapeOpts []handler.TapeOption
+		if c.color {
+			tapeOpts = append(tapeOpts, handler.WithTapeColor())
+		}
+
+		if c.escape != "" {
+			tapeOpts = append(tapeOpts, handler.WithTapeEscape(c.escape))
+		}
+
+		if c.internSize > 0 {
+			tapeOpts = append(tapeOpts, handler.WithTapeInterning(c.internSize))
+		}
+
+		newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return handler.NewTapeHandler(w, opts, tapeOpts...)
+		}
+
+		return newHandler, nil
+	}
+
+	if consoleOpts := c.consoleOptions(); name == handler.Console && len(consoleOpts) > 0 {
+		return handler.NewConsoleHandlerFunc(consoleOpts...), nil
+	}
+
+	return handler.Get(name)
+}
+
+// consoleOptions returns the options of console handler set in config.
+func (c *config) consoleOptions() []handler.ConsoleOption {
+	var consoleOpts []handler.ConsoleOption
+	if len(c.consoleColors) > 0 {
+		consoleOpts = append(consoleOpts, handler.WithConsoleLevelColors(c.consoleColors))
+	}
+
+	if len(c.consoleIcons) > 0 {
+		consoleOpts = append(consoleOpts, handler.WithConsoleLevelIcons(c.consoleIcons))
+	}
+
+	if c.consoleLevelWidth > 0 {
+		consoleOpts = append(consoleOpts, handler.WithConsoleLevelWidth(c.consoleLevelWidth))
+	}
+
+	if c.consoleLoggerWidth > 0 {
+		consoleOpts = append(consoleOpts, handler.WithConsoleLoggerWidth(c.consoleLoggerWidth))
+	}
+
+	if c.consoleMessageWidth > 0 {
+		consoleOpts = append(consoleOpts, handler.WithConsoleMessageWidth(c.consoleMessageWidth))
+	}
+
+	return consoleOpts
+}
+
 func (c *config) newHandler() (slog.Handler, Syncer, io.Closer, error) {
-	newHandler, err := handler.Get(c.handler)
+	newHandler, err := c.getNewHandler(c.handler)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	writer, err := c.newWriter()
+	var masker *masker
+	if len(c.maskRules) > 0 {
+		if masker, err = newMasker(c.maskRules); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var filter *filter
+	if len(c.filterRules) > 0 {
+		if filter, err = newFilter(c.filterRules); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	routes, err := c.newRoutes()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	if c.wrapWriter != nil {
-		writer = c.wrapWriter(writer)
+	// The auto handler is picked by the writer before wrapping, so a buffered terminal is still a terminal.
+	if c.handler == handler.Auto {
+		if newHandler, err = c.getNewHandler(handler.AutoName(routes[0].writer)); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
+	// All writers are wrapped in the same way, so records in all levels are written in the same mode.
 	opts := c.newHandlerOptions()
-	handler := newHandler(writer, opts)
-	syncer := c.newSyncer(handler, writer)
-	closer := c.newCloser(handler, writer)
+	levels := make([]slog.Level, 0, len(routes))
+	handlers := make([]slog.Handler, 0, len(routes))
+	syncers := make(multiSyncer, 0, len(routes))
+	closers := make(multiCloser, 0, len(routes))
+
+	for _, route := range routes {
+		writer := c.setupWriter(route.writer)
+		handler := newHandler(writer, opts)
+
+		levels = append(levels, route.level)
+		handlers = append(handlers, handler)
+		syncers = append(syncers, c.newSyncer(handler, writer))
+		closers = append(closers, c.newCloser(handler, writer))
+	}
+
+	handler, syncer, closer := handlers[0], syncers[0], closers[0]
+	if len(routes) > 1 {
+		handler = newRouteHandler(levels, handlers)
+		syncer = syncers
+		closer = closers
+	}
+
+	if c.stats != nil {
+		handler = newStatsHandler(handler, c.stats, c.handleTimeStats)
+	}
+
+	// Big values are offloaded after hooks, so hooks still get the values and the values offloaded are masked.
+	if c.blobStore != nil {
+		handler = newBlobHandler(handler, newBlobOffloader(c.blobStore, c.blobThreshold))
+	}
+
+	// Handlers wrapped later handle records earlier, so hooks get records masked but not suppressed.
+	if len(c.hooks) > 0 {
+		handler = newHookHandler(handler, c.hooks)
+	}
+
+	if masker != nil {
+		handler = newMaskHandler(handler, masker)
+	}
+
+	// Attrs from context are added before masking, so values extracted from context are masked too.
+	if c.withTrace {
+		handler = newTraceHandler(handler)
+	}
+
+	if len(c.contextExtractors) > 0 {
+		handler = newContextHandler(handler, c.contextExtractors)
+	}
+
+	// Records are suppressed, sampled and then limited, so the limit applies to records which will be handled.
+	// Summary records of the limiter use the handler before wrapping, so they won't be dropped.
+	if c.rateLimit > 0 {
+		limiter := newRateLimiter(handler, c.rateLimit, c.rateBurst)
+		handler = newLimitHandler(handler, limiter)
+		closer = multiCloser{limiter, closer}
+	}
+
+	if c.sampleInterval > 0 {
+		handler = newSampleHandler(handler, newSampler(c.sampleInitial, c.sampleThereafter, c.sampleInterval))
+	}
+
+	// Summary records of the deduper use the handler before wrapping, so they won't be suppressed again.
+	if c.dedupWindow > 0 {
+		deduper := newDeduper(handler, c.dedupWindow, c.dedupFile)
+		handler = newDedupHandler(handler, deduper, 0)
+		closer = multiCloser{deduper, closer}
+	}
+
+	// The queue should be drained before syncing and closing others, so records in it won't be lost.
+	if c.asyncSize > 0 {
+		queue := newAsyncQueue(c.asyncSize, c.asyncPolicy, c.asyncTTL, c.stats)
+		handler = newAsyncHandler(handler, queue)
+		syncer = multiSyncer{queue, syncer}
+		closer = multiCloser{queue, closer}
+	}
+
+	// Records are filtered before going into the queue, so muted records won't take places in the queue.
+	if filter != nil {
+		handler = newFilterHandler(handler, filter, "", nil)
+	}
+
+	// Records are stamped before going into the queue, so the order of logging is kept in output.
+	if c.strictOrder {
+		orderer := newOrderer()
+		handler = newOrderedHandler(handler, orderer)
+		syncer = multiSyncer{orderer, syncer}
+		closer = multiCloser{orderer, closer}
+	}
+
+	if len(c.staticAttrs) > 0 {
+		handler = handler.WithAttrs(c.staticAttrs)
+	}
 
 	return handler, syncer, closer, nil
 }
@@ -15,11 +15,15 @@
 package logit
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/FishGoddess/logit/handler"
 )
@@ -56,6 +60,58 @@ func TestConfigNewHandlerOptions(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewReplaceAttr$
+func TestConfigNewReplaceAttr(t *testing.T) {
+	conf := &config{}
+	if replaceAttr := conf.newReplaceAttr(); replaceAttr != nil {
+		t.Fatal("replaceAttr should be nil")
+	}
+
+	conf = &config{timeFormat: TimeFormatUnix}
+	if attr := conf.newReplaceAttr()(nil, slog.Time(slog.TimeKey, time.Unix(1, 0))); attr.Value.Int64() != 1 {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	var called []string
+	newReplaceAttr := func(name string, key string) func(groups []string, attr slog.Attr) slog.Attr {
+		return func(groups []string, attr slog.Attr) slog.Attr {
+			called = append(called, name)
+
+			if attr.Key == key {
+				return slog.Attr{}
+			}
+
+			attr.Value = slog.StringValue(attr.Value.String() + name)
+			return attr
+		}
+	}
+
+	conf = &config{
+		replaceAttr:   newReplaceAttr("user", ""),
+		attrReplacers: []func(groups []string, attr slog.Attr) slog.Attr{newReplaceAttr("a", "discard"), newReplaceAttr("b", "")},
+	}
+
+	replaceAttr := conf.newReplaceAttr()
+
+	attr := replaceAttr(nil, slog.String("k", "v"))
+	if attr.Value.String() != "vabuser" {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	if strings.Join(called, ",") != "a,b,user" {
+		t.Fatalf("called %+v is wrong", called)
+	}
+
+	called = called[:0]
+	if attr = replaceAttr(nil, slog.String("discard", "v")); attr.Key != "" {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	if strings.Join(called, ",") != "a" {
+		t.Fatalf("called %+v is wrong", called)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewHandler$
 func TestConfigNewHandler(t *testing.T) {
 	handlerName := t.Name()
@@ -112,3 +168,126 @@ func TestConfigNewHandler(t *testing.T) {
 		t.Fatalf("tcHandler.opts.ReplaceAttr %p != conf.replaceAttr %p", tcHandler.opts.ReplaceAttr, conf.replaceAttr)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewAutoHandler$
+func TestConfigNewAutoHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	logger := NewLogger(WithFile(path), WithAutoHandler(), WithBuffer(4096))
+	logger.Info("msg", "k", "v")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(data); !strings.HasSuffix(got, "INFO ¦ msg ¦ k=v\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	// The null device is a char device like terminals, so the console handler will be picked even if it's buffered.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer devNull.Close()
+
+	conf := newDefaultConfig()
+	for _, opt := range []Option{WithWriter(devNull), WithAutoHandler(), WithBuffer(4096)} {
+		opt.applyTo(conf)
+	}
+
+	handler, _, _, err := conf.newHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fmt.Sprintf("%T", handler); got != "*handler.consoleHandler" {
+		t.Fatalf("handler type %s is wrong", got)
+	}
+}
+
+type testConfigSyncer struct {
+	slog.Handler
+	io.Writer
+
+	err    error
+	synced int
+}
+
+func (tcs *testConfigSyncer) Sync() error {
+	tcs.synced++
+	return tcs.err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewSyncer$
+func TestConfigNewSyncer(t *testing.T) {
+	conf := &config{}
+
+	if _, ok := conf.newSyncer(nil, nil).(nilSyncer); !ok {
+		t.Fatal("syncer should be nilSyncer")
+	}
+
+	writerSyncer := &testConfigSyncer{}
+	if syncer := conf.newSyncer(nil, writerSyncer); syncer != writerSyncer {
+		t.Fatalf("syncer %+v is wrong", syncer)
+	}
+
+	errFailed := errors.New("failed")
+	handlerSyncer := &testConfigSyncer{err: errFailed}
+
+	// The writer should be synced even if syncing the handler failed.
+	syncer := conf.newSyncer(handlerSyncer, writerSyncer)
+	if err := syncer.Sync(); !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if handlerSyncer.synced != 1 || writerSyncer.synced != 1 {
+		t.Fatalf("handlerSyncer.synced %d or writerSyncer.synced %d is wrong", handlerSyncer.synced, writerSyncer.synced)
+	}
+}
+
+type testConfigCloser struct {
+	slog.Handler
+	io.Writer
+
+	err    error
+	closed int
+}
+
+func (tcc *testConfigCloser) Close() error {
+	tcc.closed++
+	return tcc.err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewCloser$
+func TestConfigNewCloser(t *testing.T) {
+	conf := &config{}
+
+	if _, ok := conf.newCloser(nil, nil).(nilCloser); !ok {
+		t.Fatal("closer should be nilCloser")
+	}
+
+	writerCloser := &testConfigCloser{}
+	if closer := conf.newCloser(nil, writerCloser); closer != writerCloser {
+		t.Fatalf("closer %+v is wrong", closer)
+	}
+
+	errFailed := errors.New("failed")
+	handlerCloser := &testConfigCloser{err: errFailed}
+
+	// The writer should be closed even if closing the handler failed.
+	closer := conf.newCloser(handlerCloser, writerCloser)
+	if err := closer.Close(); !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if handlerCloser.closed != 1 || writerCloser.closed != 1 {
+		t.Fatalf("handlerCloser.closed %d or writerCloser.closed %d is wrong", handlerCloser.closed, writerCloser.closed)
+	}
+}
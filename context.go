@@ -16,12 +16,147 @@ package logit
 
 import (
 	"context"
+	"log/slog"
 )
 
 type contextKey struct{}
 
+// ContextKey is the key of values in context which can be extracted by ExtractContextKeys.
+// Use it as the key of context.WithValue, like context.WithValue(ctx, logit.ContextKey("request_id"), requestID).
+type ContextKey string
+
+// ContextExtractor extracts attrs from ctx, which will be appended to records logged with ctx.
+// It should return nil if ctx doesn't have the values, and it's called for every record so it should be fast.
+// See WithContextExtractor.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// ExtractContextKeys returns an extractor extracting values stored with keys in context, see ContextKey.
+// Values are appended as attrs whose keys are keys in order, and keys not found in context are skipped.
+func ExtractContextKeys(keys ...string) ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		var attrs []slog.Attr
+		for _, key := range keys {
+			if value := ctx.Value(ContextKey(key)); value != nil {
+				attrs = append(attrs, slog.Any(key, value))
+			}
+		}
+
+		return attrs
+	}
+}
+
+// contextHandler appends attrs extracted from context to records.
+type contextHandler struct {
+	slog.Handler
+
+	extractors []ContextExtractor
+}
+
+func newContextHandler(handler slog.Handler, extractors []ContextExtractor) slog.Handler {
+	return contextHandler{Handler: handler, extractors: extractors}
+}
+
+func (ch contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		for _, extractor := range ch.extractors {
+			record.AddAttrs(extractor(ctx)...)
+		}
+	}
+
+	return ch.Handler.Handle(ctx, record)
+}
+
+func (ch contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newContextHandler(ch.Handler.WithAttrs(attrs), ch.extractors)
+}
+
+func (ch contextHandler) WithGroup(name string) slog.Handler {
+	return newContextHandler(ch.Handler.WithGroup(name), ch.extractors)
+}
+
+// origin is the handler created by NewLogger.
+// It's compared by pointer so loggers from the same NewLogger call can be recognized.
+type origin struct {
+	handler slog.Handler
+}
+
+// derivation is a With or WithGroup call deriving a logger.
+type derivation struct {
+	attrs []slog.Attr
+	group string
+}
+
+func (d *derivation) applyTo(handler slog.Handler) slog.Handler {
+	if d.group != "" {
+		return handler.WithGroup(d.group)
+	}
+
+	return handler.WithAttrs(d.attrs)
+}
+
+// recordDerivation records d if logger is created with WithContextMerge.
+// The derivations are copied so loggers derived from the same logger won't share them.
+func (l *Logger) recordDerivation(d *derivation) {
+	if l.origin == nil {
+		return
+	}
+
+	derivations := make([]*derivation, 0, len(l.derivations)+1)
+	derivations = append(derivations, l.derivations...)
+	l.derivations = append(derivations, d)
+}
+
+// sharedDerivations returns the count of derivations l and parent share from the beginning.
+// Loggers derived from the same logger share its derivations, like base.With(a).With(b) and base.With(a).With(c).
+func (l *Logger) sharedDerivations(parent *Logger) int {
+	if l.origin != parent.origin {
+		return 0
+	}
+
+	n := min(len(l.derivations), len(parent.derivations))
+	for i := 0; i < n; i++ {
+		if l.derivations[i] != parent.derivations[i] {
+			return i
+		}
+	}
+
+	return n
+}
+
+// mergeFrom returns a new logger with the derivations of parent applied before the derivations of l.
+// Derivations shared by l and parent are applied once, so loggers derived from parent won't be merged again.
+func (l *Logger) mergeFrom(parent *Logger) *Logger {
+	shared := l.sharedDerivations(parent)
+	if parent.origin == nil || shared >= len(parent.derivations) {
+		return l
+	}
+
+	derivations := make([]*derivation, 0, len(parent.derivations)+len(l.derivations)-shared)
+	derivations = append(derivations, parent.derivations...)
+	derivations = append(derivations, l.derivations[shared:]...)
+
+	handler := l.origin.handler
+	for _, d := range derivations {
+		handler = d.applyTo(handler)
+	}
+
+	newLogger := l.clone()
+	newLogger.handler = handler
+	newLogger.derivations = derivations
+
+	return newLogger
+}
+
 // NewContext wraps context with logger and returns a new context.
+// If logger is created with WithContextMerge and context already has a logger, they will be merged.
+// See WithContextMerge.
 func NewContext(ctx context.Context, logger *Logger) context.Context {
+	if logger.origin != nil {
+		if parent, ok := ctx.Value(contextKey{}).(*Logger); ok {
+			logger = logger.mergeFrom(parent)
+		}
+	}
+
 	return context.WithValue(ctx, contextKey{}, logger)
 }
 
@@ -15,7 +15,10 @@
 package logit
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -39,6 +42,77 @@ func TestNewContext(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewContextMerge$
+func TestNewContextMerge(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithContextMerge())
+
+	ctx := NewContext(context.Background(), logger)
+	if FromContext(ctx) != logger {
+		t.Fatal("logger in ctx is wrong")
+	}
+
+	// The first middleware adds request_id.
+	ctx = NewContext(ctx, logger.With("request_id", "abc"))
+
+	// The second middleware adds user_id to the root logger instead of the logger in ctx.
+	ctx = NewContext(ctx, logger.WithGroup("user").With("id", 123))
+	FromContext(ctx).Info("merged")
+
+	want := `"msg":"merged","request_id":"abc","user":{"id":123}}`
+	if got := strings.TrimSpace(buffer.String()); !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't have suffix %s", got, want)
+	}
+
+	// Loggers derived from the logger in ctx shouldn't be merged again.
+	buffer.Reset()
+
+	parent := FromContext(ctx)
+	child := parent.With("key", "value")
+
+	ctx = NewContext(ctx, child)
+	if FromContext(ctx) != child {
+		t.Fatal("child in ctx is wrong")
+	}
+
+	FromContext(ctx).Info("derived")
+
+	want = `"msg":"derived","request_id":"abc","user":{"id":123,"key":"value"}}`
+	if got := strings.TrimSpace(buffer.String()); !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't have suffix %s", got, want)
+	}
+
+	// Siblings derived from the same logger share its derivations, which should be applied once.
+	buffer.Reset()
+
+	base := logger.With("service", "api")
+	ctx = NewContext(context.Background(), base.With("request_id", "abc"))
+	ctx = NewContext(ctx, base.With("user_id", 123))
+	FromContext(ctx).Info("siblings")
+
+	want = `"msg":"siblings","service":"api","request_id":"abc","user_id":123}`
+	if got := strings.TrimSpace(buffer.String()); !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't have suffix %s", got, want)
+	}
+
+	// Loggers without WithContextMerge should replace the logger in ctx.
+	buffer.Reset()
+
+	replaced := NewLogger(WithWriter(buffer), WithJsonHandler()).With("key", "value")
+	ctx = NewContext(ctx, replaced)
+
+	if FromContext(ctx) != replaced {
+		t.Fatal("replaced in ctx is wrong")
+	}
+
+	FromContext(ctx).Info("replaced")
+
+	want = `"msg":"replaced","key":"value"}`
+	if got := strings.TrimSpace(buffer.String()); !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't have suffix %s", got, want)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestFromContext$
 func TestFromContext(t *testing.T) {
 	ctx := context.Background()
@@ -48,6 +122,20 @@ func TestFromContext(t *testing.T) {
 		t.Fatal("logger == nil")
 	}
 
+	if logger != Default() {
+		t.Fatalf("logger %+v != Default() %+v", logger, Default())
+	}
+
+	oldDefault := Default()
+	defer SetDefault(oldDefault)
+
+	defaultLogger := NewLogger()
+	SetDefault(defaultLogger)
+
+	if logger = FromContext(ctx); logger != defaultLogger {
+		t.Fatalf("logger %+v != defaultLogger %+v", logger, defaultLogger)
+	}
+
 	logger = NewLogger()
 	contextLogger := FromContext(context.WithValue(ctx, contextKey{}, logger))
 
@@ -55,3 +143,45 @@ func TestFromContext(t *testing.T) {
 		t.Fatalf("contextLogger %+v != logger %+v", contextLogger, logger)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestExtractContextKeys$
+func TestExtractContextKeys(t *testing.T) {
+	extractor := ExtractContextKeys("request_id", "tenant_id", "user_id")
+
+	if attrs := extractor(context.Background()); len(attrs) != 0 {
+		t.Fatalf("attrs %+v should be empty", attrs)
+	}
+
+	ctx := context.WithValue(context.Background(), ContextKey("request_id"), "r1")
+	ctx = context.WithValue(ctx, ContextKey("user_id"), 100)
+	ctx = context.WithValue(ctx, "tenant_id", "t1")
+
+	attrs := extractor(ctx)
+	if len(attrs) != 2 || attrs[0].String() != "request_id=r1" || attrs[1].String() != "user_id=100" {
+		t.Fatalf("attrs %+v is wrong", attrs)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestContextHandler$
+func TestContextHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	tenant := func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.String("tenant_id", "t1")}
+	}
+
+	logger := NewLogger(WithWriter(buffer), WithTraceLevel(), WithContextExtractor(ExtractContextKeys("request_id"), nil, tenant))
+	ctx := context.WithValue(context.Background(), ContextKey("request_id"), "r1")
+
+	logger.WithGroup("group").TraceContext(ctx, "msg", "key", "value")
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ msg ¦ group.key=value ¦ group.request_id=r1 ¦ group.tenant_id=t1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	logger.Slog().InfoContext(context.Background(), "msg")
+
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ msg ¦ tenant_id=t1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build logitdebug
+
+package debug
+
+import (
+	"github.com/FishGoddess/logit"
+)
+
+// Enabled reports whether the package is built with the logitdebug tag.
+const Enabled = true
+
+// Log logs a log with msg and args in debug level.
+func Log(logger *logit.Logger, msg string, args ...any) {
+	// Skip this function so the source of logs is the caller.
+	logger.WithCallerSkip(1).Debug(msg, args...)
+}
+
+// Trace logs a log with msg and args in trace level.
+func Trace(logger *logit.Logger, msg string, args ...any) {
+	logger.WithCallerSkip(1).Trace(msg, args...)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLog$
+// go test -v -cover -count=1 -test.cpu=1 -tags=logitdebug -run=^TestLog$
+func TestLog(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithTraceLevel(), logit.WithSource())
+
+	Log(logger, "debug", "key", 123)
+	Trace(logger, "trace", "key", 456)
+
+	got := buffer.String()
+	if !Enabled {
+		if got != "" {
+			t.Fatalf("got %s should be empty", got)
+		}
+
+		return
+	}
+
+	if !strings.Contains(got, "¦ DEBUG ¦ debug ¦") || !strings.Contains(got, "¦ TRACE ¦ trace ¦") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if strings.Count(got, "debug_test.go") != 2 {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !logitdebug
+
+// Package debug provides logging functions which are compiled to no-ops without the logitdebug tag.
+// It's useful for carrying diagnostic logs in hot paths without any cost in release builds.
+//
+//	debug.Log(logger, "cache missed", "key", key)
+//
+// Arguments are still evaluated by the caller even if the function is a no-op,
+// so check Enabled first if building arguments is expensive, and the whole block will be eliminated by the compiler:
+//
+//	if debug.Enabled {
+//		debug.Log(logger, "request dumped", "request", dump(request))
+//	}
+//
+// Build with the logitdebug tag to enable them:
+//
+//	go build -tags logitdebug
+package debug
+
+import (
+	"github.com/FishGoddess/logit"
+)
+
+// Enabled reports whether the package is built with the logitdebug tag.
+const Enabled = false
+
+// Log logs a log with msg and args in debug level.
+// It's a no-op without the logitdebug tag.
+func Log(logger *logit.Logger, msg string, args ...any) {}
+
+// Trace logs a log with msg and args in trace level.
+// It's a no-op without the logitdebug tag.
+func Trace(logger *logit.Logger, msg string, args ...any) {}
@@ -0,0 +1,372 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// keyRepeated is the key of the attr carrying the count of records suppressed since the last same record.
+	keyRepeated = "repeated"
+
+	// dedupSaveInterval is the min interval of removing expired entries and saving the state.
+	// The first record of a deduper is saved immediately so a crashed process still leaves it to the next one.
+	dedupSaveInterval = time.Second
+)
+
+type dedupEntry struct {
+	first      time.Time
+	suppressed uint64
+	level      slog.Level
+	message    string
+
+	// handler and record are used to log the summary record if no same records come after window.
+	// They're the handler and the record suppressed first, and handler is nil if no records are suppressed in this process.
+	handler slog.Handler
+	record  slog.Record
+}
+
+// deduper suppresses records having the same level, message and attrs in a window.
+// Its state can be saved to a file, so the records are still suppressed after restarting.
+type deduper struct {
+	// handler is the handler logging summary records of entries loaded from the state, which has no attrs and groups added by loggers.
+	handler slog.Handler
+
+	window  time.Duration
+	path    string
+	entries map[uint64]*dedupEntry
+	savedAt time.Time
+	dirty   bool
+	lock    sync.Mutex
+
+	// version increases for every snapshot, so a snapshot won't overwrite a newer one saved before it.
+	version      uint64
+	savedVersion uint64
+	saveLock     sync.Mutex
+}
+
+// dedupSnapshot is the state of deduper taken with lock held, so it can be saved without lock held.
+type dedupSnapshot struct {
+	version uint64
+	data    []byte
+}
+
+func newDeduper(handler slog.Handler, window time.Duration, path string) *deduper {
+	dd := &deduper{
+		handler: handler,
+		window:  window,
+		path:    path,
+		entries: make(map[uint64]*dedupEntry, 16),
+	}
+
+	if path == "" {
+		return dd
+	}
+
+	// A broken state only makes records emitted again, so it shouldn't stop the logger from being created.
+	if err := dd.load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		defaults.HandleError("deduper.load", err)
+	}
+
+	return dd
+}
+
+// hashAttrs hashes attrs with seed, which is the hash of attrs and groups added before.
+// The pid is ignored so records from a restarted process are still the same as before.
+func hashAttrs(seed uint64, attrs ...slog.Attr) uint64 {
+	hash := fnv.New64a()
+	binary.Write(hash, binary.LittleEndian, seed)
+
+	for _, attr := range attrs {
+		if attr.Key == keyPID {
+			continue
+		}
+
+		hash.Write([]byte(attr.Key))
+		hash.Write([]byte{'='})
+		hash.Write([]byte(attr.Value.Resolve().String()))
+		hash.Write([]byte{0})
+	}
+
+	return hash.Sum64()
+}
+
+// recordKey returns the key of record in level, message and attrs, and attrs added by WithAttrs are in seed.
+func recordKey(seed uint64, record slog.Record) uint64 {
+	attrs := make([]slog.Attr, 0, record.NumAttrs()+1)
+	attrs = append(attrs, slog.String(record.Level.String(), record.Message))
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	return hashAttrs(seed, attrs...)
+}
+
+// check reports whether the record having key should be emitted and the count of records suppressed before it.
+// It also returns expired entries having suppressed records, whose summary records should be logged.
+func (dd *deduper) check(key uint64, handler slog.Handler, record slog.Record) (bool, uint64, []dedupEntry) {
+	now := defaults.CurrentTime()
+
+	dd.lock.Lock()
+
+	var suppressed uint64
+
+	entry, ok := dd.entries[key]
+	emit := !ok || now.Sub(entry.first) >= dd.window
+
+	if emit {
+		if ok {
+			suppressed = entry.suppressed
+		}
+
+		dd.entries[key] = &dedupEntry{first: now, level: record.Level, message: record.Message}
+	} else {
+		entry.suppressed++
+
+		// The record is kept for the summary record, so clone it in case the caller modifies it.
+		if entry.handler == nil {
+			entry.handler = handler
+			entry.record = record.Clone()
+		}
+	}
+
+	dd.dirty = true
+
+	var expired []dedupEntry
+	var snapshot *dedupSnapshot
+	if now.Sub(dd.savedAt) >= dedupSaveInterval {
+		expired, snapshot = dd.prune(now)
+	}
+
+	dd.lock.Unlock()
+
+	// Writing the file may be slow, so it's done without lock held and records won't wait for it.
+	dd.save(snapshot)
+	return emit, suppressed, expired
+}
+
+// summarize logs a summary record carrying the suppressed count for each entry.
+// Entries loaded from the state only have the level and message, so their records are logged by the handler of deduper.
+func (dd *deduper) summarize(ctx context.Context, entries []dedupEntry) error {
+	now := defaults.CurrentTime()
+
+	var errs []error
+	for _, entry := range entries {
+		handler := entry.handler
+		record := slog.NewRecord(now, entry.level, entry.message, 0)
+
+		if handler != nil {
+			record = entry.record.Clone()
+			record.Time = now
+		} else {
+			handler = dd.handler
+		}
+
+		record.AddAttrs(slog.Uint64(keyRepeated, entry.suppressed))
+
+		if err := handler.Handle(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (dd *deduper) load() error {
+	data, err := os.ReadFile(dd.path)
+	if err != nil {
+		return err
+	}
+
+	now := defaults.CurrentTime()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		var key uint64
+		var first int64
+		var suppressed uint64
+		var level int
+		var message string
+
+		if _, err = fmt.Sscanf(scanner.Text(), "%x %d %d %d %q", &key, &first, &suppressed, &level, &message); err != nil {
+			return fmt.Errorf("logit: parse dedup state %s failed: %w", dd.path, err)
+		}
+
+		entry := &dedupEntry{first: time.Unix(0, first), suppressed: suppressed, level: slog.Level(level), message: message}
+		if now.Sub(entry.first) < dd.window || entry.suppressed > 0 {
+			dd.entries[key] = entry
+		}
+	}
+
+	return scanner.Err()
+}
+
+// prune removes expired entries and takes a snapshot of the state if it should be saved.
+// Expired entries having suppressed records are returned, so their counts can be logged in summary records.
+// It should be called with lock held, and the snapshot should be saved by save without lock held.
+func (dd *deduper) prune(now time.Time) ([]dedupEntry, *dedupSnapshot) {
+	var expired []dedupEntry
+	for key, entry := range dd.entries {
+		if now.Sub(entry.first) < dd.window {
+			continue
+		}
+
+		if entry.suppressed > 0 {
+			expired = append(expired, *entry)
+		}
+
+		delete(dd.entries, key)
+		dd.dirty = true
+	}
+
+	sortDedupEntries(expired)
+	dd.savedAt = now
+
+	if dd.path == "" || !dd.dirty {
+		return expired, nil
+	}
+
+	var buffer bytes.Buffer
+	for key, entry := range dd.entries {
+		fmt.Fprintf(&buffer, "%016x %d %d %d %q\n", key, entry.first.UnixNano(), entry.suppressed, entry.level, entry.message)
+	}
+
+	dd.version++
+	dd.dirty = false
+
+	return expired, &dedupSnapshot{version: dd.version, data: buffer.Bytes()}
+}
+
+// save saves snapshot to the file, and a nil snapshot or one older than the saved one will be skipped.
+// The state is written to a temporary file and renamed to the file, so it won't be broken by crashing.
+// It should be called without lock held, and the state will be saved next time if failed.
+func (dd *deduper) save(snapshot *dedupSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	dd.saveLock.Lock()
+	defer dd.saveLock.Unlock()
+
+	if snapshot.version <= dd.savedVersion {
+		return
+	}
+
+	err := os.WriteFile(dd.path+".tmp", snapshot.data, 0644)
+	if err == nil {
+		err = os.Rename(dd.path+".tmp", dd.path)
+	}
+
+	if err != nil {
+		defaults.HandleError("deduper.save", err)
+
+		dd.lock.Lock()
+		dd.dirty = true
+		dd.lock.Unlock()
+
+		return
+	}
+
+	dd.savedVersion = snapshot.version
+}
+
+// sortDedupEntries sorts entries by their first time, so summary records are logged in order.
+func sortDedupEntries(entries []dedupEntry) {
+	slices.SortFunc(entries, func(a, b dedupEntry) int {
+		return a.first.Compare(b.first)
+	})
+}
+
+// Close logs summary records of all suppressed counts and saves the state to the file.
+// Entries in window are kept with their counts reset, so the records are still suppressed after restarting.
+func (dd *deduper) Close() error {
+	dd.lock.Lock()
+
+	var pending []dedupEntry
+	for _, entry := range dd.entries {
+		if entry.suppressed > 0 {
+			pending = append(pending, *entry)
+			entry.suppressed = 0
+			dd.dirty = true
+		}
+	}
+
+	sortDedupEntries(pending)
+	_, snapshot := dd.prune(defaults.CurrentTime())
+	dd.lock.Unlock()
+
+	dd.save(snapshot)
+	return dd.summarize(context.Background(), pending)
+}
+
+// dedupHandler drops records suppressed by deduper and adds the suppressed count to the record emitted after them.
+type dedupHandler struct {
+	slog.Handler
+
+	deduper *deduper
+
+	// seed is the hash of attrs and groups added by WithAttrs and WithGroup.
+	seed uint64
+}
+
+func newDedupHandler(handler slog.Handler, deduper *deduper, seed uint64) slog.Handler {
+	return dedupHandler{Handler: handler, deduper: deduper, seed: seed}
+}
+
+func (dh dedupHandler) Handle(ctx context.Context, record slog.Record) error {
+	emit, suppressed, expired := dh.deduper.check(recordKey(dh.seed, record), dh.Handler, record)
+	if len(expired) > 0 {
+		if err := dh.deduper.summarize(ctx, expired); err != nil {
+			defaults.HandleError("deduper.summarize", err)
+		}
+	}
+
+	if !emit {
+		return nil
+	}
+
+	if suppressed > 0 {
+		record = record.Clone()
+		record.AddAttrs(slog.Uint64(keyRepeated, suppressed))
+	}
+
+	return dh.Handler.Handle(ctx, record)
+}
+
+func (dh dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newDedupHandler(dh.Handler.WithAttrs(attrs), dh.deduper, hashAttrs(dh.seed, attrs...))
+}
+
+func (dh dedupHandler) WithGroup(name string) slog.Handler {
+	seed := hashAttrs(dh.seed, slog.Group(name))
+	return newDedupHandler(dh.Handler.WithGroup(name), dh.deduper, seed)
+}
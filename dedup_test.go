@@ -0,0 +1,357 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+func testRecord(level slog.Level, msg string, attrs ...slog.Attr) slog.Record {
+	record := slog.NewRecord(time.Time{}, level, msg, 0)
+	record.AddAttrs(attrs...)
+
+	return record
+}
+
+func testRecordKey(level slog.Level, msg string, attrs ...slog.Attr) uint64 {
+	return recordKey(0, testRecord(level, msg, attrs...))
+}
+
+func testDedupCheck(dd *deduper, level slog.Level, msg string, attrs ...slog.Attr) (bool, uint64) {
+	record := testRecord(level, msg, attrs...)
+	emit, suppressed, _ := dd.check(recordKey(0, record), nil, record)
+
+	return emit, suppressed
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRecordKey$
+func TestRecordKey(t *testing.T) {
+	key := testRecordKey(slog.LevelError, "msg", slog.Int("status", 500))
+
+	if testRecordKey(slog.LevelError, "msg", slog.Int("status", 500), slog.Int(keyPID, 123)) != key {
+		t.Fatal("pid should be ignored")
+	}
+
+	if testRecordKey(slog.LevelError, "msg", slog.Int("status", 502)) == key {
+		t.Fatal("records with different attrs should have different keys")
+	}
+
+	if testRecordKey(slog.LevelWarn, "msg", slog.Int("status", 500)) == key {
+		t.Fatal("records in different levels should have different keys")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDeduperCheck$
+func TestDeduperCheck(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	dd := newDeduper(nil, time.Minute, "")
+
+	emit, suppressed := testDedupCheck(dd, slog.LevelError, "startup failed")
+	if !emit || suppressed != 0 {
+		t.Fatalf("emit %+v or suppressed %d is wrong", emit, suppressed)
+	}
+
+	for i := 0; i < 3; i++ {
+		if emit, _ = testDedupCheck(dd, slog.LevelError, "startup failed"); emit {
+			t.Fatal("duplicated record should be suppressed")
+		}
+	}
+
+	if emit, _ = testDedupCheck(dd, slog.LevelWarn, "startup failed"); !emit {
+		t.Fatal("record in another level should be emitted")
+	}
+
+	now = now.Add(time.Minute)
+
+	emit, suppressed = testDedupCheck(dd, slog.LevelError, "startup failed")
+	if !emit || suppressed != 3 {
+		t.Fatalf("emit %+v or suppressed %d is wrong", emit, suppressed)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDeduperState$
+func TestDeduperState(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	path := filepath.Join(t.TempDir(), "dedup.state")
+
+	// Every process emits the startup error once and crashes, so each one creates a new deduper.
+	for i := 0; i < 5; i++ {
+		dd := newDeduper(nil, time.Minute, path)
+		emit, _ := testDedupCheck(dd, slog.LevelError, "startup failed")
+
+		if emit != (i == 0) {
+			t.Fatalf("emit %+v of process %d is wrong", emit, i)
+		}
+
+		now = now.Add(time.Second)
+	}
+
+	now = now.Add(time.Minute)
+
+	dd := newDeduper(nil, time.Minute, path)
+	emit, suppressed := testDedupCheck(dd, slog.LevelError, "startup failed")
+	if !emit || suppressed != 4 {
+		t.Fatalf("emit %+v or suppressed %d is wrong", emit, suppressed)
+	}
+
+	dd = newDeduper(nil, time.Minute, filepath.Join(t.TempDir(), "not_exist.state"))
+	if len(dd.entries) != 0 {
+		t.Fatalf("len(dd.entries) %d is wrong", len(dd.entries))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDeduperSaveInterval$
+func TestDeduperSaveInterval(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	path := filepath.Join(t.TempDir(), "dedup.state")
+	dd := newDeduper(nil, time.Millisecond, path)
+
+	countLines := func() int {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	// The first record is saved immediately, and others are saved after the interval.
+	for i := 0; i < 10; i++ {
+		testDedupCheck(dd, slog.LevelError, "startup failed", slog.Int("i", i))
+	}
+
+	if got := countLines(); got != 1 {
+		t.Fatalf("got %d != want 1", got)
+	}
+
+	if len(dd.entries) != 10 {
+		t.Fatalf("len(dd.entries) %d != 10", len(dd.entries))
+	}
+
+	// Expired entries are removed when saving.
+	now = now.Add(dedupSaveInterval)
+	testDedupCheck(dd, slog.LevelError, "startup failed", slog.Int("i", 10))
+
+	if got := countLines(); got != 1 {
+		t.Fatalf("got %d != want 1", got)
+	}
+
+	if len(dd.entries) != 1 {
+		t.Fatalf("len(dd.entries) %d != 1", len(dd.entries))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDeduperSaveSnapshot$
+func TestDeduperSaveSnapshot(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	path := filepath.Join(t.TempDir(), "dedup.state")
+	dd := newDeduper(nil, time.Minute, path)
+
+	dd.lock.Lock()
+	dd.entries[1] = &dedupEntry{first: now, message: "old"}
+	dd.dirty = true
+	_, oldSnapshot := dd.prune(now)
+
+	dd.entries[2] = &dedupEntry{first: now, message: "new"}
+	dd.dirty = true
+	_, newSnapshot := dd.prune(now)
+	dd.lock.Unlock()
+
+	// The old snapshot is saved after the new one, so it should be skipped.
+	dd.save(newSnapshot)
+	dd.save(oldSnapshot)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, newSnapshot.data) {
+		t.Fatalf("data %q != newSnapshot.data %q", data, newSnapshot.data)
+	}
+
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	defaults.HandleError = func(label string, err error) {}
+
+	// The state should be saved next time if saving failed.
+	dd.path = filepath.Join(t.TempDir(), "not_exist", "dedup.state")
+
+	dd.lock.Lock()
+	dd.dirty = true
+	_, snapshot := dd.prune(now)
+	dd.lock.Unlock()
+
+	dd.save(snapshot)
+
+	if !dd.dirty {
+		t.Fatal("dd.dirty should be true after saving failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDeduperSummarize$
+func TestDeduperSummarize(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	path := filepath.Join(t.TempDir(), "dedup.state")
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	checkLines := func(wantLines []string) {
+		t.Helper()
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if len(lines) != len(wantLines) {
+			t.Fatalf("lines %+v != want %+v", lines, wantLines)
+		}
+
+		for i, line := range lines {
+			if !strings.HasSuffix(line, wantLines[i]) {
+				t.Fatalf("line %d %s != want %s", i, line, wantLines[i])
+			}
+		}
+
+		buffer.Reset()
+	}
+
+	// The burst happens once and never again, so its count is logged in a summary record after window.
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithDedup(time.Minute, path))
+	for i := 0; i < 5; i++ {
+		logger.With("host", "db").Error("connect failed", "status", 500)
+	}
+
+	now = now.Add(time.Minute)
+	logger.Info("recovered")
+
+	checkLines([]string{
+		`"level":"ERROR","msg":"connect failed","host":"db","status":500}`,
+		`"level":"ERROR","msg":"connect failed","host":"db","status":500,"repeated":4}`,
+		`"level":"INFO","msg":"recovered"}`,
+	})
+
+	if dd := newDeduper(nil, time.Minute, path); len(dd.entries) != 1 {
+		t.Fatalf("len(dd.entries) %d != 1", len(dd.entries))
+	}
+
+	// Counts not summarized yet are logged when closing.
+	for i := 0; i < 3; i++ {
+		logger.Info("recovered")
+	}
+
+	logger.Close()
+
+	checkLines([]string{
+		`"level":"INFO","msg":"recovered","repeated":3}`,
+	})
+
+	// The entry left by a crashed process only has its level and message.
+	if err := os.WriteFile(path, []byte("0000000000000001 1000 2 8 \"startup failed\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger = NewLogger(WithWriter(buffer), WithJsonHandler(), WithDedup(time.Minute, path))
+	logger.Info("started")
+	logger.Close()
+
+	checkLines([]string{
+		`"level":"ERROR","msg":"startup failed","repeated":2}`,
+		`"level":"INFO","msg":"started"}`,
+	})
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDedupHandler$
+func TestDedupHandler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithPID(), WithDedup(time.Minute, ""))
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ {
+		logger.With("host", "db").Error("connect failed", "status", 500)
+	}
+
+	logger.With("host", "cache").Error("connect failed", "status", 500)
+	logger.WithGroup("db").Error("connect failed", "status", 500)
+	logger.Error("connect failed", "status", 500)
+
+	if got := strings.Count(buffer.String(), "connect failed"); got != 4 {
+		t.Fatalf("got %d != want 4", got)
+	}
+
+	now = now.Add(time.Minute)
+	logger.With("host", "db").Error("connect failed", "status", 500)
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"status":500,"repeated":9}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
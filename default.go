@@ -15,6 +15,7 @@
 package logit
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"sync/atomic"
@@ -38,45 +39,83 @@ func Default() *Logger {
 	return defaultLogger.Load().(*Logger)
 }
 
+// SetAsSlogDefault sets logger as the default logger of slog.
+// All logs from slog's package functions and log's package functions will be handled by logger.
+// See slog.SetDefault.
+func SetAsSlogDefault(logger *Logger) {
+	slog.SetDefault(logger.Slog())
+}
+
+// Trace logs a log with msg and args in trace level.
+func Trace(msg string, args ...any) {
+	Default().log(context.Background(), LevelTrace, 0, msg, args...)
+}
+
 // Debug logs a log with msg and args in debug level.
 func Debug(msg string, args ...any) {
-	Default().log(slog.LevelDebug, msg, args...)
+	Default().log(context.Background(), slog.LevelDebug, 0, msg, args...)
 }
 
 // Info logs a log with msg and args in info level.
 func Info(msg string, args ...any) {
-	Default().log(slog.LevelInfo, msg, args...)
+	Default().log(context.Background(), slog.LevelInfo, 0, msg, args...)
 }
 
 // Warn logs a log with msg and args in warn level.
 func Warn(msg string, args ...any) {
-	Default().log(slog.LevelWarn, msg, args...)
+	Default().log(context.Background(), slog.LevelWarn, 0, msg, args...)
 }
 
 // Error logs a log with msg and args in error level.
 func Error(msg string, args ...any) {
-	Default().log(slog.LevelError, msg, args...)
+	Default().log(context.Background(), slog.LevelError, 0, msg, args...)
+}
+
+// Panic logs a log with msg and args in panic level.
+// It syncs the default logger and panics with msg after logging.
+func Panic(msg string, args ...any) {
+	logger := Default()
+	logger.log(context.Background(), LevelPanic, 0, msg, args...)
+	logger.panic(msg)
+}
+
+// Panicf logs a log with format and args in panic level.
+// It syncs the default logger and panics with the formatted message after logging.
+func Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	logger := Default()
+	logger.log(context.Background(), LevelPanic, 0, msg)
+	logger.panic(msg)
+}
+
+// Fatal logs a log with msg and args in fatal level.
+// It syncs the default logger and exits the process with code 1 after logging.
+func Fatal(msg string, args ...any) {
+	logger := Default()
+	logger.log(context.Background(), LevelFatal, 0, msg, args...)
+	logger.exit()
 }
 
 // Printf logs a log with format and args in print level.
 // It a old-school way to log.
 func Printf(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Print logs a log with args in print level.
 // It a old-school way to log.
 func Print(args ...interface{}) {
 	msg := fmt.Sprint(args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Println logs a log with args in print level.
 // It a old-school way to log.
 func Println(args ...interface{}) {
 	msg := fmt.Sprintln(args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Sync syncs the default logger and returns an error if failed.
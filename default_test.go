@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"io"
 	"log/slog"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/FishGoddess/logit/handler"
@@ -133,3 +135,73 @@ func TestDefaultLoggerClose(t *testing.T) {
 		t.Fatal("closer.closed is wrong")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDefaultLoggerFatal$
+func TestDefaultLoggerFatal(t *testing.T) {
+	exitCode := 0
+	exit = func(code int) {
+		exitCode = code
+	}
+
+	defer func() {
+		exit = os.Exit
+	}()
+
+	syncer := &testSyncer{
+		synced: false,
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	logger.syncer = syncer
+
+	SetDefault(logger)
+	Fatal("fatal msg", "key", 1)
+
+	if !syncer.synced {
+		t.Fatal("syncer.synced is wrong")
+	}
+
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	if !strings.Contains(buffer.String(), "FATAL ¦ fatal msg ¦ key=1") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDefaultLoggerPanic$
+func TestDefaultLoggerPanic(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	SetDefault(logger)
+
+	defer func() {
+		if r := recover(); r != "panicf msg" {
+			t.Fatalf("r %+v != 'panicf msg'", r)
+		}
+
+		if !strings.Contains(buffer.String(), "PANIC ¦ panicf msg") {
+			t.Fatalf("buffer %s is wrong", buffer.String())
+		}
+	}()
+
+	Panicf("panicf %s", "msg")
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSetAsSlogDefault$
+func TestSetAsSlogDefault(t *testing.T) {
+	slogDefault := slog.Default()
+	defer slog.SetDefault(slogDefault)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+
+	SetAsSlogDefault(logger)
+	slog.Info("slog msg", "key", 1)
+
+	if !strings.Contains(buffer.String(), "INFO ¦ slog msg ¦ key=1") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
@@ -17,6 +17,7 @@ package defaults
 import (
 	"log/slog"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -55,6 +56,29 @@ var (
 	FileDirMode os.FileMode = 0755
 )
 
+var (
+	// MaxCachedLoggers is the max count of loggers cached by Logger.WithCached.
+	MaxCachedLoggers = 1024
+)
+
+var (
+	// JournalSocket is the path of the socket that journald receives logs in native protocol.
+	JournalSocket = "/run/systemd/journal/socket"
+
+	// JournalIdentifier is the SYSLOG_IDENTIFIER field of logs in journal export format.
+	JournalIdentifier = filepath.Base(os.Args[0])
+)
+
+var (
+	// ConsoleShortSource shortens sources of logs in console handler to the last directory and file, like "logit/logger.go:123".
+	ConsoleShortSource = true
+
+	// ConsoleSourceLink returns the link of source, which will be rendered as an OSC 8 hyperlink in console handler.
+	// Sources are only linked when logs are written to a terminal, and nil means no links.
+	// See handler.FileSourceLink and handler.VSCodeSourceLink.
+	ConsoleSourceLink func(file string, line int) string
+)
+
 var (
 	// OpenFile opens a file of path with given mode.
 	OpenFile = func(path string, mode os.FileMode) (*os.File, error) {
@@ -0,0 +1,71 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// depthWarning warns once if a logger is derived by With or WithGroup too many times.
+// It's shared by all loggers derived from the same logger.
+type depthWarning struct {
+	maxDepth int
+
+	// handler is the handler of the logger created by NewLogger, so the warning won't carry attrs added later.
+	handler slog.Handler
+	warned  atomic.Bool
+}
+
+func newDepthWarning(maxDepth int, handler slog.Handler) *depthWarning {
+	warning := &depthWarning{
+		maxDepth: maxDepth,
+		handler:  handler,
+	}
+
+	return warning
+}
+
+// check checks depth and warns once if it exceeds max depth.
+// The caller of With or WithGroup will be carried by the warning, so you can find where the logger is derived.
+func (dw *depthWarning) check(depth int) {
+	if depth <= dw.maxDepth || !dw.warned.CompareAndSwap(false, true) {
+		return
+	}
+
+	ctx := context.Background()
+	if !dw.handler.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	// Skip runtime.Caller, check and With or WithGroup.
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(3); ok {
+		caller = file + ":" + strconv.Itoa(line)
+	}
+
+	msg := "logit: logger is derived by With or WithGroup too many times, maybe it's derived repeatedly and leaks"
+	record := slog.NewRecord(defaults.CurrentTime(), slog.LevelWarn, msg, 0)
+	record.AddAttrs(slog.Int("depth", depth), slog.Int("max_depth", dw.maxDepth), slog.String("caller", caller))
+
+	if err := dw.handler.Handle(ctx, record); err != nil {
+		defaults.HandleError("depthWarning.handler.Handle", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDepthWarning$
+func TestDepthWarning(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithDepthWarning(3))
+
+	derived := logger
+	for i := 0; i < 3; i++ {
+		derived = derived.With("i", i)
+	}
+
+	if derived.depth != 3 || buffer.Len() > 0 {
+		t.Fatalf("depth %d or buffer %s is wrong", derived.depth, buffer.String())
+	}
+
+	derived = derived.WithGroup("group")
+	derived.With("i", 4).WithGroup("again")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(logs) != 1 {
+		t.Fatalf("len(logs) %d != 1", len(logs))
+	}
+
+	if !strings.Contains(logs[0], "WARN") || !strings.Contains(logs[0], "depth=4 ¦ max_depth=3 ¦ caller=") {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	if !strings.Contains(logs[0], "depth_test.go:") || strings.Contains(logs[0], "i=") {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	buffer.Reset()
+	logger = NewLogger(WithWriter(buffer))
+
+	for i := 0; i < 100; i++ {
+		logger = logger.With("i", i)
+	}
+
+	if logger.depthWarning != nil || buffer.Len() > 0 {
+		t.Fatalf("depthWarning %+v or buffer %s is wrong", logger.depthWarning, buffer.String())
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// elevation is a level used temporarily until deadline.
+type elevation struct {
+	level    slog.Level
+	deadline time.Time
+}
+
+// levelState is the level of loggers which can be elevated temporarily.
+// It's shared by derived loggers because they share the same handler.
+// The leveler may be dynamic like a slog.LevelVar, see WithLeveler.
+type levelState struct {
+	leveler   slog.Leveler
+	elevation atomic.Pointer[elevation]
+}
+
+func newLevelState(leveler slog.Leveler) *levelState {
+	return &levelState{leveler: leveler}
+}
+
+// Level returns the elevated level if it's not expired, or the level of logger.
+// Expired elevations are cleared when checking, so the level will be restored automatically without timers.
+func (ls *levelState) Level() slog.Level {
+	if e := ls.elevation.Load(); e != nil {
+		if defaults.CurrentTime().Before(e.deadline) {
+			return e.level
+		}
+
+		// Only the expired one is cleared, so a new elevation stored concurrently won't be lost.
+		ls.elevation.CompareAndSwap(e, nil)
+	}
+
+	return ls.leveler.Level()
+}
+
+// elevate uses level until d passed and returns a function restoring the level.
+// The latest elevation replaces the former ones, and restoring a replaced elevation does nothing.
+func (ls *levelState) elevate(level slog.Level, d time.Duration) (undo func()) {
+	e := &elevation{
+		level:    level,
+		deadline: defaults.CurrentTime().Add(d),
+	}
+
+	ls.elevation.Store(e)
+
+	undo = func() {
+		ls.elevation.CompareAndSwap(e, nil)
+	}
+
+	return undo
+}
+
+// Elevate uses level as the level of logger temporarily for d, which is useful for debugging incidents.
+// The level will be restored automatically after d or by calling undo, so you won't forget to turn debug logs off.
+// The elevation applies to all loggers derived from the same logger because they share the same handler,
+// and the latest elevation replaces the former ones.
+//
+//	undo := logger.Elevate(slog.LevelDebug, 5*time.Minute)
+//	defer undo()
+func (l *Logger) Elevate(level slog.Level, d time.Duration) (undo func()) {
+	if l.levelState == nil {
+		return func() {}
+	}
+
+	return l.levelState.elevate(level, d)
+}
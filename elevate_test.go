@@ -0,0 +1,102 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelState$
+func TestLevelState(t *testing.T) {
+	now := time.Unix(100, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	ls := newLevelState(slog.LevelInfo)
+	if ls.Level() != slog.LevelInfo {
+		t.Fatalf("ls.Level() %s != slog.LevelInfo", ls.Level())
+	}
+
+	undo := ls.elevate(slog.LevelDebug, time.Minute)
+	if ls.Level() != slog.LevelDebug {
+		t.Fatalf("ls.Level() %s != slog.LevelDebug", ls.Level())
+	}
+
+	now = now.Add(time.Minute)
+	if ls.Level() != slog.LevelInfo {
+		t.Fatalf("ls.Level() %s != slog.LevelInfo", ls.Level())
+	}
+
+	// The expired elevation is cleared, so checking levels won't load it again.
+	if e := ls.elevation.Load(); e != nil {
+		t.Fatalf("expired elevation %+v isn't cleared", e)
+	}
+
+	undo = ls.elevate(slog.LevelDebug, time.Minute)
+	latestUndo := ls.elevate(LevelTrace, time.Minute)
+
+	// Restoring a replaced elevation does nothing.
+	undo()
+	if ls.Level() != LevelTrace {
+		t.Fatalf("ls.Level() %s != LevelTrace", ls.Level())
+	}
+
+	latestUndo()
+	if ls.Level() != slog.LevelInfo {
+		t.Fatalf("ls.Level() %s != slog.LevelInfo", ls.Level())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerElevate$
+func TestLoggerElevate(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithInfoLevel())
+	derived := logger.With("k", "v")
+
+	logger.Debug("before")
+	undo := logger.Elevate(slog.LevelDebug, time.Hour)
+
+	logger.Debug("elevated")
+	derived.Debug("derived")
+
+	if !logger.DebugEnabled() {
+		t.Fatal("logger.DebugEnabled() should be true")
+	}
+
+	undo()
+	logger.Debug("after")
+
+	got := buffer.String()
+	if strings.Contains(got, "before") || strings.Contains(got, "after") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if !strings.Contains(got, "DEBUG ¦ elevated") || !strings.Contains(got, "DEBUG ¦ derived ¦ k=v") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	NewNopLogger().Elevate(slog.LevelDebug, time.Hour)()
+}
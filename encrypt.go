@@ -0,0 +1,172 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// encryptedConnector connects the key id and the encrypted value.
+	encryptedConnector = ":"
+
+	// encryptFailedValue replaces the value failed to encrypt so the raw value won't be exposed.
+	encryptFailedValue = "!ENCRYPT_FAILED"
+)
+
+// KeyProvider returns the key for encrypting attrs and its id.
+// The key should be 16, 24 or 32 bytes to select AES-128, AES-192 or AES-256.
+// The id will be written with the encrypted value, so you can rotate keys and still find the right key when decrypting.
+// It's called every time an attr is encrypted, so keep it fast.
+type KeyProvider func() (id string, key []byte, err error)
+
+// KeyFinder finds the key with its id for decrypting attrs.
+type KeyFinder func(id string) (key []byte, err error)
+
+type cachedAEAD struct {
+	id   string
+	key  []byte
+	aead cipher.AEAD
+}
+
+type attrEncryptor struct {
+	provider KeyProvider
+	keys     map[string]struct{}
+
+	// cached is the aead created last time, so we don't need to create it for every attr.
+	cached atomic.Pointer[cachedAEAD]
+}
+
+func newAttrEncryptor(provider KeyProvider, keys []string) *attrEncryptor {
+	encryptor := &attrEncryptor{
+		provider: provider,
+		keys:     make(map[string]struct{}, len(keys)),
+	}
+
+	for _, key := range keys {
+		encryptor.keys[key] = struct{}{}
+	}
+
+	return encryptor
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (ae *attrEncryptor) aead() (string, cipher.AEAD, error) {
+	id, key, err := ae.provider()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if cached := ae.cached.Load(); cached != nil && cached.id == id && bytes.Equal(cached.key, key) {
+		return id, cached.aead, nil
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ae.cached.Store(&cachedAEAD{id: id, key: bytes.Clone(key), aead: aead})
+	return id, aead, nil
+}
+
+func (ae *attrEncryptor) encrypt(value string) (string, error) {
+	id, aead, err := ae.aead()
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := aead.NonceSize()
+	nonce := make([]byte, nonceSize, nonceSize+len(value)+aead.Overhead())
+
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return id + encryptedConnector + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (ae *attrEncryptor) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if _, ok := ae.keys[attr.Key]; !ok {
+		return attr
+	}
+
+	encrypted, err := ae.encrypt(attr.Value.Resolve().String())
+	if err != nil {
+		defaults.HandleError("attrEncryptor.encrypt", err)
+		encrypted = encryptFailedValue
+	}
+
+	attr.Value = slog.StringValue(encrypted)
+	return attr
+}
+
+// DecryptAttr decrypts a value encrypted by WithEncryptedAttrs.
+// The key will be found by finder with the id in value.
+func DecryptAttr(value string, finder KeyFinder) (string, error) {
+	index := strings.LastIndex(value, encryptedConnector)
+	if index < 0 {
+		return "", fmt.Errorf("logit: encrypted value %s missing key id", value)
+	}
+
+	id, encoded := value[:index], value[index+len(encryptedConnector):]
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := finder(id)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("logit: encrypted value is too short")
+	}
+
+	decrypted, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithEncryptedAttrs$
+func TestWithEncryptedAttrs(t *testing.T) {
+	keys := map[string][]byte{
+		"k1": []byte("0123456789abcdef"),
+		"k2": []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	id := "k1"
+	provider := func() (string, []byte, error) {
+		return id, keys[id], nil
+	}
+
+	finder := func(id string) ([]byte, error) {
+		if key, ok := keys[id]; ok {
+			return key, nil
+		}
+
+		return nil, errors.New("key not found")
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithEncryptedAttrs(provider, "email", "user_id"))
+
+	logger.Info("msg", "email", "fish@goddess.com", "user_id", 123, "other", "plain")
+	id = "k2"
+	logger.Info("msg", "email", "fish@goddess.com", "user_id", 456, "other", "plain")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	wants := []struct {
+		id     string
+		userID string
+	}{
+		{id: "k1", userID: "123"},
+		{id: "k2", userID: "456"},
+	}
+
+	for i, want := range wants {
+		m := make(map[string]any, 8)
+		if err := json.Unmarshal([]byte(logs[i]), &m); err != nil {
+			t.Fatal(err)
+		}
+
+		if m["other"] != "plain" {
+			t.Fatalf("other %+v != plain", m["other"])
+		}
+
+		email := m["email"].(string)
+		if !strings.HasPrefix(email, want.id+":") || strings.Contains(email, "fish") {
+			t.Fatalf("email %s is wrong", email)
+		}
+
+		decrypted, err := DecryptAttr(email, finder)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if decrypted != "fish@goddess.com" {
+			t.Fatalf("decrypted %s != fish@goddess.com", decrypted)
+		}
+
+		decrypted, err = DecryptAttr(m["user_id"].(string), finder)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if decrypted != want.userID {
+			t.Fatalf("decrypted %s != %s", decrypted, want.userID)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithEncryptedAttrsFailed$
+func TestWithEncryptedAttrsFailed(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	var gotErr error
+	defaults.HandleError = func(label string, err error) {
+		gotErr = err
+	}
+
+	provider := func() (string, []byte, error) {
+		return "bad", []byte("short"), nil
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithEncryptedAttrs(provider, "email"))
+	logger.Info("msg", "email", "fish@goddess.com")
+
+	if gotErr == nil {
+		t.Fatal("gotErr == nil")
+	}
+
+	log := buffer.String()
+	if strings.Contains(log, "fish") || !strings.Contains(log, "email="+encryptFailedValue) {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDecryptAttr$
+func TestDecryptAttr(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	provider := func() (string, []byte, error) {
+		return "id:with:colons", key, nil
+	}
+
+	finder := func(id string) ([]byte, error) {
+		if id != "id:with:colons" {
+			t.Fatalf("id %s is wrong", id)
+		}
+
+		return key, nil
+	}
+
+	encryptor := newAttrEncryptor(provider, nil)
+
+	encrypted, err := encryptor.encrypt("value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := DecryptAttr(encrypted, finder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != "value" {
+		t.Fatalf("decrypted %s != value", decrypted)
+	}
+
+	badValues := []string{"no_id", "id:!!!", "id:YWJj", encrypted[:len(encrypted)-4] + "AAAA"}
+	for _, value := range badValues {
+		if _, err = DecryptAttr(value, func(id string) ([]byte, error) { return key, nil }); err == nil {
+			t.Fatalf("decrypt %s should be failed", value)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAttrEncryptorReplaceAttr$
+func TestAttrEncryptorReplaceAttr(t *testing.T) {
+	provider := func() (string, []byte, error) {
+		return "id", []byte("0123456789abcdef"), nil
+	}
+
+	encryptor := newAttrEncryptor(provider, []string{"k"})
+
+	attr := encryptor.replaceAttr(nil, slog.String("other", "v"))
+	if attr.Value.String() != "v" {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	attr = encryptor.replaceAttr(nil, slog.String("k", "v"))
+	if !strings.HasPrefix(attr.Value.String(), "id:") {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	cached := encryptor.cached.Load()
+	encryptor.replaceAttr(nil, slog.String("k", "v"))
+
+	if encryptor.cached.Load() != cached {
+		t.Fatal("cached aead should be reused")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+const (
+	keyError      = "error"
+	keyErrorMsg   = "msg"
+	keyErrorType  = "type"
+	keyErrorChain = "chain"
+	keyErrorStack = "stack"
+
+	// maxStackFrames is the max count of frames captured by ErrWithStack.
+	maxStackFrames = 32
+)
+
+// errorChain returns the messages of errors wrapped by err in depth-first order.
+// Errors joined by errors.Join or wrapped by multiple %w are all included.
+func errorChain(err error) []string {
+	var chain []string
+
+	var walk func(err error)
+	walk = func(err error) {
+		var wrapped []error
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			if unwrapped := e.Unwrap(); unwrapped != nil {
+				wrapped = []error{unwrapped}
+			}
+		case interface{ Unwrap() []error }:
+			wrapped = e.Unwrap()
+		}
+
+		for _, e := range wrapped {
+			if e == nil {
+				continue
+			}
+
+			chain = append(chain, e.Error())
+			walk(e)
+		}
+	}
+
+	walk(err)
+	return chain
+}
+
+// errorStack returns the stack of caller which skips skip frames.
+// The skip is the same as runtime.Callers and the frame of errorStack is skipped.
+func errorStack(skip int) []string {
+	var pcs [maxStackFrames]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+
+	stack := make([]string, 0, n)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+func errorAttrs(err error) []any {
+	attrs := []any{
+		slog.String(keyErrorMsg, err.Error()),
+		slog.String(keyErrorType, fmt.Sprintf("%T", err)),
+	}
+
+	if chain := errorChain(err); len(chain) > 0 {
+		attrs = append(attrs, slog.Any(keyErrorChain, chain))
+	}
+
+	return attrs
+}
+
+// Err returns an error group attr carrying the message, type and wrapped chain of err,
+// so errors won't lose their structure like logging them with slog.Any.
+// The chain includes messages of all errors wrapped by err, see errors.Unwrap and errors.Join.
+// A nil err returns an attr with a nil value.
+// See ErrWithStack if you want the stack trace.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Any(keyError, nil)
+	}
+
+	return slog.Group(keyError, errorAttrs(err)...)
+}
+
+// ErrWithStack is like Err but the stack trace of its caller will be captured, which is useful for unexpected errors.
+// Capturing stack trace is expensive, so don't use it in hot paths.
+func ErrWithStack(err error) slog.Attr {
+	if err == nil {
+		return slog.Any(keyError, nil)
+	}
+
+	// Skip runtime.Callers, errorStack and ErrWithStack.
+	attrs := errorAttrs(err)
+	attrs = append(attrs, slog.Any(keyErrorStack, errorStack(2)))
+
+	return slog.Group(keyError, attrs...)
+}
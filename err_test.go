@@ -0,0 +1,114 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErrorChain$
+func TestErrorChain(t *testing.T) {
+	errA := errors.New("a")
+	errB := fmt.Errorf("b: %w", errA)
+	errC := errors.New("c")
+	err := fmt.Errorf("top: %w", errors.Join(errB, errC))
+
+	chain := errorChain(err)
+	want := []string{"b: a\nc", "b: a", "a", "c"}
+
+	if strings.Join(chain, "|") != strings.Join(want, "|") {
+		t.Fatalf("chain %q != want %q", chain, want)
+	}
+
+	if chain = errorChain(errA); len(chain) != 0 {
+		t.Fatalf("chain %q should be empty", chain)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErr$
+func TestErr(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+
+	err := fmt.Errorf("open config: %w", fs.ErrNotExist)
+	logger.Error("msg", Err(err))
+
+	var got struct {
+		Error struct {
+			Msg   string   `json:"msg"`
+			Type  string   `json:"type"`
+			Chain []string `json:"chain"`
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(buffer.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Error.Msg != "open config: file does not exist" || got.Error.Type != "*fmt.wrapError" {
+		t.Fatalf("got %+v is wrong", got)
+	}
+
+	if len(got.Error.Chain) != 1 || got.Error.Chain[0] != "file does not exist" || len(got.Error.Stack) != 0 {
+		t.Fatalf("got %+v is wrong", got)
+	}
+
+	if attr := Err(nil); attr.Key != keyError || attr.Value.Any() != nil {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErrWithStack$
+func TestErrWithStack(t *testing.T) {
+	attr := ErrWithStack(errors.New("oops"))
+	if attr.Value.Kind() != slog.KindGroup {
+		t.Fatalf("attr.Value.Kind() %s != slog.KindGroup", attr.Value.Kind())
+	}
+
+	var stack []string
+	for _, groupAttr := range attr.Value.Group() {
+		if groupAttr.Key == keyErrorStack {
+			stack = groupAttr.Value.Any().([]string)
+		}
+	}
+
+	if len(stack) == 0 || !strings.HasPrefix(stack[0], "github.com/FishGoddess/logit.TestErrWithStack ") {
+		t.Fatalf("stack %q is wrong", stack)
+	}
+
+	if !strings.Contains(stack[0], "err_test.go:") {
+		t.Fatalf("stack[0] %s is wrong", stack[0])
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	logger.Error("msg", attr)
+
+	if got := buffer.String(); !strings.Contains(got, "error.msg=oops ¦ error.type=*errors.errorString ¦ error.stack=[") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if attr = ErrWithStack(nil); attr.Key != keyError || attr.Value.Any() != nil {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+}
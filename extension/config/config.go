@@ -16,15 +16,26 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/handler"
 	"github.com/FishGoddess/logit/rotate"
+	"github.com/FishGoddess/logit/writer"
 )
 
 type WriterConfig struct {
 	// Target is where the writer writes logs.
-	// Values: "stdout", "stderr", or a file path like "./logit.log".
+	// Values: "stdout", "stderr", "journal", or a file path like "./logit.log".
+	// The "journal" target sends logs to journald with journal handler, see logit.WithJournal.
 	Target string `json:"target" yaml:"target" toml:"target" bson:"target"`
 
 	// FileRotate is log file should split and backup when satisfy some conditions.
@@ -32,6 +43,11 @@ type WriterConfig struct {
 	// Only available when target is a file path.
 	FileRotate bool `json:"file_rotate" yaml:"file_rotate" toml:"file_rotate" bson:"file_rotate"`
 
+	// FileLevels writes each level to its own rotate file like "logit.info.log" and "logit.error.log".
+	// All files share the same rotation options in this config, and FileRotate is ignored.
+	// Only available when target is a file path and not available in Writers and ErrorWriter, see logit.WithLevelFiles.
+	FileLevels bool `json:"file_levels" yaml:"file_levels" toml:"file_levels" bson:"file_levels"`
+
 	// FileMaxSize is the max size of a log file.
 	// If size of data in one output operation is bigger than this value, then file will rotate before writing,
 	// which means file and its backups may be bigger than this value in size.
@@ -50,6 +66,34 @@ type WriterConfig struct {
 	// Only available when rotate is true.
 	FileMaxBackups uint32 `json:"file_max_backups" yaml:"file_max_backups" toml:"file_max_backups" bson:"file_max_backups"`
 
+	// FileMaxTotalSize is the max total size of file backups.
+	// The oldest backups will be removed automatically if total size exceeds it.
+	// You can use common words like "100MB" or "1GB".
+	// Only available when rotate is true.
+	FileMaxTotalSize string `json:"file_max_total_size" yaml:"file_max_total_size" toml:"file_max_total_size" bson:"file_max_total_size"`
+
+	// FileRetainAtLeast is the min count of file backups to keep.
+	// A backup is removed if it breaks any of max age, max backups and max total size,
+	// but the newest backups in this count are always kept even if they exceed max backups, reach max age or exceed max total size.
+	// Only available when rotate is true.
+	FileRetainAtLeast uint32 `json:"file_retain_at_least" yaml:"file_retain_at_least" toml:"file_retain_at_least" bson:"file_retain_at_least"`
+
+	// FileTimeFormat is the time format in names of file backups, like "20060102150405".
+	// See time.Layout.
+	// Only available when rotate is true.
+	FileTimeFormat string `json:"file_time_format" yaml:"file_time_format" toml:"file_time_format" bson:"file_time_format"`
+
+	// FileTimeZone is the time zone in names of file backups, which is "UTC" by default.
+	// You can use "Local" or a name in IANA time zone database like "Asia/Shanghai".
+	// See time.LoadLocation.
+	// Only available when rotate is true.
+	FileTimeZone string `json:"file_time_zone" yaml:"file_time_zone" toml:"file_time_zone" bson:"file_time_zone"`
+
+	// AtomicSize is the max size of logs which are guaranteed to be written atomically, like "4KB".
+	// Each log will be written in one write call with a lock, so logs won't be interleaved or torn under high concurrency.
+	// See logit.WithAtomicWrites.
+	AtomicSize string `json:"atomic_size" yaml:"atomic_size" toml:"atomic_size" bson:"atomic_size"`
+
 	// BufferSize is the size of a buffer.
 	// You can use common words like "512B" or "4KB".
 	// Only available when mode is "buffer".
@@ -58,6 +102,17 @@ type WriterConfig struct {
 	// BatchSize is the size of a batch.
 	// Only available when mode is "batch".
 	BatchSize uint64 `json:"batch_size" yaml:"batch_size" toml:"batch_size" bson:"batch_size"`
+
+	// BatchParallelism is the max count of full batches flushed in parallel.
+	// Batches are flushed in order if it's less than 2, which is required by streams like audit logs.
+	// Otherwise, batches may be written out of order, which is fine for telemetry streams to network sinks.
+	// Only available when mode is "batch", see logit.WithParallelBatch.
+	BatchParallelism int `json:"batch_parallelism" yaml:"batch_parallelism" toml:"batch_parallelism" bson:"batch_parallelism"`
+
+	// Stats enables stats of writes and flushes in buffer or batch, see logit.Logger.WriteStats.
+	// It's useful for tuning buffer size or batch size from data.
+	// Only available when mode is "buffer" or "batch".
+	Stats bool `json:"stats" yaml:"stats" toml:"stats" bson:"stats"`
 }
 
 func (wc *WriterConfig) parseFileOptions() ([]rotate.Option, error) {
@@ -85,6 +140,32 @@ func (wc *WriterConfig) parseFileOptions() ([]rotate.Option, error) {
 		opts = append(opts, rotate.WithMaxBackups(wc.FileMaxBackups))
 	}
 
+	if wc.FileMaxTotalSize != "" {
+		maxTotalSize, err := parseByteSize(wc.FileMaxTotalSize)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, rotate.WithMaxTotalSize(maxTotalSize))
+	}
+
+	if wc.FileRetainAtLeast > 0 {
+		opts = append(opts, rotate.WithRetainAtLeast(wc.FileRetainAtLeast))
+	}
+
+	if wc.FileTimeFormat != "" {
+		opts = append(opts, rotate.WithTimeFormat(wc.FileTimeFormat))
+	}
+
+	if wc.FileTimeZone != "" {
+		location, err := time.LoadLocation(wc.FileTimeZone)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, rotate.WithTimeLocation(location))
+	}
+
 	return opts, nil
 }
 
@@ -105,7 +186,12 @@ func (wc *WriterConfig) appendTargetOptions(opts []logit.Option) ([]logit.Option
 		return opts, nil
 	}
 
-	if !wc.FileRotate {
+	if target == "journal" {
+		opts = append(opts, logit.WithJournal())
+		return opts, nil
+	}
+
+	if !wc.FileRotate && !wc.FileLevels {
 		opts = append(opts, logit.WithFile(wc.Target))
 		return opts, nil
 	}
@@ -115,11 +201,38 @@ func (wc *WriterConfig) appendTargetOptions(opts []logit.Option) ([]logit.Option
 		return nil, err
 	}
 
+	if wc.FileLevels {
+		opts = append(opts, logit.WithLevelFiles(wc.Target, fileOpts...))
+		return opts, nil
+	}
+
 	opts = append(opts, logit.WithRotateFile(wc.Target, fileOpts...))
 	return opts, nil
 }
 
+func (wc *WriterConfig) parseAtomicSize() (int, error) {
+	atomicSize, err := parseByteSize(wc.AtomicSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if atomicSize <= 0 || atomicSize > math.MaxInt32 {
+		return 0, fmt.Errorf("logit: atomic size %s is out of range", wc.AtomicSize)
+	}
+
+	return int(atomicSize), nil
+}
+
 func (wc *WriterConfig) appendModeOptions(opts []logit.Option) ([]logit.Option, error) {
+	if wc.AtomicSize != "" {
+		atomicSize, err := wc.parseAtomicSize()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, logit.WithAtomicWrites(atomicSize))
+	}
+
 	if wc.BufferSize != "" {
 		bufferSize, err := parseByteSize(wc.BufferSize)
 		if err != nil {
@@ -129,13 +242,97 @@ func (wc *WriterConfig) appendModeOptions(opts []logit.Option) ([]logit.Option,
 		opts = append(opts, logit.WithBuffer(bufferSize))
 	}
 
-	if wc.BatchSize > 0 {
+	if wc.BatchSize > 0 && wc.BatchParallelism > 1 {
+		opts = append(opts, logit.WithParallelBatch(wc.BatchSize, wc.BatchParallelism))
+	} else if wc.BatchSize > 0 {
 		opts = append(opts, logit.WithBatch(wc.BatchSize))
 	}
 
+	if wc.Stats {
+		opts = append(opts, logit.WithWriteStats())
+	}
+
 	return opts, nil
 }
 
+func (wc *WriterConfig) newTargetWriter() (io.Writer, error) {
+	target := strings.ToLower(wc.Target)
+
+	if target == "stdout" {
+		return os.Stdout, nil
+	}
+
+	if target == "stderr" {
+		return os.Stderr, nil
+	}
+
+	if target == "" || target == "journal" {
+		return nil, fmt.Errorf("logit: target %s isn't supported in writers", wc.Target)
+	}
+
+	if wc.FileLevels {
+		return nil, fmt.Errorf("logit: file levels of target %s isn't supported in writers", wc.Target)
+	}
+
+	if !wc.FileRotate {
+		if err := defaults.OpenFileDir(filepath.Dir(wc.Target), defaults.FileDirMode); err != nil {
+			return nil, err
+		}
+
+		return defaults.OpenFile(wc.Target, defaults.FileMode)
+	}
+
+	fileOpts, err := wc.parseFileOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return rotate.New(wc.Target, fileOpts...)
+}
+
+// newWriter creates the writer of target wrapped in mode, which is used by Config.Writers.
+// The writer is wrapped in the same order as options of logit, and batch is used if both buffer and batch are set.
+func (wc *WriterConfig) newWriter() (io.Writer, error) {
+	atomicSize := 0
+	if wc.AtomicSize != "" {
+		size, err := wc.parseAtomicSize()
+		if err != nil {
+			return nil, err
+		}
+
+		atomicSize = size
+	}
+
+	var bufferSize uint64
+	if wc.BufferSize != "" {
+		size, err := parseByteSize(wc.BufferSize)
+		if err != nil {
+			return nil, err
+		}
+
+		bufferSize = size
+	}
+
+	w, err := wc.newTargetWriter()
+	if err != nil {
+		return nil, err
+	}
+
+	if atomicSize > 0 {
+		w = writer.Atomic(w, atomicSize)
+	}
+
+	if wc.BatchSize > 0 {
+		return writer.BatchParallel(w, wc.BatchSize, wc.BatchParallelism), nil
+	}
+
+	if bufferSize > 0 {
+		return writer.Buffer(w, bufferSize), nil
+	}
+
+	return w, nil
+}
+
 // Options parses a writer config and returns a list of options.
 // Return an error if parse failed.
 func (wc *WriterConfig) Options() (opts []logit.Option, err error) {
@@ -155,39 +352,254 @@ func (wc *WriterConfig) Options() (opts []logit.Option, err error) {
 	return opts, nil
 }
 
+type FilterConfig struct {
+	// Message is a regular expression matching messages of records, and an empty string matches all messages.
+	Message string `json:"message" yaml:"message" toml:"message" bson:"message"`
+
+	// Attrs are attrs which records must have, like {"component": "grpc"}.
+	// Keys of attrs in groups are joined with ".", like "http.status".
+	Attrs map[string]string `json:"attrs" yaml:"attrs" toml:"attrs" bson:"attrs"`
+
+	// ForbiddenAttrs are attrs which records must not have.
+	ForbiddenAttrs map[string]string `json:"forbidden_attrs" yaml:"forbidden_attrs" toml:"forbidden_attrs" bson:"forbidden_attrs"`
+
+	// MaxLevel is the max level of records suppressed, and an empty string means "info".
+	// Records higher than it are always kept, so the errors of muted components won't be lost.
+	MaxLevel string `json:"max_level" yaml:"max_level" toml:"max_level" bson:"max_level"`
+}
+
+// rule parses a filter config and returns a filter rule.
+func (fc *FilterConfig) rule() (logit.FilterRule, error) {
+	rule := logit.FilterRule{
+		Message:        fc.Message,
+		Attrs:          fc.Attrs,
+		ForbiddenAttrs: fc.ForbiddenAttrs,
+		MaxLevel:       slog.LevelInfo,
+	}
+
+	if fc.MaxLevel != "" {
+		level, err := logit.ParseLevel(fc.MaxLevel)
+		if err != nil {
+			return logit.FilterRule{}, err
+		}
+
+		rule.MaxLevel = level
+	}
+
+	return rule, nil
+}
+
 type Config struct {
 	// Level is the level of logger.
-	// Values: debug, info, warn, error.
+	// Values: trace, debug, info, warn, error.
+	// It can also be the name of a leveler registered by logit.RegisterLeveler, so loggers share the dynamic level.
+	// Also, you can use the levels registered by logit.RegisterLevel.
 	Level string `json:"level" yaml:"level" toml:"level" bson:"level"`
 
+	// LevelNames is the names of levels output by handlers, whose key is the level and value is the name.
+	// For example, {"warn": "WARNING", "info": "info"} outputs levels as "WARNING" and "info".
+	// Names can also be used in Level, and notice that they're registered globally, see logit.RegisterLevelNames.
+	LevelNames map[string]string `json:"level_names" yaml:"level_names" toml:"level_names" bson:"level_names"`
+
 	// Handler is how the handler handles the logs.
-	// Values: "tape", "text", "json".
+	// Values: "tape", "text", "json", "console", "csv", "protobuf", "journal", "journal_export", "auto".
+	// The "auto" handler picks console for terminals, json for stdout and stderr which aren't terminals and tape for files.
 	// Also, you can register your handlers to logit, see RegisterHandler.
 	Handler string `json:"handler" yaml:"handler" toml:"handler" bson:"handler"`
 
+	// CSVColumns is the columns of csv handler, like ["time", "level", "msg", "user_id"].
+	// Only available when Handler is "csv", see logit.WithCSVHandler.
+	CSVColumns []string `json:"csv_columns" yaml:"csv_columns" toml:"csv_columns" bson:"csv_columns"`
+
+	// ConsoleColors is the colors of levels in console handler, whose key is the level and value is the color.
+	// Colors can be names like "red" and "bright_blue", codes in the 256-color palette like "208", or hex like "#ff8700".
+	// For example, {"info": "28", "warn": "#af5f00"} is more readable on light terminal themes.
+	// Only available when Handler is "console" or "auto", see logit.WithConsoleLevelColors.
+	ConsoleColors map[string]string `json:"console_colors" yaml:"console_colors" toml:"console_colors" bson:"console_colors"`
+
+	// ConsoleIcons is the icons of levels in console handler like emojis, whose key is the level and value is the icon.
+	// Only available when Handler is "console" or "auto", see logit.WithConsoleLevelIcons.
+	ConsoleIcons map[string]string `json:"console_icons" yaml:"console_icons" toml:"console_icons" bson:"console_icons"`
+
+	// ConsoleLevelWidth is the fixed width of levels in console handler, and longer levels are cut.
+	// Only available when Handler is "console" or "auto", see logit.WithConsoleColumns.
+	ConsoleLevelWidth int `json:"console_level_width" yaml:"console_level_width" toml:"console_level_width" bson:"console_level_width"`
+
+	// ConsoleLoggerWidth is the width of the column of logger names in console handler.
+	// Logger names are set by logit.Logger.Named, and longer names keep their last parts.
+	// Only available when Handler is "console" or "auto", see logit.WithConsoleColumns.
+	ConsoleLoggerWidth int `json:"console_logger_width" yaml:"console_logger_width" toml:"console_logger_width" bson:"console_logger_width"`
+
+	// ConsoleMessageWidth is the max width of messages in console handler, and longer messages are wrapped.
+	// Only available when Handler is "console" or "auto", see logit.WithConsoleColumns.
+	ConsoleMessageWidth int `json:"console_message_width" yaml:"console_message_width" toml:"console_message_width" bson:"console_message_width"`
+
 	// Writer is the config of writer.
 	Writer WriterConfig `json:"writer" yaml:"writer" toml:"writer" bson:"writer"`
 
+	// Writers is the configs of writers which logs are written to at once, like stdout and a rotate file.
+	// Target of Writer is ignored if it's not empty, but modes of Writer still wrap all writers.
+	// Targets in it can't be "journal".
+	// Stats isn't available in it, and files in it are opened when parsing options, see logit.WithWriters.
+	Writers []WriterConfig `json:"writers" yaml:"writers" toml:"writers" bson:"writers"`
+
+	// ErrorWriter is the config of writer which warn and error logs are written to instead of Writer and Writers.
+	// It's ignored if its target is empty, and modes of Writer also wrap it.
+	// Its target can't be "journal" and Stats isn't available in it, see logit.WithErrorWriter.
+	ErrorWriter WriterConfig `json:"error_writer" yaml:"error_writer" toml:"error_writer" bson:"error_writer"`
+
 	// WithSource adds source to logs if true.
 	WithSource bool `json:"with_source" yaml:"with_source" toml:"with_source" bson:"with_source"`
 
+	// SourceRoot is the root trimmed from the file of sources.
+	// Values: "module" means the root of the main module, or a path like "/home/app".
+	// Only available when WithSource is true, see logit.WithSourceRoot.
+	SourceRoot string `json:"source_root" yaml:"source_root" toml:"source_root" bson:"source_root"`
+
+	// SourceSegments is the count of the last segments of the file of sources kept.
+	// Only available when WithSource is true, see logit.WithSourceSegments.
+	SourceSegments int `json:"source_segments" yaml:"source_segments" toml:"source_segments" bson:"source_segments"`
+
 	// WithPID adds pid to logs if true.
 	WithPID bool `json:"with_pid" yaml:"with_pid" toml:"with_pid" bson:"with_pid"`
 
+	// Colors colors levels and keys of logs written to a terminal if true.
+	// Only available when Handler is "tape", see logit.WithColor.
+	Colors bool `json:"colors" yaml:"colors" toml:"colors" bson:"colors"`
+
+	// Escape is the mode of escaping strings in logs.
+	// Values: "full", "minimal", "none", and an empty string means "minimal".
+	// Only available when Handler is "tape", see logit.WithEscapeMode.
+	Escape string `json:"escape" yaml:"escape" toml:"escape" bson:"escape"`
+
+	// TimeFormat is the format of the time of logs.
+	// Values: "unix", "unix_ms", "unix_us", "unix_ns", "rfc3339", "rfc3339nano", or a layout like "2006-01-02 15:04:05".
+	// An empty string means using the default format of handler.
+	// See logit.WithTimeFormat.
+	TimeFormat string `json:"time_format" yaml:"time_format" toml:"time_format" bson:"time_format"`
+
+	// UTC converts the time of logs to UTC if true.
+	// See logit.WithUTC.
+	UTC bool `json:"utc" yaml:"utc" toml:"utc" bson:"utc"`
+
+	// Attrs is the attrs attached to all records, like the metadata of deployment.
+	// Attrs are sorted by keys, and they're added once when creating the logger.
+	// See logit.WithStaticAttrs.
+	Attrs map[string]string `json:"attrs" yaml:"attrs" toml:"attrs" bson:"attrs"`
+
+	// ContextKeys is the keys of values in context which will be appended to logs with context, like ["request_id", "tenant_id"].
+	// Values should be stored in context with logit.ContextKey, see logit.ExtractContextKeys.
+	ContextKeys []string `json:"context_keys" yaml:"context_keys" toml:"context_keys" bson:"context_keys"`
+
+	// AttrTypes is the types that values of attrs will be coerced to, whose key is the key of attrs.
+	// Values: "string", "int", "float", "bool".
+	// See logit.WithCoercedAttrs.
+	AttrTypes map[string]string `json:"attr_types" yaml:"attr_types" toml:"attr_types" bson:"attr_types"`
+
+	// Masking is the keys and regular expressions whose values will be replaced with "***".
+	// Rules like "password" are keys and others like `\d{16}` are regular expressions.
+	// See logit.WithMasking.
+	Masking []string `json:"masking" yaml:"masking" toml:"masking" bson:"masking"`
+
+	// Filters are rules suppressing records matching them, which is useful for muting noisy components.
+	// A record is suppressed if it matches one of rules, see logit.WithFilters.
+	Filters []FilterConfig `json:"filters" yaml:"filters" toml:"filters" bson:"filters"`
+
+	// BlobDir is the directory where values of attrs bigger than BlobThreshold are stored.
+	// Values in logs are replaced with their references, and an empty string means values won't be offloaded.
+	// See logit.WithBlobOffload and logit.NewFileBlobStore.
+	BlobDir string `json:"blob_dir" yaml:"blob_dir" toml:"blob_dir" bson:"blob_dir"`
+
+	// BlobThreshold is the max size of values of attrs kept in logs.
+	// You can use common words like "64KB" or "1MB", and an empty string means "64KB".
+	// Only available when BlobDir isn't empty.
+	BlobThreshold string `json:"blob_threshold" yaml:"blob_threshold" toml:"blob_threshold" bson:"blob_threshold"`
+
+	// SampleInterval is the interval of sampling records in each level.
+	// An empty string means records won't be sampled.
+	// You can use common words like "1s" or "100ms".
+	// See logit.WithSampling.
+	SampleInterval string `json:"sample_interval" yaml:"sample_interval" toml:"sample_interval" bson:"sample_interval"`
+
+	// SampleInitial is the count of records logged first in each level in every interval.
+	// Only available when SampleInterval isn't empty.
+	SampleInitial int `json:"sample_initial" yaml:"sample_initial" toml:"sample_initial" bson:"sample_initial"`
+
+	// SampleThereafter is the count of records dropped plus one after the first ones, which means every thereafter record is logged.
+	// Only available when SampleInterval isn't empty.
+	SampleThereafter int `json:"sample_thereafter" yaml:"sample_thereafter" toml:"sample_thereafter" bson:"sample_thereafter"`
+
+	// AsyncQueueSize is the size of the queue handling records in background.
+	// Zero means records are handled synchronously.
+	// See logit.WithAsync.
+	AsyncQueueSize int `json:"async_queue_size" yaml:"async_queue_size" toml:"async_queue_size" bson:"async_queue_size"`
+
+	// AsyncDropPolicy is the policy of handling records when the async queue is full.
+	// Values: "block", "oldest", "newest", and "block" is used if it's empty.
+	// See logit.DropPolicy.
+	AsyncDropPolicy string `json:"async_drop_policy" yaml:"async_drop_policy" toml:"async_drop_policy" bson:"async_drop_policy"`
+
+	// AsyncTTL is the max time records below warn level can wait in the async queue.
+	// An empty string means records never expire.
+	// You can use common words like "5m" or "60s".
+	// See logit.WithAsyncTTL.
+	AsyncTTL string `json:"async_ttl" yaml:"async_ttl" toml:"async_ttl" bson:"async_ttl"`
+
+	// Stats enables stats of records like the counts of written and dropped records.
+	// See logit.WithStats and logit.Logger.Stats.
+	Stats bool `json:"stats" yaml:"stats" toml:"stats" bson:"stats"`
+
+	// HandleTimeStats enables stats like Stats and measures the time of handling each record.
+	// See logit.WithHandleTimeStats and logit.Stats.HandleTimePercentile.
+	HandleTimeStats bool `json:"handle_time_stats" yaml:"handle_time_stats" toml:"handle_time_stats" bson:"handle_time_stats"`
+
 	// SyncTimer is the timer duration of syncing.
 	// An empty string means syncing is manual.
 	// You can use common words like "5m" or "60s".
 	// See time.Duration and time.ParseDuration.
 	SyncTimer string `json:"sync_timer" yaml:"sync_timer" toml:"sync_timer" bson:"sync_timer"`
+
+	// Include is a list of config files which will be loaded and merged before this config.
+	// It's useful for shipping platform-wide defaults in one file and overriding them in another file.
+	// Only available when loading config by LoadConfig.
+	Include []string `json:"include" yaml:"include" toml:"include" bson:"include"`
+}
+
+func (c *Config) registerLevelNames() error {
+	if len(c.LevelNames) == 0 {
+		return nil
+	}
+
+	names := make(map[slog.Level]string, len(c.LevelNames))
+	for level, name := range c.LevelNames {
+		parsed, err := logit.ParseLevel(level)
+		if err != nil {
+			return err
+		}
+
+		names[parsed] = name
+	}
+
+	return logit.RegisterLevelNames(names)
 }
 
 func (c *Config) appendLevelOptions(opts []logit.Option) ([]logit.Option, error) {
+	// Level names should be registered before parsing level, so level can be one of them.
+	if err := c.registerLevelNames(); err != nil {
+		return nil, err
+	}
+
 	if c.Level == "" {
 		return opts, nil
 	}
 
 	level := strings.ToLower(c.Level)
 
+	if level == "trace" {
+		opts = append(opts, logit.WithTraceLevel())
+		return opts, nil
+	}
+
 	if level == "debug" {
 		opts = append(opts, logit.WithDebugLevel())
 		return opts, nil
@@ -208,16 +620,83 @@ func (c *Config) appendLevelOptions(opts []logit.Option) ([]logit.Option, error)
 		return opts, nil
 	}
 
-	return nil, fmt.Errorf("logit: level %s unknown", level)
+	parsed, err := logit.ParseLevel(level)
+	if err != nil {
+		// Level may be the name of a leveler shared by loggers, like a slog.LevelVar owned by the application.
+		if leveler, ok := logit.LookupLeveler(level); ok {
+			opts = append(opts, logit.WithLeveler(leveler))
+			return opts, nil
+		}
+
+		return nil, err
+	}
+
+	opts = append(opts, logit.WithLevel(parsed))
+	return opts, nil
 }
 
 func (c *Config) appendHandlerOptions(opts []logit.Option) ([]logit.Option, error) {
+	if c.Escape != "" {
+		mode, err := handler.ParseEscapeMode(strings.ToLower(c.Escape))
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, logit.WithEscapeMode(mode))
+	}
+
 	if c.Handler == "" {
 		return opts, nil
 	}
 
-	handler := strings.ToLower(c.Handler)
-	opts = append(opts, logit.WithHandler(handler))
+	name := strings.ToLower(c.Handler)
+	if name == handler.CSV && len(c.CSVColumns) > 0 {
+		opts = append(opts, logit.WithCSVHandler(c.CSVColumns...))
+		return opts, nil
+	}
+
+	opts = append(opts, logit.WithHandler(name))
+
+	return opts, nil
+}
+
+func (c *Config) appendConsoleOptions(opts []logit.Option) ([]logit.Option, error) {
+	if len(c.ConsoleColors) > 0 {
+		colors := make(map[slog.Level]handler.Color, len(c.ConsoleColors))
+		for level, color := range c.ConsoleColors {
+			parsedLevel, err := logit.ParseLevel(level)
+			if err != nil {
+				return nil, err
+			}
+
+			parsedColor, err := handler.ParseColor(color)
+			if err != nil {
+				return nil, err
+			}
+
+			colors[parsedLevel] = parsedColor
+		}
+
+		opts = append(opts, logit.WithConsoleLevelColors(colors))
+	}
+
+	if len(c.ConsoleIcons) > 0 {
+		icons := make(map[slog.Level]string, len(c.ConsoleIcons))
+		for level, icon := range c.ConsoleIcons {
+			parsedLevel, err := logit.ParseLevel(level)
+			if err != nil {
+				return nil, err
+			}
+
+			icons[parsedLevel] = icon
+		}
+
+		opts = append(opts, logit.WithConsoleLevelIcons(icons))
+	}
+
+	if c.ConsoleLevelWidth > 0 || c.ConsoleLoggerWidth > 0 || c.ConsoleMessageWidth > 0 {
+		opts = append(opts, logit.WithConsoleColumns(c.ConsoleLevelWidth, c.ConsoleLoggerWidth, c.ConsoleMessageWidth))
+	}
 
 	return opts, nil
 }
@@ -232,15 +711,219 @@ func (c *Config) appendWriterOptions(opts []logit.Option) ([]logit.Option, error
 	return opts, nil
 }
 
+// appendWritersOptions creates writers and the error writer, so it should be the last one appending options, or writers may be leaked if others fail.
+func (c *Config) appendWritersOptions(opts []logit.Option) ([]logit.Option, error) {
+	writers := make([]io.Writer, 0, len(c.Writers)+1)
+	for _, wc := range c.Writers {
+		w, err := wc.newWriter()
+		if err != nil {
+			// Close writers created so they won't be leaked, and stdout and stderr won't be closed.
+			writer.Fanout(writers...).Close()
+			return nil, err
+		}
+
+		writers = append(writers, w)
+	}
+
+	var errorWriter io.Writer
+	if c.ErrorWriter.Target != "" {
+		w, err := c.ErrorWriter.newWriter()
+		if err != nil {
+			writer.Fanout(writers...).Close()
+			return nil, err
+		}
+
+		errorWriter = w
+	}
+
+	if len(writers) > 0 {
+		opts = append(opts, logit.WithWriters(writers...))
+	}
+
+	if errorWriter != nil {
+		opts = append(opts, logit.WithErrorWriter(errorWriter))
+	}
+
+	return opts, nil
+}
+
 func (c *Config) appendFlagOptions(opts []logit.Option) ([]logit.Option, error) {
 	if c.WithSource {
 		opts = append(opts, logit.WithSource())
 	}
 
+	if c.SourceRoot == "module" {
+		opts = append(opts, logit.WithSourceRoot(""))
+	} else if c.SourceRoot != "" {
+		opts = append(opts, logit.WithSourceRoot(c.SourceRoot))
+	}
+
+	if c.SourceSegments > 0 {
+		opts = append(opts, logit.WithSourceSegments(c.SourceSegments))
+	}
+
 	if c.WithPID {
 		opts = append(opts, logit.WithPID())
 	}
 
+	if c.Colors {
+		opts = append(opts, logit.WithColor())
+	}
+
+	if c.Stats {
+		opts = append(opts, logit.WithStats())
+	}
+
+	if c.HandleTimeStats {
+		opts = append(opts, logit.WithHandleTimeStats())
+	}
+
+	return opts, nil
+}
+
+func (c *Config) appendTimeOptions(opts []logit.Option) ([]logit.Option, error) {
+	if c.UTC {
+		opts = append(opts, logit.WithUTC())
+	}
+
+	if c.TimeFormat == "" {
+		return opts, nil
+	}
+
+	format := c.TimeFormat
+
+	// Only time formats defined by logit are case-insensitive, layouts are not.
+	switch lower := strings.ToLower(format); lower {
+	case logit.TimeFormatUnix, logit.TimeFormatUnixMilli, logit.TimeFormatUnixMicro, logit.TimeFormatUnixNano,
+		logit.TimeFormatRFC3339, logit.TimeFormatRFC3339Nano:
+		format = lower
+	}
+
+	opts = append(opts, logit.WithTimeFormat(format))
+	return opts, nil
+}
+
+func (c *Config) appendAttrOptions(opts []logit.Option) ([]logit.Option, error) {
+	if len(c.Attrs) > 0 {
+		keys := make([]string, 0, len(c.Attrs))
+		for key := range c.Attrs {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, key := range keys {
+			attrs = append(attrs, slog.String(key, c.Attrs[key]))
+		}
+
+		opts = append(opts, logit.WithStaticAttrs(attrs...))
+	}
+
+	if len(c.ContextKeys) > 0 {
+		opts = append(opts, logit.WithContextExtractor(logit.ExtractContextKeys(c.ContextKeys...)))
+	}
+
+	if len(c.Masking) > 0 {
+		opts = append(opts, logit.WithMasking(c.Masking...))
+	}
+
+	if len(c.Filters) > 0 {
+		rules := make([]logit.FilterRule, 0, len(c.Filters))
+		for _, fc := range c.Filters {
+			rule, err := fc.rule()
+			if err != nil {
+				return nil, err
+			}
+
+			rules = append(rules, rule)
+		}
+
+		opts = append(opts, logit.WithFilters(rules...))
+	}
+
+	if c.BlobDir != "" {
+		blobThreshold := c.BlobThreshold
+		if blobThreshold == "" {
+			blobThreshold = "64KB"
+		}
+
+		threshold, err := parseByteSize(blobThreshold)
+		if err != nil {
+			return nil, err
+		}
+
+		if threshold > math.MaxInt32 {
+			return nil, fmt.Errorf("logit: blob threshold %s is out of range", blobThreshold)
+		}
+
+		opts = append(opts, logit.WithBlobOffload(logit.NewFileBlobStore(c.BlobDir), int(threshold)))
+	}
+
+	if len(c.AttrTypes) == 0 {
+		return opts, nil
+	}
+
+	kinds := make(map[string]slog.Kind, len(c.AttrTypes))
+	for key, attrType := range c.AttrTypes {
+		switch strings.ToLower(attrType) {
+		case "string":
+			kinds[key] = slog.KindString
+		case "int":
+			kinds[key] = slog.KindInt64
+		case "float":
+			kinds[key] = slog.KindFloat64
+		case "bool":
+			kinds[key] = slog.KindBool
+		default:
+			return nil, fmt.Errorf("logit: attr %s has an unknown type %s", key, attrType)
+		}
+	}
+
+	opts = append(opts, logit.WithCoercedAttrs(kinds))
+	return opts, nil
+}
+
+func (c *Config) appendSampleOptions(opts []logit.Option) ([]logit.Option, error) {
+	if c.SampleInterval == "" {
+		return opts, nil
+	}
+
+	interval, err := parseTimeDuration(c.SampleInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, logit.WithSampling(c.SampleInitial, c.SampleThereafter, interval))
+	return opts, nil
+}
+
+func (c *Config) appendAsyncOptions(opts []logit.Option) ([]logit.Option, error) {
+	if c.AsyncQueueSize <= 0 {
+		return opts, nil
+	}
+
+	policy := logit.DropPolicyBlock
+	if c.AsyncDropPolicy != "" {
+		parsed, err := logit.ParseDropPolicy(strings.ToLower(c.AsyncDropPolicy))
+		if err != nil {
+			return nil, err
+		}
+
+		policy = parsed
+	}
+
+	opts = append(opts, logit.WithAsync(c.AsyncQueueSize, policy))
+
+	if c.AsyncTTL != "" {
+		ttl, err := parseTimeDuration(c.AsyncTTL)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, logit.WithAsyncTTL(ttl))
+	}
+
 	return opts, nil
 }
 
@@ -264,7 +947,9 @@ func (c *Config) Options() (opts []logit.Option, err error) {
 	opts = make([]logit.Option, 0, 4)
 
 	appendFuncs := []func(opts []logit.Option) ([]logit.Option, error){
-		c.appendLevelOptions, c.appendHandlerOptions, c.appendWriterOptions, c.appendFlagOptions, c.appendSyncOptions,
+		c.appendLevelOptions, c.appendHandlerOptions, c.appendConsoleOptions, c.appendWriterOptions, c.appendFlagOptions,
+		c.appendTimeOptions, c.appendAttrOptions, c.appendSampleOptions, c.appendAsyncOptions,
+		c.appendSyncOptions, c.appendWritersOptions,
 	}
 
 	for _, append := range appendFuncs {
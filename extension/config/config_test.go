@@ -15,14 +15,20 @@
 package config
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/FishGoddess/logit"
 	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/handler"
 )
 
 func removeTimeAndSource(str string) string {
@@ -55,13 +61,14 @@ func TestConfig(t *testing.T) {
 		Level:   "debug",
 		Handler: "text",
 		Writer: WriterConfig{
-			Target:         logitFile,
-			FileRotate:     true,
-			FileMaxSize:    "1GB",
-			FileMaxAge:     "7d",
-			FileMaxBackups: 30,
-			BufferSize:     "64KB",
-			BatchSize:      16,
+			Target:           logitFile,
+			FileRotate:       true,
+			FileMaxSize:      "1GB",
+			FileMaxAge:       "7d",
+			FileMaxBackups:   30,
+			FileMaxTotalSize: "10GB",
+			BufferSize:       "64KB",
+			BatchSize:        16,
 		},
 		WithSource: true,
 		WithPID:    true,
@@ -112,3 +119,888 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("got %s != want %s", got, want)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigLevel$
+func TestConfigLevel(t *testing.T) {
+	conf := Config{Level: "trace"}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	if !logger.TraceEnabled() {
+		t.Fatal("logger disabled trace")
+	}
+
+	if err = logit.RegisterLevel(slog.LevelWarn+2, "audit"); err != nil {
+		t.Fatal(err)
+	}
+
+	conf = Config{Level: "AUDIT"}
+
+	opts, err = conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger = logit.NewLogger(opts...)
+	if logger.WarnEnabled() || !logger.ErrorEnabled() {
+		t.Fatal("logger level is wrong")
+	}
+
+	conf = Config{Level: "unknown"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parsing unknown level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigLeveler$
+func TestConfigLeveler(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slog.LevelError)
+
+	if err := logit.RegisterLeveler("TestConfigLeveler", levelVar); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := Config{Level: "TestConfigLeveler"}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger1 := logit.NewLogger(opts...)
+	logger2 := logit.NewLogger(opts...)
+
+	for _, logger := range []*logit.Logger{logger1, logger2} {
+		if logger.WarnEnabled() || !logger.ErrorEnabled() {
+			t.Fatal("logger level is wrong")
+		}
+	}
+
+	levelVar.Set(slog.LevelInfo)
+
+	for _, logger := range []*logit.Logger{logger1, logger2} {
+		if logger.DebugEnabled() || !logger.InfoEnabled() {
+			t.Fatal("logger level is wrong")
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigLevelNames$
+func TestConfigLevelNames(t *testing.T) {
+	defer logit.RegisterLevelNames(map[slog.Level]string{slog.LevelWarn: "WARN", slog.LevelError: "ERROR"})
+
+	conf := Config{Level: "Warning", Handler: "json", LevelNames: map[string]string{"warn": "WARNING", "error": "error"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	if logger.InfoEnabled() || !logger.WarnEnabled() {
+		t.Fatal("logger level is wrong")
+	}
+
+	logger.Warn("msg")
+	logger.Error("msg")
+
+	got := buffer.String()
+	if !strings.Contains(got, `"level":"WARNING"`) || !strings.Contains(got, `"level":"error"`) {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = Config{LevelNames: map[string]string{"unknown": "UNKNOWN"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown level in level names should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigSource$
+func TestConfigSource(t *testing.T) {
+	confs := map[string]Config{
+		"source=extension/config/config_test.go:": {WithSource: true, SourceRoot: "module"},
+		"source=config_test.go:":                  {WithSource: true, SourceSegments: 1},
+	}
+
+	for want, conf := range confs {
+		opts, err := conf.Options()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		opts = append(opts, logit.WithWriter(buffer))
+
+		logit.NewLogger(opts...).Info("msg")
+
+		if got := buffer.String(); !strings.Contains(got, want) {
+			t.Fatalf("got %s doesn't contain %s", got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigTimeFormat$
+func TestConfigTimeFormat(t *testing.T) {
+	formats := map[string]string{
+		"UNIX_MS":    `{"time":1700000000000,`,
+		"rfc3339":    `{"time":"2023-11-14T22:13:20Z",`,
+		"2006/01/02": `{"time":"2023/11/14",`,
+	}
+
+	for format, want := range formats {
+		conf := Config{Handler: "json", TimeFormat: format}
+
+		opts, err := conf.Options()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		opts = append(opts, logit.WithWriter(buffer))
+
+		record := slog.NewRecord(time.UnixMilli(1700000000000).UTC(), slog.LevelInfo, "msg", 0)
+		logit.NewLogger(opts...).Slog().Handler().Handle(context.Background(), record)
+
+		if got := buffer.String(); !strings.HasPrefix(got, want) {
+			t.Fatalf("format %s got %s is wrong", format, got)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigUTC$
+func TestConfigUTC(t *testing.T) {
+	conf := Config{Handler: "json", TimeFormat: "rfc3339", UTC: true}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	now := time.UnixMilli(1700000000000).In(time.FixedZone("CST", 8*60*60))
+	record := slog.NewRecord(now, slog.LevelInfo, "msg", 0)
+	logit.NewLogger(opts...).Slog().Handler().Handle(context.Background(), record)
+
+	if got := buffer.String(); !strings.HasPrefix(got, `{"time":"2023-11-14T22:13:20Z",`) {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigAttrTypes$
+func TestConfigAttrTypes(t *testing.T) {
+	conf := Config{Handler: "json", AttrTypes: map[string]string{"status": "INT", "ok": "bool"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "status", "200", "ok", "true")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"status":200,"ok":true}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = Config{AttrTypes: map[string]string{"status": "time"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown attr type should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigMasking$
+func TestConfigMasking(t *testing.T) {
+	conf := Config{Handler: "json", Masking: []string{"password", `\d{16}`}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "password", "123456", "card", "1234567812345678")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"password":"***","card":"***"}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigAttrs$
+func TestConfigAttrs(t *testing.T) {
+	conf := Config{Handler: "json", Attrs: map[string]string{"region": "cn", "env": "prod"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "key", "value")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"msg":"msg","env":"prod","region":"cn","key":"value"}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigContextKeys$
+func TestConfigContextKeys(t *testing.T) {
+	conf := Config{Handler: "json", ContextKeys: []string{"request_id", "tenant_id"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	ctx := context.WithValue(context.Background(), logit.ContextKey("tenant_id"), "t1")
+	logit.NewLogger(opts...).Slog().InfoContext(ctx, "msg")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"msg":"msg","tenant_id":"t1"}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigSampling$
+func TestConfigSampling(t *testing.T) {
+	conf := Config{SampleInterval: "1m", SampleInitial: 2}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	for i := 0; i < 10; i++ {
+		logger.Debug("msg")
+	}
+
+	if got := strings.Count(buffer.String(), "msg"); got != 2 {
+		t.Fatalf("got %d != want 2", got)
+	}
+
+	conf = Config{SampleInterval: "1x"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("invalid sample interval should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigAsync$
+func TestConfigAsync(t *testing.T) {
+	conf := Config{AsyncQueueSize: 16, AsyncDropPolicy: "Newest"}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("msg")
+	logger.Close()
+
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ msg\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = Config{AsyncQueueSize: 16, AsyncDropPolicy: "all"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown drop policy should return an error")
+	}
+
+	conf = Config{AsyncQueueSize: 16, AsyncTTL: "10m"}
+	if opts, err = conf.Options(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 2 {
+		t.Fatalf("len(opts) %d != 2", len(opts))
+	}
+
+	conf = Config{AsyncQueueSize: 16, AsyncTTL: "1x"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("invalid async ttl should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigStats$
+func TestConfigStats(t *testing.T) {
+	conf := Config{Stats: true}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts = append(opts, logit.WithWriter(bytes.NewBuffer(nil)))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("msg")
+	logger.Close()
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if stats.Written != 1 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigWriters$
+func TestConfigWriters(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logit.log")
+	rotatePath := filepath.Join(dir, "rotate", "logit.log")
+
+	conf := Config{
+		Handler: "text",
+		Writers: []WriterConfig{
+			{Target: path, BufferSize: "4KB"},
+			{Target: rotatePath, FileRotate: true, BatchSize: 16},
+		},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("msg", "key", "value")
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{path, rotatePath} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(data), "msg=msg key=value") {
+			t.Fatalf("data %q of %s is wrong", data, p)
+		}
+	}
+
+	conf = Config{Writers: []WriterConfig{{Target: "stdout"}, {Target: "journal"}}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("options of journal in writers should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigErrorWriter$
+func TestConfigErrorWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logit.log")
+	errorPath := filepath.Join(dir, "error", "logit.log")
+
+	conf := Config{
+		Handler:     "text",
+		Writer:      WriterConfig{Target: path},
+		ErrorWriter: WriterConfig{Target: errorPath},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("info")
+	logger.Error("error")
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(data); !strings.Contains(got, "msg=info") || strings.Contains(got, "msg=error") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	data, err = os.ReadFile(errorPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(data); !strings.Contains(got, "msg=error") || strings.Contains(got, "msg=info") {
+		t.Fatalf("got %q of error writer is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigFileLevels$
+func TestConfigFileLevels(t *testing.T) {
+	dir := t.TempDir()
+
+	conf := Config{
+		Handler: "text",
+		Writer:  WriterConfig{Target: filepath.Join(dir, "app.log"), FileLevels: true, FileMaxBackups: 3},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("info")
+	logger.Error("error")
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{"app.info.log": "msg=info", "app.error.log": "msg=error"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := string(data); strings.Count(got, "\n") != 1 || !strings.Contains(got, want) {
+			t.Fatalf("got %q of %s is wrong", got, name)
+		}
+	}
+
+	conf = Config{Writers: []WriterConfig{{Target: filepath.Join(dir, "app.log"), FileLevels: true}}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("options of file levels in writers should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigFilters$
+func TestConfigFilters(t *testing.T) {
+	conf := Config{
+		Handler: "text",
+		Filters: []FilterConfig{
+			{Attrs: map[string]string{"component": "grpc"}},
+			{Message: "^health", MaxLevel: "warn"},
+		},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	logger.With("component", "grpc").Info("muted")
+	logger.With("component", "grpc").Warn("kept")
+	logger.Warn("health muted")
+	logger.Error("health kept")
+
+	if got := buffer.String(); strings.Contains(got, "muted") || !strings.Contains(got, "msg=kept") || !strings.Contains(got, `msg="health kept"`) {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = Config{Filters: []FilterConfig{{MaxLevel: "unknown"}}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("options of filters with unknown level should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigBlobDir$
+func TestConfigBlobDir(t *testing.T) {
+	dir := t.TempDir()
+	conf := Config{Handler: "text", BlobDir: dir, BlobThreshold: "1KB"}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	big := strings.Repeat("x", 2048)
+	logit.NewLogger(opts...).Info("msg", "big", big, "small", "value")
+
+	if got := buffer.String(); strings.Contains(got, big) || !strings.Contains(got, "big.blob_ref="+dir) || !strings.Contains(got, "small=value") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) %d != 1", len(entries))
+	}
+
+	conf = Config{BlobDir: dir, BlobThreshold: "4GB"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("options of blob threshold out of range should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigHandleTimeStats$
+func TestConfigHandleTimeStats(t *testing.T) {
+	conf := Config{HandleTimeStats: true}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts = append(opts, logit.WithWriter(bytes.NewBuffer(nil)))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("msg")
+	logger.Close()
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if stats.Written != 1 || stats.HandleTime <= 0 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
+
+func TestConfigCSVColumns(t *testing.T) {
+	conf := Config{Handler: "CSV", CSVColumns: []string{"level", "msg", "user_id"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "user_id", 123)
+
+	if got := buffer.String(); got != "level,msg,user_id\nINFO,msg,123\n" {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigColors$
+func TestConfigColors(t *testing.T) {
+	conf := Config{Colors: true}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "user_id", 123)
+
+	// Buffer isn't a terminal so logs shouldn't be colored.
+	if got := buffer.String(); !strings.HasSuffix(got, "INFO ¦ msg ¦ user_id=123\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigConsoleColors$
+func TestConfigConsoleColors(t *testing.T) {
+	conf := Config{Handler: "console", ConsoleColors: map[string]string{"info": "28", "warn": "#af5f00"}, ConsoleIcons: map[string]string{"error": "❌"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("info")
+	logger.Error("error")
+
+	// Buffer isn't a terminal so logs shouldn't be colored, but icons are still written.
+	if got := buffer.String(); !strings.Contains(got, " INFO  info\n") || !strings.HasSuffix(got, " ❌ ERROR error\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	conf = Config{ConsoleColors: map[string]string{"info": "pink"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown color should return an error")
+	}
+
+	conf = Config{ConsoleIcons: map[string]string{"unknown": "❓"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown level should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigConsoleColumns$
+func TestConfigConsoleColumns(t *testing.T) {
+	conf := Config{Handler: "console", ConsoleLevelWidth: 4, ConsoleLoggerWidth: 4, ConsoleMessageWidth: 8}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Named("db").Warn("pool is full", "size", 100)
+
+	if got := buffer.String(); !strings.Contains(got, " WARN db   pool is\n") || !strings.HasSuffix(got, " full     size=100\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigEscape$
+func TestConfigEscape(t *testing.T) {
+	conf := Config{Escape: "None"}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logit.NewLogger(opts...).Info("msg", "sql", "a\tb")
+
+	if got := buffer.String(); !strings.HasSuffix(got, "INFO ¦ msg ¦ sql=a\tb\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	conf = Config{Escape: "unknown"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown escape mode should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigFileTimeZone$
+func TestConfigFileTimeZone(t *testing.T) {
+	currentTime := defaults.CurrentTime
+	defer func() {
+		defaults.CurrentTime = currentTime
+	}()
+
+	defaults.CurrentTime = func() time.Time {
+		return time.Unix(1, 0).In(time.FixedZone("CST", 8*60*60))
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	conf := Config{
+		Handler: "json",
+		Writer: WriterConfig{
+			Target:         path,
+			FileRotate:     true,
+			FileMaxSize:    "1B",
+			FileTimeFormat: "2006-01-02T15-04-05",
+			FileTimeZone:   "UTC",
+		},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("msg")
+	logger.Info("msg")
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := filepath.Join(dir, "test.1970-01-01T00-00-01.log")
+	if _, err = os.Stat(backup); err != nil {
+		t.Fatal(err)
+	}
+
+	conf.Writer.FileTimeZone = "Unknown/Zone"
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("unknown time zone should return an error")
+	}
+
+	conf.Writer.FileTimeZone = ""
+	conf.Writer.FileMaxTotalSize = "1XB"
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("wrong max total size should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigWriterStats$
+func TestConfigWriterStats(t *testing.T) {
+	conf := Config{Writer: WriterConfig{BatchSize: 16, Stats: true}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append([]logit.Option{logit.WithWriter(buffer)}, opts...)
+
+	logger := logit.NewLogger(opts...)
+	defer logger.Close()
+
+	logger.Info("msg")
+
+	if stats, ok := logger.WriteStats(); !ok || stats.Writes != 1 {
+		t.Fatalf("stats %+v or ok %+v is wrong", stats, ok)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigBatchParallelism$
+func TestConfigBatchParallelism(t *testing.T) {
+	conf := Config{Writer: WriterConfig{BatchSize: 2, BatchParallelism: 4, AtomicSize: "4KB"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append([]logit.Option{logit.WithWriter(buffer)}, opts...)
+
+	logger := logit.NewLogger(opts...)
+	for i := 0; i < 9; i++ {
+		logger.Info("msg")
+	}
+
+	logger.Close()
+
+	if got := strings.Count(buffer.String(), "¦ msg\n"); got != 9 {
+		t.Fatalf("got %d != 9", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigAtomicSize$
+func TestConfigAtomicSize(t *testing.T) {
+	conf := Config{Writer: WriterConfig{AtomicSize: "4KB"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append([]logit.Option{logit.WithWriter(buffer)}, opts...)
+	logit.NewLogger(opts...).Info("msg")
+
+	if got := buffer.String(); !strings.HasSuffix(got, "INFO ¦ msg\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	for _, atomicSize := range []string{"0B", "1XB"} {
+		conf = Config{Writer: WriterConfig{AtomicSize: atomicSize}}
+		if _, err = conf.Options(); err == nil {
+			t.Fatalf("atomic size %s should return an error", atomicSize)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigJournal$
+func TestConfigJournal(t *testing.T) {
+	journalSocket := defaults.JournalSocket
+	defer func() {
+		defaults.JournalSocket = journalSocket
+	}()
+
+	defaults.JournalSocket = filepath.Join(t.TempDir(), "journal.socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: defaults.JournalSocket, Net: "unixgram"})
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer conn.Close()
+
+	conf := Config{Writer: WriterConfig{Target: "journal"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	defer logger.Close()
+
+	logger.Info("msg")
+
+	datagram := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	n, err := conn.Read(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(datagram[:n]); !strings.HasPrefix(got, "MESSAGE=msg\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -run=^$ -fuzz=^FuzzConfig$ -fuzztime=10s
+func FuzzConfig(f *testing.F) {
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`{"level":"debug","handler":"json","with_source":true,"with_pid":true,"sync_timer":"1s"}`))
+	f.Add([]byte(`{"level":"INFO+2","writer":{"target":"stderr","buffer_size":"4KB"}}`))
+	f.Add([]byte(`{"writer":{"target":"logit.log","file_rotate":true,"file_max_size":"1MB","file_max_age":"7d","file_max_backups":3}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Options registers level names globally, so restore them or fuzzing inputs will leak into other tests.
+		names := handler.LevelNames()
+		defer handler.SetLevelNames(names)
+
+		conf := new(Config)
+		if err := json.Unmarshal(data, conf); err != nil {
+			return
+		}
+
+		_, err := conf.Options()
+
+		marshaled, marshalErr := json.Marshal(conf)
+		if marshalErr != nil {
+			t.Fatal(marshalErr)
+		}
+
+		unmarshaled := new(Config)
+		if unmarshalErr := json.Unmarshal(marshaled, unmarshaled); unmarshalErr != nil {
+			t.Fatal(unmarshalErr)
+		}
+
+		if _, gotErr := unmarshaled.Options(); (gotErr == nil) != (err == nil) {
+			t.Fatalf("config %s got error %v but marshaled config got error %v", data, err, gotErr)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+const (
+	exampleYaml = "yaml"
+	exampleJson = "json"
+	exampleToml = "toml"
+)
+
+var (
+	// configSource is the source of this package's config, and the doc comments of fields are used as the comments of examples.
+	//go:embed config.go
+	configSource string
+
+	fieldComments     map[string][]string
+	fieldCommentsErr  error
+	fieldCommentsOnce sync.Once
+)
+
+// DefaultConfig returns a config having the same defaults as logit, which is a good start of writing examples.
+// See Config.WriteExample.
+func DefaultConfig() *Config {
+	conf := &Config{
+		Level:   "debug",
+		Handler: handler.Tape,
+		Writer: WriterConfig{
+			Target: "stdout",
+		},
+	}
+
+	return conf
+}
+
+// parseFieldComments parses the doc comments of fields in config structs from configSource.
+// The keys of comments are like "Config.Level" and "WriterConfig.Target".
+func parseFieldComments() (map[string][]string, error) {
+	fieldCommentsOnce.Do(func() {
+		file, err := parser.ParseFile(token.NewFileSet(), "config.go", configSource, parser.ParseComments)
+		if err != nil {
+			fieldCommentsErr = err
+			return
+		}
+
+		fieldComments = make(map[string][]string, 64)
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			structType, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+
+			for _, field := range structType.Fields.List {
+				if field.Doc == nil {
+					continue
+				}
+
+				lines := strings.Split(strings.TrimSpace(field.Doc.Text()), "\n")
+				for _, name := range field.Names {
+					fieldComments[spec.Name.Name+"."+name.Name] = lines
+				}
+			}
+
+			return false
+		})
+	})
+
+	return fieldComments, fieldCommentsErr
+}
+
+type exampleField struct {
+	key      string
+	value    reflect.Value
+	comments []string
+}
+
+// exampleFields returns the fields of value which is a config struct with their keys in format and comments.
+func exampleFields(value reflect.Value, format string, comments map[string][]string) []exampleField {
+	valueType := value.Type()
+
+	fields := make([]exampleField, 0, valueType.NumField())
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+
+		key, _, _ := strings.Cut(structField.Tag.Get(format), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		fieldComments := comments[valueType.Name()+"."+structField.Name]
+		if valueType == reflect.TypeOf(Config{}) && structField.Name == "Handler" {
+			registered := "Registered: " + strings.Join(handler.Names(), ", ") + "."
+			fieldComments = append(slices.Clip(fieldComments), registered)
+		}
+
+		fields = append(fields, exampleField{key: key, value: value.Field(i), comments: fieldComments})
+	}
+
+	return fields
+}
+
+// encodeExampleValue encodes value which isn't a struct in format.
+// Strings are quoted like json, which is also valid in yaml and toml.
+func encodeExampleValue(value reflect.Value, format string) (string, error) {
+	switch value.Kind() {
+	case reflect.Slice:
+		elems := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elem, err := encodeExampleValue(value.Index(i), format)
+			if err != nil {
+				return "", err
+			}
+
+			elems = append(elems, elem)
+		}
+
+		return "[" + strings.Join(elems, ", ") + "]", nil
+	case reflect.Map:
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+
+		slices.Sort(keys)
+
+		separator := ": "
+		if format == exampleToml {
+			separator = " = "
+		}
+
+		elems := make([]string, 0, len(keys))
+		for _, key := range keys {
+			elem, err := encodeExampleValue(value.MapIndex(reflect.ValueOf(key)), format)
+			if err != nil {
+				return "", err
+			}
+
+			quotedKey, err := json.Marshal(key)
+			if err != nil {
+				return "", err
+			}
+
+			elems = append(elems, string(quotedKey)+separator+elem)
+		}
+
+		return "{" + strings.Join(elems, ", ") + "}", nil
+	default:
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return "", err
+		}
+
+		return string(encoded), nil
+	}
+}
+
+func writeExampleComments(builder *strings.Builder, indent string, comments []string) {
+	for _, comment := range comments {
+		builder.WriteString(strings.TrimRight(indent+"# "+comment, " ") + "\n")
+	}
+}
+
+func writeYamlExample(builder *strings.Builder, value reflect.Value, indent string, comments map[string][]string) error {
+	for i, field := range exampleFields(value, exampleYaml, comments) {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		writeExampleComments(builder, indent, field.comments)
+
+		if field.value.Kind() == reflect.Struct {
+			builder.WriteString(indent + field.key + ":\n")
+
+			if err := writeYamlExample(builder, field.value, indent+"  ", comments); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		encoded, err := encodeExampleValue(field.value, exampleYaml)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(indent + field.key + ": " + encoded + "\n")
+	}
+
+	return nil
+}
+
+func writeJsonExample(builder *strings.Builder, value reflect.Value, indent string, comments map[string][]string) error {
+	builder.WriteString("{\n")
+
+	fields := exampleFields(value, exampleJson, comments)
+	for i, field := range fields {
+		builder.WriteString(indent + "  \"" + field.key + "\": ")
+
+		if field.value.Kind() == reflect.Struct {
+			if err := writeJsonExample(builder, field.value, indent+"  ", comments); err != nil {
+				return err
+			}
+		} else {
+			encoded, err := encodeExampleValue(field.value, exampleJson)
+			if err != nil {
+				return err
+			}
+
+			builder.WriteString(encoded)
+		}
+
+		if i < len(fields)-1 {
+			builder.WriteString(",")
+		}
+
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(indent + "}")
+	return nil
+}
+
+func writeTomlExample(builder *strings.Builder, value reflect.Value, table string, comments map[string][]string) error {
+	fields := exampleFields(value, exampleToml, comments)
+
+	// Keys after a table header belong to the table in toml, so tables are written after all keys.
+	var tables []exampleField
+	for i, field := range fields {
+		if field.value.Kind() == reflect.Struct {
+			tables = append(tables, field)
+			continue
+		}
+
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		writeExampleComments(builder, "", field.comments)
+
+		encoded, err := encodeExampleValue(field.value, exampleToml)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(field.key + " = " + encoded + "\n")
+	}
+
+	for _, field := range tables {
+		key := field.key
+		if table != "" {
+			key = table + "." + key
+		}
+
+		builder.WriteString("\n")
+		writeExampleComments(builder, "", field.comments)
+		builder.WriteString("[" + key + "]\n")
+
+		if err := writeTomlExample(builder, field.value, key, comments); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteExample writes config as an example config file in format to writer.
+// Values: "yaml", "json", "toml".
+// All fields are written with their doc comments in yaml and toml, so the example is a reference of all fields,
+// and the comment of Handler lists all handlers registered, including the ones registered by users.
+// Notice that json doesn't support comments, so the example in json only has fields.
+// Use DefaultConfig to write an example having the defaults of logit.
+func (c *Config) WriteExample(writer io.Writer, format string) error {
+	comments, err := parseFieldComments()
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+	value := reflect.ValueOf(*c)
+
+	switch strings.ToLower(format) {
+	case exampleYaml:
+		err = writeYamlExample(&builder, value, "", comments)
+	case exampleJson:
+		err = writeJsonExample(&builder, value, "", comments)
+		builder.WriteString("\n")
+	case exampleToml:
+		err = writeTomlExample(&builder, value, "", comments)
+	default:
+		err = fmt.Errorf("logit: example format %s unknown", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, builder.String())
+	return err
+}
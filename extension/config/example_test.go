@@ -0,0 +1,127 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseFieldComments$
+func TestParseFieldComments(t *testing.T) {
+	comments, err := parseFieldComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range []any{Config{}, WriterConfig{}} {
+		valueType := reflect.TypeOf(value)
+
+		for i := 0; i < valueType.NumField(); i++ {
+			key := valueType.Name() + "." + valueType.Field(i).Name
+			if len(comments[key]) == 0 {
+				t.Fatalf("comments of %s not found", key)
+			}
+		}
+	}
+
+	if got := comments["Config.Level"][0]; got != "Level is the level of logger." {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigWriteExample$
+func TestConfigWriteExample(t *testing.T) {
+	conf := DefaultConfig()
+	conf.LevelNames = map[string]string{"warn": "WARNING", "info": "info"}
+	conf.Masking = []string{"password", `\d{16}`}
+	conf.Writer.BatchSize = 16
+
+	wants := map[string][]string{
+		"yaml": {
+			"# Also, you can use the levels registered by logit.RegisterLevel.\nlevel: \"debug\"\n",
+			"level_names: {\"info\": \"info\", \"warn\": \"WARNING\"}\n",
+			"# Registered: auto, console, csv,",
+			"masking: [\"password\", \"\\\\d{16}\"]\n",
+			"writer:\n  # Target is where the writer writes logs.\n",
+			"  target: \"stdout\"\n",
+			"  batch_size: 16\n",
+		},
+		"toml": {
+			"# Also, you can use the levels registered by logit.RegisterLevel.\nlevel = \"debug\"\n",
+			"level_names = {\"info\" = \"info\", \"warn\" = \"WARNING\"}\n",
+			"# Registered: auto, console, csv,",
+			"masking = [\"password\", \"\\\\d{16}\"]\n",
+			"# Writer is the config of writer.\n[writer]\n",
+			"target = \"stdout\"\n",
+			"batch_size = 16\n",
+		},
+	}
+
+	for format, want := range wants {
+		buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+		if err := conf.WriteExample(buffer, format); err != nil {
+			t.Fatal(err)
+		}
+
+		got := buffer.String()
+		for _, line := range want {
+			if !strings.Contains(got, line) {
+				t.Fatalf("format %s: got %s doesn't contain %s", format, got, line)
+			}
+		}
+	}
+
+	// Keys after the writer table belong to the table in toml, so the table should be the last.
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	if err := conf.WriteExample(buffer, "TOML"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); strings.Index(got, "[writer]") < strings.Index(got, "include = ") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	if err := conf.WriteExample(buffer, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := new(Config)
+	if err := json.Unmarshal(buffer.Bytes(), decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Level != conf.Level || decoded.LevelNames["warn"] != "WARNING" || decoded.Writer.BatchSize != 16 {
+		t.Fatalf("decoded %+v is wrong", decoded)
+	}
+
+	// Nil slices and maps are written as empty ones, so compare the examples of decoded and conf.
+	reencoded := bytes.NewBuffer(make([]byte, 0, 4096))
+	if err := decoded.WriteExample(reencoded, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if reencoded.String() != buffer.String() {
+		t.Fatalf("reencoded %s != buffer %s", reencoded.String(), buffer.String())
+	}
+
+	if err := conf.WriteExample(buffer, "ini"); err == nil {
+		t.Fatal("unknown format should return an error")
+	}
+}
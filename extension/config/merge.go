@@ -0,0 +1,206 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UnmarshalFunc unmarshals data to v, like json.Unmarshal or yaml.Unmarshal.
+type UnmarshalFunc func(data []byte, v any) error
+
+func mergeString(base string, override string) string {
+	if override != "" {
+		return override
+	}
+
+	return base
+}
+
+func mergeInt(base int, override int) int {
+	if override > 0 {
+		return override
+	}
+
+	return base
+}
+
+func mergeStrings(base []string, override []string) []string {
+	if len(override) > 0 {
+		return override
+	}
+
+	return base
+}
+
+func mergeStringMap(base map[string]string, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range override {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func mergeWriterConfig(base WriterConfig, override WriterConfig) WriterConfig {
+	merged := base
+	merged.Target = mergeString(base.Target, override.Target)
+	merged.FileRotate = base.FileRotate || override.FileRotate
+	merged.FileLevels = base.FileLevels || override.FileLevels
+	merged.Stats = base.Stats || override.Stats
+	merged.FileMaxSize = mergeString(base.FileMaxSize, override.FileMaxSize)
+	merged.FileMaxAge = mergeString(base.FileMaxAge, override.FileMaxAge)
+	merged.FileMaxTotalSize = mergeString(base.FileMaxTotalSize, override.FileMaxTotalSize)
+	merged.FileTimeFormat = mergeString(base.FileTimeFormat, override.FileTimeFormat)
+	merged.FileTimeZone = mergeString(base.FileTimeZone, override.FileTimeZone)
+	merged.AtomicSize = mergeString(base.AtomicSize, override.AtomicSize)
+	merged.BufferSize = mergeString(base.BufferSize, override.BufferSize)
+
+	if override.FileMaxBackups > 0 {
+		merged.FileMaxBackups = override.FileMaxBackups
+	}
+
+	if override.FileRetainAtLeast > 0 {
+		merged.FileRetainAtLeast = override.FileRetainAtLeast
+	}
+
+	if override.BatchSize > 0 {
+		merged.BatchSize = override.BatchSize
+	}
+
+	merged.BatchParallelism = mergeInt(base.BatchParallelism, override.BatchParallelism)
+
+	return merged
+}
+
+// MergeConfig merges override to base and returns a new config.
+// Fields having zero values in override won't replace the fields in base,
+// which means a bool field can't be turned off by override.
+// Both base and override won't be modified.
+func MergeConfig(base *Config, override *Config) *Config {
+	merged := new(Config)
+	if base != nil {
+		*merged = *base
+	}
+
+	if override == nil {
+		return merged
+	}
+
+	merged.Level = mergeString(merged.Level, override.Level)
+	merged.LevelNames = mergeStringMap(merged.LevelNames, override.LevelNames)
+	merged.Handler = mergeString(merged.Handler, override.Handler)
+	merged.CSVColumns = mergeStrings(merged.CSVColumns, override.CSVColumns)
+	merged.ConsoleColors = mergeStringMap(merged.ConsoleColors, override.ConsoleColors)
+	merged.ConsoleIcons = mergeStringMap(merged.ConsoleIcons, override.ConsoleIcons)
+	merged.ConsoleLevelWidth = mergeInt(merged.ConsoleLevelWidth, override.ConsoleLevelWidth)
+	merged.ConsoleLoggerWidth = mergeInt(merged.ConsoleLoggerWidth, override.ConsoleLoggerWidth)
+	merged.ConsoleMessageWidth = mergeInt(merged.ConsoleMessageWidth, override.ConsoleMessageWidth)
+	merged.Writer = mergeWriterConfig(merged.Writer, override.Writer)
+	merged.ErrorWriter = mergeWriterConfig(merged.ErrorWriter, override.ErrorWriter)
+	merged.WithSource = merged.WithSource || override.WithSource
+	merged.SourceRoot = mergeString(merged.SourceRoot, override.SourceRoot)
+	merged.SourceSegments = mergeInt(merged.SourceSegments, override.SourceSegments)
+	merged.WithPID = merged.WithPID || override.WithPID
+	merged.Colors = merged.Colors || override.Colors
+	merged.Escape = mergeString(merged.Escape, override.Escape)
+	merged.TimeFormat = mergeString(merged.TimeFormat, override.TimeFormat)
+	merged.UTC = merged.UTC || override.UTC
+	merged.Attrs = mergeStringMap(merged.Attrs, override.Attrs)
+	merged.ContextKeys = mergeStrings(merged.ContextKeys, override.ContextKeys)
+	merged.AttrTypes = mergeStringMap(merged.AttrTypes, override.AttrTypes)
+	merged.Masking = mergeStrings(merged.Masking, override.Masking)
+	merged.BlobDir = mergeString(merged.BlobDir, override.BlobDir)
+	merged.BlobThreshold = mergeString(merged.BlobThreshold, override.BlobThreshold)
+	merged.SampleInterval = mergeString(merged.SampleInterval, override.SampleInterval)
+	merged.SampleInitial = mergeInt(merged.SampleInitial, override.SampleInitial)
+	merged.SampleThereafter = mergeInt(merged.SampleThereafter, override.SampleThereafter)
+	merged.AsyncQueueSize = mergeInt(merged.AsyncQueueSize, override.AsyncQueueSize)
+	merged.AsyncDropPolicy = mergeString(merged.AsyncDropPolicy, override.AsyncDropPolicy)
+	merged.AsyncTTL = mergeString(merged.AsyncTTL, override.AsyncTTL)
+	merged.Stats = merged.Stats || override.Stats
+	merged.HandleTimeStats = merged.HandleTimeStats || override.HandleTimeStats
+	merged.SyncTimer = mergeString(merged.SyncTimer, override.SyncTimer)
+	merged.Include = nil
+
+	if len(override.Writers) > 0 {
+		merged.Writers = override.Writers
+	}
+
+	if len(override.Filters) > 0 {
+		merged.Filters = override.Filters
+	}
+
+	return merged
+}
+
+func loadConfig(path string, unmarshal UnmarshalFunc, loading map[string]struct{}) (*Config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := loading[absPath]; ok {
+		return nil, fmt.Errorf("logit: config %s is included circularly", path)
+	}
+
+	loading[absPath] = struct{}{}
+	defer delete(loading, absPath)
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := new(Config)
+	if err = unmarshal(data, conf); err != nil {
+		return nil, err
+	}
+
+	merged := new(Config)
+	for _, include := range conf.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(absPath), include)
+		}
+
+		included, err := loadConfig(include, unmarshal, loading)
+		if err != nil {
+			return nil, err
+		}
+
+		merged = MergeConfig(merged, included)
+	}
+
+	return MergeConfig(merged, conf), nil
+}
+
+// LoadConfig loads a config from path and unmarshals it with unmarshal.
+// All configs in Include will be loaded and merged in order before the config in path,
+// so the config in path overrides all configs it includes.
+// Relative paths in Include are relative to the directory of the config including them.
+func LoadConfig(path string, unmarshal UnmarshalFunc) (*Config, error) {
+	loading := make(map[string]struct{}, 4)
+	return loadConfig(path, unmarshal, loading)
+}
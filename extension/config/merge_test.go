@@ -0,0 +1,226 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMergeConfig$
+func TestMergeConfig(t *testing.T) {
+	base := &Config{
+		Level:              "info",
+		LevelNames:         map[string]string{"warn": "WARNING"},
+		Handler:            "json",
+		CSVColumns:         []string{"time", "msg"},
+		ConsoleColors:      map[string]string{"info": "28"},
+		ConsoleLevelWidth:  4,
+		ConsoleLoggerWidth: 12,
+		Writer: WriterConfig{
+			Target:            "./logit.log",
+			FileRotate:        true,
+			FileMaxSize:       "1GB",
+			FileMaxBackups:    30,
+			FileRetainAtLeast: 3,
+			FileTimeZone:      "UTC",
+		},
+		Writers:          []WriterConfig{{Target: "stdout"}},
+		ErrorWriter:      WriterConfig{Target: "stderr"},
+		WithPID:          true,
+		TimeFormat:       "unix",
+		UTC:              true,
+		Attrs:            map[string]string{"env": "prod"},
+		ContextKeys:      []string{"request_id"},
+		AttrTypes:        map[string]string{"status": "int", "cost": "float"},
+		Masking:          []string{"password"},
+		Filters:          []FilterConfig{{Message: "^health"}},
+		BlobDir:          "./blobs",
+		BlobThreshold:    "64KB",
+		SampleInterval:   "1s",
+		SampleInitial:    100,
+		SampleThereafter: 10,
+		AsyncQueueSize:   1024,
+		AsyncTTL:         "10m",
+	}
+
+	override := &Config{
+		Level:               "debug",
+		LevelNames:          map[string]string{"info": "info"},
+		CSVColumns:          []string{"level", "msg"},
+		ConsoleColors:       map[string]string{"warn": "#af5f00"},
+		ConsoleIcons:        map[string]string{"error": "❌"},
+		ConsoleLoggerWidth:  16,
+		ConsoleMessageWidth: 60,
+		Attrs:               map[string]string{"region": "cn"},
+		AttrTypes:           map[string]string{"status": "string"},
+		BlobDir:             "./logs/blobs",
+		Filters:             []FilterConfig{{Attrs: map[string]string{"component": "grpc"}, MaxLevel: "warn"}},
+		Writer: WriterConfig{
+			FileMaxSize:       "64MB",
+			FileMaxTotalSize:  "1GB",
+			FileRetainAtLeast: 5,
+			FileTimeZone:      "Local",
+			BatchSize:         16,
+			BatchParallelism:  4,
+			Stats:             true,
+			FileLevels:        true,
+		},
+		Writers:          []WriterConfig{{Target: "stderr"}, {Target: "./logit.log"}},
+		ErrorWriter:      WriterConfig{BufferSize: "4KB"},
+		WithSource:       true,
+		SourceSegments:   2,
+		Colors:           true,
+		Escape:           "none",
+		SampleThereafter: 100,
+		AsyncDropPolicy:  "newest",
+		Stats:            true,
+		HandleTimeStats:  true,
+	}
+
+	want := &Config{
+		Level:               "debug",
+		LevelNames:          map[string]string{"warn": "WARNING", "info": "info"},
+		Handler:             "json",
+		CSVColumns:          []string{"level", "msg"},
+		ConsoleColors:       map[string]string{"info": "28", "warn": "#af5f00"},
+		ConsoleIcons:        map[string]string{"error": "❌"},
+		ConsoleLevelWidth:   4,
+		ConsoleLoggerWidth:  16,
+		ConsoleMessageWidth: 60,
+		Writer: WriterConfig{
+			Target:            "./logit.log",
+			FileRotate:        true,
+			FileMaxSize:       "64MB",
+			FileMaxBackups:    30,
+			FileMaxTotalSize:  "1GB",
+			FileRetainAtLeast: 5,
+			FileTimeZone:      "Local",
+			BatchSize:         16,
+			BatchParallelism:  4,
+			Stats:             true,
+			FileLevels:        true,
+		},
+		Writers:          []WriterConfig{{Target: "stderr"}, {Target: "./logit.log"}},
+		ErrorWriter:      WriterConfig{Target: "stderr", BufferSize: "4KB"},
+		WithSource:       true,
+		SourceSegments:   2,
+		WithPID:          true,
+		Colors:           true,
+		Escape:           "none",
+		TimeFormat:       "unix",
+		UTC:              true,
+		Attrs:            map[string]string{"env": "prod", "region": "cn"},
+		ContextKeys:      []string{"request_id"},
+		AttrTypes:        map[string]string{"status": "string", "cost": "float"},
+		Masking:          []string{"password"},
+		Filters:          []FilterConfig{{Attrs: map[string]string{"component": "grpc"}, MaxLevel: "warn"}},
+		BlobDir:          "./logs/blobs",
+		BlobThreshold:    "64KB",
+		SampleInterval:   "1s",
+		SampleInitial:    100,
+		SampleThereafter: 100,
+		AsyncQueueSize:   1024,
+		AsyncDropPolicy:  "newest",
+		AsyncTTL:         "10m",
+		Stats:            true,
+		HandleTimeStats:  true,
+	}
+
+	merged := MergeConfig(base, override)
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("merged %+v != want %+v", merged, want)
+	}
+
+	if base.Level != "info" || override.Handler != "" || base.AttrTypes["status"] != "int" {
+		t.Fatalf("base %+v or override %+v is modified", base, override)
+	}
+
+	if merged = MergeConfig(nil, override); merged.Level != "debug" {
+		t.Fatalf("merged.Level %s != 'debug'", merged.Level)
+	}
+
+	if merged = MergeConfig(base, nil); merged.Level != "info" {
+		t.Fatalf("merged.Level %s != 'info'", merged.Level)
+	}
+}
+
+func writeConfigFile(t *testing.T, path string, conf map[string]any) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoadConfig$
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	platformDir := filepath.Join(dir, "platform")
+
+	if err := os.MkdirAll(platformDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfigFile(t, filepath.Join(platformDir, "base.json"), map[string]any{
+		"level":    "info",
+		"handler":  "json",
+		"with_pid": true,
+		"writer":   map[string]any{"target": "stderr"},
+	})
+
+	writeConfigFile(t, filepath.Join(platformDir, "file.json"), map[string]any{
+		"writer": map[string]any{"target": "./logit.log", "file_rotate": true},
+	})
+
+	appPath := filepath.Join(dir, "app.json")
+	writeConfigFile(t, appPath, map[string]any{
+		"include": []string{"platform/base.json", filepath.Join(platformDir, "file.json")},
+		"level":   "debug",
+	})
+
+	conf, err := LoadConfig(appPath, json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Config{
+		Level:   "debug",
+		Handler: "json",
+		Writer: WriterConfig{
+			Target:     "./logit.log",
+			FileRotate: true,
+		},
+		WithPID: true,
+	}
+
+	if !reflect.DeepEqual(conf, want) {
+		t.Fatalf("conf %+v != want %+v", conf, want)
+	}
+
+	writeConfigFile(t, filepath.Join(platformDir, "base.json"), map[string]any{
+		"include": []string{"../app.json"},
+	})
+
+	if _, err = LoadConfig(appPath, json.Unmarshal); err == nil {
+		t.Fatal("loading config included circularly should be failed")
+	}
+}
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -102,3 +103,43 @@ func TestParseTimeDuration(t *testing.T) {
 		})
 	}
 }
+
+// go test -v -run=^$ -fuzz=^FuzzParseByteSize$ -fuzztime=10s
+func FuzzParseByteSize(f *testing.F) {
+	f.Add("64")
+	f.Add("128b")
+	f.Add("4KB")
+	f.Add(" 2Mb ")
+	f.Add("1g")
+
+	f.Fuzz(func(t *testing.T, size string) {
+		got, err := parseByteSize(size)
+
+		spaced, spacedErr := parseByteSize(" " + size + " ")
+		if (err == nil) != (spacedErr == nil) || got != spaced {
+			t.Fatalf("size %q got %d, %v but spaced got %d, %v", size, got, err, spaced, spacedErr)
+		}
+	})
+}
+
+// go test -v -run=^$ -fuzz=^FuzzParseTimeDuration$ -fuzztime=10s
+func FuzzParseTimeDuration(f *testing.F) {
+	f.Add("7d")
+	f.Add("7D")
+	f.Add("24h")
+	f.Add("1h30m")
+	f.Add("-1d")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := parseTimeDuration(s)
+
+		if strings.HasSuffix(s, "d") || strings.HasSuffix(s, "D") {
+			return
+		}
+
+		want, wantErr := time.ParseDuration(s)
+		if (err == nil) != (wantErr == nil) || got != want {
+			t.Fatalf("s %q got %d, %v != want %d, %v", s, got, err, want, wantErr)
+		}
+	})
+}
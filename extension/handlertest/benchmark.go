@@ -0,0 +1,134 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlertest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// Scenario is a standardized workload for benchmarking handlers.
+type Scenario struct {
+	// Name is the name of scenario and will be used as the name of sub benchmark.
+	Name string
+
+	// Level is the level of records.
+	Level slog.Level
+
+	// Message is the message of records.
+	Message string
+
+	// Attrs are the attrs added to each record.
+	Attrs []slog.Attr
+
+	// WithAttrs are the attrs added to handler by WithAttrs before benchmarking.
+	WithAttrs []slog.Attr
+
+	// WithGroup is the group added to handler by WithGroup before benchmarking.
+	// It will be added after WithAttrs and be ignored if empty.
+	WithGroup string
+}
+
+var (
+	// ScenarioMessage logs a message without any attrs.
+	ScenarioMessage = Scenario{
+		Name:    "message",
+		Level:   slog.LevelInfo,
+		Message: "benchmark message",
+	}
+
+	// ScenarioAttrs logs a message with attrs of common kinds.
+	ScenarioAttrs = Scenario{
+		Name:    "attrs",
+		Level:   slog.LevelInfo,
+		Message: "benchmark message",
+		Attrs: []slog.Attr{
+			slog.String("trace", "0123456789abcdef"),
+			slog.Int("id", 123),
+			slog.Float64("pi", 3.14),
+			slog.Bool("ok", true),
+			slog.Duration("cost", time.Millisecond),
+			slog.Time("at", time.Unix(1700000000, 0)),
+		},
+	}
+
+	// ScenarioWithAttrs logs a message with a handler which has attrs added by WithAttrs.
+	ScenarioWithAttrs = Scenario{
+		Name:    "with_attrs",
+		Level:   slog.LevelInfo,
+		Message: "benchmark message",
+		Attrs: []slog.Attr{
+			slog.Int("id", 123),
+		},
+		WithAttrs: []slog.Attr{
+			slog.String("service", "benchmark"),
+			slog.Int("pid", 1),
+		},
+	}
+
+	// ScenarioGroup logs a message with a handler which has a group added by WithGroup.
+	ScenarioGroup = Scenario{
+		Name:    "group",
+		Level:   slog.LevelInfo,
+		Message: "benchmark message",
+		Attrs: []slog.Attr{
+			slog.Int("id", 123),
+			slog.Group("request", slog.String("method", "GET"), slog.String("path", "/")),
+		},
+		WithGroup: "benchmark",
+	}
+)
+
+// Scenarios returns all standardized scenarios.
+func Scenarios() []Scenario {
+	return []Scenario{ScenarioMessage, ScenarioAttrs, ScenarioWithAttrs, ScenarioGroup}
+}
+
+// BenchmarkHandler benchmarks handler with scenario and reports allocations.
+// The record is created once before benchmarking so only the cost of handler is measured.
+func BenchmarkHandler(b *testing.B, handler slog.Handler, scenario Scenario) {
+	if len(scenario.WithAttrs) > 0 {
+		handler = handler.WithAttrs(scenario.WithAttrs)
+	}
+
+	if scenario.WithGroup != "" {
+		handler = handler.WithGroup(scenario.WithGroup)
+	}
+
+	ctx := context.Background()
+	record := slog.NewRecord(time.Now(), scenario.Level, scenario.Message, 0)
+	record.AddAttrs(scenario.Attrs...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := handler.Handle(ctx, record); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkScenarios benchmarks handler with all standardized scenarios as sub benchmarks.
+// Run it with the built-in handlers and your handler to compare their performances.
+func BenchmarkScenarios(b *testing.B, handler slog.Handler) {
+	for _, scenario := range Scenarios() {
+		b.Run(scenario.Name, func(b *testing.B) {
+			BenchmarkHandler(b, handler, scenario)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlertest
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+// go test -v -run=^$ -bench=^BenchmarkTapeHandler$ -benchtime=1s
+func BenchmarkTapeHandler(b *testing.B) {
+	BenchmarkScenarios(b, handler.NewTapeHandler(io.Discard, nil))
+}
+
+// go test -v -run=^$ -bench=^BenchmarkTextHandler$ -benchtime=1s
+func BenchmarkTextHandler(b *testing.B) {
+	BenchmarkScenarios(b, slog.NewTextHandler(io.Discard, nil))
+}
+
+// go test -v -run=^$ -bench=^BenchmarkJsonHandler$ -benchtime=1s
+func BenchmarkJsonHandler(b *testing.B) {
+	BenchmarkScenarios(b, slog.NewJSONHandler(io.Discard, nil))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBenchmarkHandler$
+func TestBenchmarkHandler(t *testing.T) {
+	benchtime := flag.Lookup("test.benchtime")
+	defer benchtime.Value.Set(benchtime.Value.String())
+
+	if err := benchtime.Value.Set("100x"); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	for _, scenario := range Scenarios() {
+		buffer.Reset()
+		handler := slog.NewTextHandler(buffer, nil)
+
+		result := testing.Benchmark(func(b *testing.B) {
+			BenchmarkHandler(b, handler, scenario)
+		})
+
+		if result.N != 100 {
+			t.Fatalf("scenario %s result.N %d != 100", scenario.Name, result.N)
+		}
+
+		line, _, _ := strings.Cut(buffer.String(), "\n")
+		if !strings.Contains(line, "msg=\""+scenario.Message+"\"") {
+			t.Fatalf("scenario %s line %s is wrong", scenario.Name, line)
+		}
+
+		if scenario.WithGroup != "" && !strings.Contains(line, scenario.WithGroup+".") {
+			t.Fatalf("scenario %s line %s missing group", scenario.Name, line)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	MB = 1024 * 1024
+)
+
+type config struct {
+	// client is the client sending requests.
+	client *http.Client
+
+	// ownClient reports whether client is created by the writer, so its idle connections can be closed when closing.
+	ownClient bool
+
+	// header is the header of requests.
+	header http.Header
+
+	// gzip compresses the body of requests if true.
+	gzip bool
+
+	// batchSize is the max count of logs in one request.
+	batchSize uint64
+
+	// maxBatchBytes is the max bytes of logs in one request before compressing.
+	// A request will be sent if the logs in batch reach batchSize or maxBatchBytes.
+	maxBatchBytes uint64
+
+	// envelopePrefix, envelopeSeparator and envelopeSuffix wrap logs in batch as the body, see WithEnvelope.
+	envelopePrefix    []byte
+	envelopeSeparator []byte
+	envelopeSuffix    []byte
+
+	// retries is the max count of retries after the first request failed.
+	retries int
+
+	// retryInterval is the interval between retries, and it grows linearly with the count of retries.
+	retryInterval time.Duration
+}
+
+func newDefaultConfig() config {
+	header := make(http.Header, 4)
+	header.Set("Content-Type", "application/x-ndjson")
+
+	return config{
+		client:        &http.Client{Timeout: 10 * time.Second},
+		ownClient:     true,
+		header:        header,
+		gzip:          false,
+		batchSize:     100,
+		maxBatchBytes: MB,
+		retries:       3,
+		retryInterval: time.Second,
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewDefaultConfig$
+func TestNewDefaultConfig(t *testing.T) {
+	conf := newDefaultConfig()
+
+	if conf.client == nil || conf.client.Timeout != 10*time.Second || !conf.ownClient {
+		t.Fatalf("conf.client %+v or conf.ownClient %+v is wrong", conf.client, conf.ownClient)
+	}
+
+	if contentType := conf.header.Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Fatalf("contentType %s is wrong", contentType)
+	}
+
+	if conf.gzip {
+		t.Fatal("conf.gzip is wrong")
+	}
+
+	if conf.batchSize != 100 || conf.maxBatchBytes != MB {
+		t.Fatalf("conf.batchSize %d or conf.maxBatchBytes %d is wrong", conf.batchSize, conf.maxBatchBytes)
+	}
+
+	if conf.retries != 3 || conf.retryInterval != time.Second {
+		t.Fatalf("conf.retries %d or conf.retryInterval %s is wrong", conf.retries, conf.retryInterval)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option sets some fields to config.
+type Option func(c *config)
+
+func (o Option) apply(c *config) {
+	o(c)
+}
+
+// WithClient sets client to config.
+// Use it if you want to customize the transport like tls.
+// Notice that the timeout set by WithTimeout is the timeout of client.
+// The client is owned by the caller, so its idle connections won't be closed when closing the writer.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+		c.ownClient = false
+	}
+}
+
+// WithTimeout sets the timeout of each request to config.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.client.Timeout = timeout
+	}
+}
+
+// WithHeader sets a header of requests to config.
+// The default content type is "application/x-ndjson", and you can replace it by this option.
+func WithHeader(key string, value string) Option {
+	return func(c *config) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithGzip sets gzip=true to config.
+// The body of requests will be compressed by gzip with header "Content-Encoding: gzip".
+func WithGzip() Option {
+	return func(c *config) {
+		c.gzip = true
+	}
+}
+
+// WithBatchSize sets the max count of logs in one request to config.
+func WithBatchSize(size uint64) Option {
+	return func(c *config) {
+		c.batchSize = size
+	}
+}
+
+// WithMaxBatchBytes sets the max bytes of logs in one request to config.
+func WithMaxBatchBytes(bytes uint64) Option {
+	return func(c *config) {
+		c.maxBatchBytes = bytes
+	}
+}
+
+// WithEnvelope sets the envelope of logs in one request to config.
+// The body will be prefix, logs joined by separator, and suffix, which is useful for apis accepting a json array or object.
+// For example, WithEnvelope("[", ",", "]") posts logs in a json array if each log is a json object without '\n'.
+func WithEnvelope(prefix string, separator string, suffix string) Option {
+	return func(c *config) {
+		c.envelopePrefix = []byte(prefix)
+		c.envelopeSeparator = []byte(separator)
+		c.envelopeSuffix = []byte(suffix)
+	}
+}
+
+// WithRetry sets the max count of retries and the interval between retries to config.
+// The interval grows linearly, so the nth retry will wait n*interval.
+func WithRetry(retries int, interval time.Duration) Option {
+	return func(c *config) {
+		c.retries = retries
+		c.retryInterval = interval
+	}
+}
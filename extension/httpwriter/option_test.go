@@ -0,0 +1,108 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithClient$
+func TestWithClient(t *testing.T) {
+	c := newDefaultConfig()
+	client := &http.Client{}
+
+	WithClient(client).apply(&c)
+
+	if c.client != client || c.ownClient {
+		t.Fatalf("c.client %p != client %p or c.ownClient %+v is wrong", c.client, client, c.ownClient)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTimeout$
+func TestWithTimeout(t *testing.T) {
+	c := newDefaultConfig()
+	WithTimeout(time.Minute).apply(&c)
+
+	if c.client.Timeout != time.Minute {
+		t.Fatalf("c.client.Timeout %s != time.Minute", c.client.Timeout)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHeader$
+func TestWithHeader(t *testing.T) {
+	c := newDefaultConfig()
+	WithHeader("Authorization", "Bearer token").apply(&c)
+	WithHeader("Content-Type", "application/json").apply(&c)
+
+	if got := c.header.Get("Authorization"); got != "Bearer token" {
+		t.Fatalf("got %s != Bearer token", got)
+	}
+
+	if got := c.header.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("got %s != application/json", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithGzip$
+func TestWithGzip(t *testing.T) {
+	c := newDefaultConfig()
+	WithGzip().apply(&c)
+
+	if !c.gzip {
+		t.Fatal("c.gzip is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBatchSize$
+func TestWithBatchSize(t *testing.T) {
+	c := newDefaultConfig()
+	WithBatchSize(16).apply(&c)
+
+	if c.batchSize != 16 {
+		t.Fatalf("c.batchSize %d != 16", c.batchSize)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithMaxBatchBytes$
+func TestWithMaxBatchBytes(t *testing.T) {
+	c := newDefaultConfig()
+	WithMaxBatchBytes(1024).apply(&c)
+
+	if c.maxBatchBytes != 1024 {
+		t.Fatalf("c.maxBatchBytes %d != 1024", c.maxBatchBytes)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithEnvelope$
+func TestWithEnvelope(t *testing.T) {
+	c := newDefaultConfig()
+	WithEnvelope("[", ",", "]").apply(&c)
+
+	if string(c.envelopePrefix) != "[" || string(c.envelopeSeparator) != "," || string(c.envelopeSuffix) != "]" {
+		t.Fatalf("c.envelopePrefix %s or c.envelopeSeparator %s or c.envelopeSuffix %s is wrong", c.envelopePrefix, c.envelopeSeparator, c.envelopeSuffix)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithRetry$
+func TestWithRetry(t *testing.T) {
+	c := newDefaultConfig()
+	WithRetry(5, time.Millisecond).apply(&c)
+
+	if c.retries != 5 || c.retryInterval != time.Millisecond {
+		t.Fatalf("c.retries %d or c.retryInterval %s is wrong", c.retries, c.retryInterval)
+	}
+}
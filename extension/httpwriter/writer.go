@@ -0,0 +1,227 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// maxDiscardBytes is the max bytes of response body read before closing, so the connection can be reused.
+	maxDiscardBytes = 4 * 1024
+)
+
+// Writer is a writer posting batches of logs to a url.
+// Each write is a log, and logs in batch are joined as the body of one request,
+// so the body is newline delimited if the handler ends logs with '\n' like json handler.
+// Logs are posted when the batch is full or the writer is synced, so use logit.WithSyncTimer to post them periodically.
+// Logs in a batch will be discarded if all retries failed, so they won't pile up when the server is down.
+// Batches are posted without holding the lock, so retrying a slow server won't block other goroutines writing logs,
+// and batches taken by different goroutines may be posted concurrently.
+type Writer struct {
+	conf config
+	url  string
+
+	// count is the count of logs in buffer.
+	count  uint64
+	buffer *bytes.Buffer
+
+	// envelopeBuffer is reused for wrapping logs in envelope.
+	envelopeBuffer *bytes.Buffer
+
+	// gzipBuffer and gzipWriter are reused for compressing body.
+	gzipBuffer *bytes.Buffer
+	gzipWriter *gzip.Writer
+
+	lock sync.Mutex
+}
+
+// New returns a new writer posting logs to url with options.
+// See Option.
+func New(url string, opts ...Option) *Writer {
+	conf := newDefaultConfig()
+	for _, opt := range opts {
+		opt.apply(&conf)
+	}
+
+	w := &Writer{
+		conf:   conf,
+		url:    url,
+		buffer: bytes.NewBuffer(make([]byte, 0, 4*1024)),
+	}
+
+	if len(conf.envelopePrefix) > 0 || len(conf.envelopeSuffix) > 0 {
+		w.envelopeBuffer = bytes.NewBuffer(make([]byte, 0, 4*1024))
+	}
+
+	if conf.gzip {
+		w.gzipBuffer = bytes.NewBuffer(make([]byte, 0, 4*1024))
+		w.gzipWriter = gzip.NewWriter(w.gzipBuffer)
+	}
+
+	return w
+}
+
+// Write writes p to batch and posts the batch if it's full.
+// It returns an error if posting failed, and the batch is discarded.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.lock.Lock()
+
+	if w.count > 0 {
+		w.buffer.Write(w.conf.envelopeSeparator)
+	}
+
+	n, _ = w.buffer.Write(p)
+	w.count++
+
+	var body []byte
+	if w.count >= w.conf.batchSize || uint64(w.buffer.Len()) >= w.conf.maxBatchBytes {
+		body, err = w.takeBody()
+	}
+
+	w.lock.Unlock()
+
+	if err != nil {
+		return n, err
+	}
+
+	return n, w.post(body)
+}
+
+func (w *Writer) newBody() ([]byte, error) {
+	body := w.buffer.Bytes()
+	if w.envelopeBuffer != nil {
+		w.envelopeBuffer.Reset()
+		w.envelopeBuffer.Write(w.conf.envelopePrefix)
+		w.envelopeBuffer.Write(body)
+		w.envelopeBuffer.Write(w.conf.envelopeSuffix)
+		body = w.envelopeBuffer.Bytes()
+	}
+
+	if !w.conf.gzip {
+		return body, nil
+	}
+
+	w.gzipBuffer.Reset()
+	w.gzipWriter.Reset(w.gzipBuffer)
+
+	if _, err := w.gzipWriter.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := w.gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return w.gzipBuffer.Bytes(), nil
+}
+
+// send sends body in one request and reports whether it should be retried if failed.
+func (w *Writer) send(body []byte) (retry bool, err error) {
+	request, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	for key, values := range w.conf.header {
+		request.Header[key] = values
+	}
+
+	if w.conf.gzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+
+	response, err := w.conf.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+
+	defer response.Body.Close()
+	io.CopyN(io.Discard, response.Body, maxDiscardBytes)
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+
+	retry = response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError
+	return retry, fmt.Errorf("logit: post logs to %s failed with status %s", w.url, response.Status)
+}
+
+// takeBody returns the body of the batch and resets it, and the body is nil if the batch is empty.
+// The body is copied because buffers are reused by the next batch, so it can be posted without holding the lock.
+// It should be called with lock held.
+func (w *Writer) takeBody() ([]byte, error) {
+	if w.count <= 0 {
+		return nil, nil
+	}
+
+	defer func() {
+		w.count = 0
+		w.buffer.Reset()
+	}()
+
+	body, err := w.newBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.Clone(body), nil
+}
+
+// post posts body with retries if it's not nil.
+func (w *Writer) post(body []byte) error {
+	if body == nil {
+		return nil
+	}
+
+	for i := 0; ; i++ {
+		retry, err := w.send(body)
+		if err == nil || !retry || i >= w.conf.retries {
+			return err
+		}
+
+		time.Sleep(time.Duration(i+1) * w.conf.retryInterval)
+	}
+}
+
+// Sync posts the batch if it has logs.
+func (w *Writer) Sync() error {
+	w.lock.Lock()
+	body, err := w.takeBody()
+	w.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return w.post(body)
+}
+
+// Close posts the batch if it has logs and closes idle connections of client if it isn't set by WithClient.
+func (w *Writer) Close() error {
+	err := w.Sync()
+
+	if w.conf.ownClient {
+		w.conf.client.CloseIdleConnections()
+	}
+
+	return err
+}
@@ -0,0 +1,307 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpwriter
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testServer struct {
+	*httptest.Server
+
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+	lock     sync.Mutex
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	ts := &testServer{statuses: statuses}
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.lock.Lock()
+		defer ts.lock.Unlock()
+
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			reader = gzipReader
+		}
+
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		ts.bodies = append(ts.bodies, string(body))
+		ts.headers = append(ts.headers, r.Header.Clone())
+
+		status := http.StatusOK
+		if len(ts.statuses) > 0 {
+			status = ts.statuses[0]
+			ts.statuses = ts.statuses[1:]
+		}
+
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *testServer) reset(statuses ...int) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.statuses = statuses
+	ts.bodies = nil
+	ts.headers = nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriter$
+func TestWriter(t *testing.T) {
+	server := newTestServer(t)
+	writer := New(server.URL, WithBatchSize(2), WithHeader("Authorization", "Bearer token"), WithGzip())
+
+	logs := []string{"log1\n", "log2\n", "log3\n"}
+	for _, log := range logs {
+		n, err := writer.Write([]byte(log))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != len(log) {
+			t.Fatalf("n %d != len(log) %d", n, len(log))
+		}
+	}
+
+	if len(server.bodies) != 1 || server.bodies[0] != "log1\nlog2\n" {
+		t.Fatalf("server.bodies %q is wrong", server.bodies)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.bodies) != 2 || server.bodies[1] != "log3\n" {
+		t.Fatalf("server.bodies %q is wrong", server.bodies)
+	}
+
+	// Syncing an empty batch won't send a request.
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.bodies) != 2 {
+		t.Fatalf("len(server.bodies) %d != 2", len(server.bodies))
+	}
+
+	header := server.headers[0]
+	if header.Get("Authorization") != "Bearer token" || header.Get("Content-Type") != "application/x-ndjson" || header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("header %+v is wrong", header)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterMaxBatchBytes$
+func TestWriterMaxBatchBytes(t *testing.T) {
+	server := newTestServer(t)
+	writer := New(server.URL, WithMaxBatchBytes(8))
+
+	for _, log := range []string{"log1\n", "log2\n", "log3\n"} {
+		if _, err := writer.Write([]byte(log)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(server.bodies) != 1 || server.bodies[0] != "log1\nlog2\n" {
+		t.Fatalf("server.bodies %q is wrong", server.bodies)
+	}
+
+	if server.headers[0].Get("Content-Encoding") != "" {
+		t.Fatalf("header %+v is wrong", server.headers[0])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterEnvelope$
+func TestWriterEnvelope(t *testing.T) {
+	server := newTestServer(t)
+	writer := New(server.URL, WithBatchSize(2), WithEnvelope(`{"logs":[`, ",", "]}"), WithGzip())
+
+	for _, log := range []string{`{"id":1}`, `{"id":2}`, `{"id":3}`} {
+		if _, err := writer.Write([]byte(log)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`{"logs":[{"id":1},{"id":2}]}`, `{"logs":[{"id":3}]}`}
+	if len(server.bodies) != 2 || server.bodies[0] != want[0] || server.bodies[1] != want[1] {
+		t.Fatalf("server.bodies %q != want %q", server.bodies, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterRetry$
+func TestWriterRetry(t *testing.T) {
+	server := newTestServer(t, http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK)
+	writer := New(server.URL, WithBatchSize(1), WithRetry(2, time.Millisecond))
+
+	if _, err := writer.Write([]byte("log\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.bodies) != 3 {
+		t.Fatalf("len(server.bodies) %d != 3", len(server.bodies))
+	}
+
+	// All retries failed.
+	server.reset(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	if _, err := writer.Write([]byte("log\n")); err == nil {
+		t.Fatal("writing should fail")
+	}
+
+	if len(server.bodies) != 3 {
+		t.Fatalf("len(server.bodies) %d != 3", len(server.bodies))
+	}
+
+	// Client errors won't be retried.
+	server.reset(http.StatusBadRequest)
+
+	if _, err := writer.Write([]byte("log\n")); err == nil {
+		t.Fatal("writing should fail")
+	}
+
+	if len(server.bodies) != 1 {
+		t.Fatalf("len(server.bodies) %d != 1", len(server.bodies))
+	}
+
+	// The failed batch is discarded.
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.bodies) != 1 {
+		t.Fatalf("len(server.bodies) %d != 1", len(server.bodies))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterRetryConcurrently$
+func TestWriterRetryConcurrently(t *testing.T) {
+	server := newTestServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	writer := New(server.URL, WithBatchSize(2), WithRetry(1, 500*time.Millisecond))
+
+	writer.Write([]byte("log1\n"))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := writer.Write([]byte("log2\n"))
+		done <- err
+	}()
+
+	// Wait for the first request failing, so the batch is waiting for retrying.
+	for {
+		server.lock.Lock()
+		requests := len(server.bodies)
+		server.lock.Unlock()
+
+		if requests > 0 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// Writing logs to the next batch shouldn't wait for retrying.
+	begin := time.Now()
+	if _, err := writer.Write([]byte("log3\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if cost := time.Since(begin); cost >= 100*time.Millisecond {
+		t.Fatalf("writing costs %s and it's blocked by retrying", cost)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"log1\nlog2\n", "log1\nlog2\n", "log3\n"}
+	if len(server.bodies) != len(want) || server.bodies[0] != want[0] || server.bodies[1] != want[1] || server.bodies[2] != want[2] {
+		t.Fatalf("server.bodies %q != want %q", server.bodies, want)
+	}
+}
+
+type testTransport struct {
+	http.RoundTripper
+
+	idleClosed bool
+}
+
+func (tt *testTransport) CloseIdleConnections() {
+	tt.idleClosed = true
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterClose$
+func TestWriterClose(t *testing.T) {
+	server := newTestServer(t)
+	transport := &testTransport{RoundTripper: http.DefaultTransport}
+
+	// The client is owned by the caller, so its idle connections shouldn't be closed.
+	writer := New(server.URL, WithClient(&http.Client{Transport: transport}))
+	writer.Write([]byte("log\n"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.bodies) != 1 || server.bodies[0] != "log\n" {
+		t.Fatalf("server.bodies %q is wrong", server.bodies)
+	}
+
+	if transport.idleClosed {
+		t.Fatal("idle connections of the client set by WithClient shouldn't be closed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterUnreachable$
+func TestWriterUnreachable(t *testing.T) {
+	server := newTestServer(t)
+	url := server.URL
+	server.Close()
+
+	writer := New(url, WithBatchSize(1), WithRetry(1, time.Millisecond), WithTimeout(time.Second))
+	if _, err := writer.Write([]byte("log\n")); err == nil {
+		t.Fatal("writing should fail")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Logitgen generates typed logging functions from a schema of events.
+//
+//	logitgen -schema events.json -out events/events.go
+//
+// The schema is in json, see logitgen.Schema.
+// Use logitgen.LoadSchema and logitgen.Generate in your own tool if you want a schema in yaml or toml.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/FishGoddess/logit/extension/logitgen"
+)
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("logitgen", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+
+	schemaPath := flags.String("schema", "", "the path of schema in json")
+	outPath := flags.String("out", "", "the path of generated code, and code will be written to stdout if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *schemaPath == "" {
+		return errors.New("logit: schema is required")
+	}
+
+	schema, err := logitgen.LoadSchema(*schemaPath, json.Unmarshal)
+	if err != nil {
+		return err
+	}
+
+	code, err := logitgen.Generate(*schema)
+	if err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		_, err = stdout.Write(code)
+		return err
+	}
+
+	return os.WriteFile(*outPath, code, 0644)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
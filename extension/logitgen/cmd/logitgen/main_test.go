@@ -0,0 +1,53 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRun$
+func TestRun(t *testing.T) {
+	stdout := bytes.NewBuffer(make([]byte, 0, 1024))
+	if err := run([]string{"-schema", "../../testdata/events.json"}, stdout); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stdout.String(); !strings.HasPrefix(got, "// Code generated by logitgen. DO NOT EDIT.") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "events.go")
+	if err := run([]string{"-schema", "../../testdata/events.json", "-out", outPath}, stdout); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(code) != stdout.String() {
+		t.Fatalf("code %s != stdout %s", code, stdout.String())
+	}
+
+	if err = run(nil, stdout); err == nil {
+		t.Fatal("running without schema should return an error")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logitgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:generate go run ./cmd/logitgen -schema testdata/events.json -out internal/events/events.go
+
+type attrType struct {
+	// goType is the type of the parameter.
+	goType string
+
+	// newAttr is the function creating the attr without reflection.
+	newAttr string
+}
+
+var attrTypes = map[string]attrType{
+	"string":   {goType: "string", newAttr: "slog.String"},
+	"int":      {goType: "int", newAttr: "slog.Int"},
+	"int64":    {goType: "int64", newAttr: "slog.Int64"},
+	"uint64":   {goType: "uint64", newAttr: "slog.Uint64"},
+	"float64":  {goType: "float64", newAttr: "slog.Float64"},
+	"bool":     {goType: "bool", newAttr: "slog.Bool"},
+	"duration": {goType: "time.Duration", newAttr: "slog.Duration"},
+	"time":     {goType: "time.Time", newAttr: "slog.Time"},
+	"any":      {goType: "any", newAttr: "slog.Any"},
+}
+
+var levelMethods = map[string]string{
+	"trace": "Trace",
+	"debug": "Debug",
+	"info":  "Info",
+	"warn":  "Warn",
+	"error": "Error",
+}
+
+var fileTemplate = template.Must(template.New("logitgen").Parse(`// Code generated by logitgen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"log/slog"
+{{- if .UseTime }}
+	"time"
+{{- end }}
+
+	"github.com/FishGoddess/logit"
+)
+{{ if .ImportPath }}
+func init() {
+	logit.SkipCallers({{ .ImportPath }})
+}
+{{ end }}
+{{- range .Events }}
+{{ .Doc }}
+func Log{{ .Name }}(logger *logit.Logger{{ range .Params }}, {{ . }}{{ end }}) {
+	logger.{{ .Method }}({{ .Msg }}{{ range .Attrs }}, {{ . }}{{ end }})
+}
+{{ end -}}
+`))
+
+type templateEvent struct {
+	Name   string
+	Doc    string
+	Method string
+	Msg    string
+	Params []string
+	Attrs  []string
+}
+
+type templateData struct {
+	Package    string
+	ImportPath string
+	UseTime    bool
+	Events     []templateEvent
+}
+
+// comment converts doc to a comment, like "// LogUserLogin logs ...".
+func comment(doc string) string {
+	lines := strings.Split(strings.TrimSpace(doc), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace("// " + strings.TrimSpace(line))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func newTemplateEvent(event Event) (templateEvent, bool) {
+	level := strings.ToLower(event.Level)
+
+	doc := event.Doc
+	if doc == "" {
+		doc = fmt.Sprintf("Log%s logs event %s in %s level.", event.Name, event.Name, level)
+	}
+
+	tmplEvent := templateEvent{
+		Name:   event.Name,
+		Doc:    comment(doc),
+		Method: levelMethods[level],
+		Msg:    strconv.Quote(event.Msg),
+		Params: make([]string, 0, len(event.Fields)),
+		Attrs:  make([]string, 0, len(event.Fields)),
+	}
+
+	useTime := false
+	for _, field := range event.Fields {
+		attrType := attrTypes[field.Type]
+		if strings.HasPrefix(attrType.goType, "time.") {
+			useTime = true
+		}
+
+		tmplEvent.Params = append(tmplEvent.Params, field.Param+" "+attrType.goType)
+		tmplEvent.Attrs = append(tmplEvent.Attrs, fmt.Sprintf("%s(%s, %s)", attrType.newAttr, strconv.Quote(field.Key), field.Param))
+	}
+
+	return tmplEvent, useTime
+}
+
+// Generate generates Go source code of schema, which has a function like LogUserLogin for each event.
+// Attrs of events are created by functions like slog.String, so there is no reflection when logging them.
+// The schema won't be modified.
+func Generate(schema Schema) ([]byte, error) {
+	events := make([]Event, 0, len(schema.Events))
+	for _, event := range schema.Events {
+		event.Fields = append([]Field(nil), event.Fields...)
+		events = append(events, event)
+	}
+
+	schema.Events = events
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+
+	data := templateData{
+		Package: schema.Package,
+		Events:  make([]templateEvent, 0, len(schema.Events)),
+	}
+
+	if schema.ImportPath != "" {
+		data.ImportPath = strconv.Quote(schema.ImportPath)
+	}
+
+	for _, event := range schema.Events {
+		tmplEvent, useTime := newTemplateEvent(event)
+		data.UseTime = data.UseTime || useTime
+		data.Events = append(data.Events, tmplEvent)
+	}
+
+	var buffer bytes.Buffer
+	if err := fileTemplate.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buffer.Bytes())
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logitgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/extension/logitgen/internal/events"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestGenerate$
+func TestGenerate(t *testing.T) {
+	schema, err := LoadSchema("testdata/events.json", json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := Generate(*schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("internal/events/events.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(code, want) {
+		t.Fatalf("code %s != want %s", code, want)
+	}
+
+	if schema.Events[0].Fields[0].Param != "" {
+		t.Fatalf("schema %+v shouldn't be modified", schema)
+	}
+
+	schema.Events[0].Level = "fatal"
+	if _, err = Generate(*schema); err == nil {
+		t.Fatal("generating an invalid schema should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestGeneratedCode$
+func TestGeneratedCode(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithSource())
+
+	events.LogUserLogin(logger, 123, "127.0.0.1")
+
+	got := buffer.String()
+	if !strings.Contains(got, "¦ user login ¦") || !strings.HasSuffix(got, "¦ user_id=123 ¦ ip=127.0.0.1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	// The generated package is skipped, so the source is this file.
+	if !strings.Contains(got, "generate_test.go") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	paidAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events.LogOrderPaid(logger, 1, 9.9, time.Second, paidAt, "online")
+
+	got = buffer.String()
+	if !strings.Contains(got, "¦ WARN ¦ order paid ¦") || !strings.Contains(got, "¦ order.id=1 ¦ amount=9.9 ¦ cost=1s ¦ paid_at=") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
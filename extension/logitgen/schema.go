@@ -0,0 +1,198 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logitgen
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// UnmarshalFunc unmarshals data to v, like json.Unmarshal or yaml.Unmarshal.
+type UnmarshalFunc func(data []byte, v any) error
+
+// Field is a field of an event, which will be a parameter of the generated function and an attr of the log.
+type Field struct {
+	// Key is the key of the attr, like "user_id".
+	Key string `json:"key" yaml:"key" toml:"key"`
+
+	// Type is the type of the attr.
+	// Values: "string", "int", "int64", "uint64", "float64", "bool", "duration", "time", "any".
+	Type string `json:"type" yaml:"type" toml:"type"`
+
+	// Param is the name of the parameter, which is converted from the key if empty, like "userID" of "user_id".
+	Param string `json:"param" yaml:"param" toml:"param"`
+}
+
+// Event is an event type, which will be generated to a function like LogUserLogin.
+type Event struct {
+	// Name is the name of the event in Go style, like "UserLogin".
+	Name string `json:"name" yaml:"name" toml:"name"`
+
+	// Level is the level of the event.
+	// Values: "trace", "debug", "info", "warn", "error".
+	Level string `json:"level" yaml:"level" toml:"level"`
+
+	// Msg is the message of the event, like "user login".
+	Msg string `json:"msg" yaml:"msg" toml:"msg"`
+
+	// Doc is the comment of the generated function, which is generated from the name and level if empty.
+	Doc string `json:"doc" yaml:"doc" toml:"doc"`
+
+	// Fields are the fields of the event in order.
+	Fields []Field `json:"fields" yaml:"fields" toml:"fields"`
+}
+
+// Schema is a list of events which will be generated to a Go package.
+type Schema struct {
+	// Package is the name of the generated package, like "events".
+	Package string `json:"package" yaml:"package" toml:"package"`
+
+	// ImportPath is the import path of the generated package, like "mycorp/events".
+	// If not empty, the package will be registered by logit.SkipCallers, so the source of logs is the caller of generated functions.
+	ImportPath string `json:"import_path" yaml:"import_path" toml:"import_path"`
+
+	// Events are the events in order.
+	Events []Event `json:"events" yaml:"events" toml:"events"`
+}
+
+// LoadSchema loads a schema from path and unmarshals it with unmarshal.
+func LoadSchema(path string, unmarshal UnmarshalFunc) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := new(Schema)
+	if err = unmarshal(data, schema); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// initialisms are the words which will be uppercase in parameter names.
+var initialisms = map[string]string{
+	"id": "ID", "ip": "IP", "url": "URL", "uri": "URI", "http": "HTTP", "api": "API", "json": "JSON", "sql": "SQL",
+}
+
+// reservedParams are the names which can't be used as parameters, including identifiers used by generated code.
+var reservedParams = map[string]struct{}{
+	"logger": {}, "slog": {}, "time": {}, "logit": {},
+}
+
+// paramName converts key to a parameter name, like "userID" of "user_id" and "requestURL" of "request-url".
+func paramName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	})
+
+	var builder strings.Builder
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		if i == 0 {
+			builder.WriteString(lower)
+			continue
+		}
+
+		if initialism, ok := initialisms[lower]; ok {
+			builder.WriteString(initialism)
+			continue
+		}
+
+		builder.WriteString(strings.ToUpper(lower[:1]) + lower[1:])
+	}
+
+	name := builder.String()
+	if _, ok := reservedParams[name]; ok || token.IsKeyword(name) {
+		name += "Value"
+	}
+
+	return name
+}
+
+func (f *Field) validate(event string) error {
+	if f.Key == "" {
+		return fmt.Errorf("logit: field of event %s has an empty key", event)
+	}
+
+	if _, ok := attrTypes[f.Type]; !ok {
+		return fmt.Errorf("logit: field %s of event %s has an unknown type %s", f.Key, event, f.Type)
+	}
+
+	if f.Param == "" {
+		f.Param = paramName(f.Key)
+	}
+
+	if !token.IsIdentifier(f.Param) {
+		return fmt.Errorf("logit: field %s of event %s has an invalid param %s", f.Key, event, f.Param)
+	}
+
+	if _, ok := reservedParams[f.Param]; ok {
+		return fmt.Errorf("logit: field %s of event %s has a reserved param %s", f.Key, event, f.Param)
+	}
+
+	return nil
+}
+
+func (e *Event) validate() error {
+	if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+		return fmt.Errorf("logit: event %s should be an exported identifier", e.Name)
+	}
+
+	if _, ok := levelMethods[strings.ToLower(e.Level)]; !ok {
+		return fmt.Errorf("logit: event %s has an unknown level %s", e.Name, e.Level)
+	}
+
+	params := make(map[string]struct{}, len(e.Fields))
+	for i := range e.Fields {
+		field := &e.Fields[i]
+		if err := field.validate(e.Name); err != nil {
+			return err
+		}
+
+		if _, ok := params[field.Param]; ok {
+			return fmt.Errorf("logit: event %s has a duplicated param %s", e.Name, field.Param)
+		}
+
+		params[field.Param] = struct{}{}
+	}
+
+	return nil
+}
+
+// validate checks the schema and fills the default values like params of fields.
+func (s *Schema) validate() error {
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("logit: package %s is invalid", s.Package)
+	}
+
+	names := make(map[string]struct{}, len(s.Events))
+	for i := range s.Events {
+		event := &s.Events[i]
+		if err := event.validate(); err != nil {
+			return err
+		}
+
+		if _, ok := names[event.Name]; ok {
+			return fmt.Errorf("logit: event %s is duplicated", event.Name)
+		}
+
+		names[event.Name] = struct{}{}
+	}
+
+	return nil
+}
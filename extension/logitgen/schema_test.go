@@ -0,0 +1,101 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logitgen
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoadSchema$
+func TestLoadSchema(t *testing.T) {
+	schema, err := LoadSchema("testdata/events.json", json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if schema.Package != "events" || len(schema.Events) != 2 || len(schema.Events[1].Fields) != 5 {
+		t.Fatalf("schema %+v is wrong", schema)
+	}
+
+	if _, err = LoadSchema(filepath.Join(t.TempDir(), "not_exist.json"), json.Unmarshal); err == nil {
+		t.Fatal("loading a schema not existed should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParamName$
+func TestParamName(t *testing.T) {
+	testCases := map[string]string{
+		"user_id":     "userID",
+		"request-url": "requestURL",
+		"order.id":    "orderID",
+		"IP":          "ip",
+		"paid_at":     "paidAt",
+		"type":        "typeValue",
+		"logger":      "loggerValue",
+		"time":        "timeValue",
+	}
+
+	for key, want := range testCases {
+		if got := paramName(key); got != want {
+			t.Fatalf("key %s: got %s != want %s", key, got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSchemaValidate$
+func TestSchemaValidate(t *testing.T) {
+	newSchema := func() Schema {
+		return Schema{
+			Package: "events",
+			Events: []Event{
+				{Name: "UserLogin", Level: "INFO", Msg: "user login", Fields: []Field{{Key: "user_id", Type: "int64"}}},
+			},
+		}
+	}
+
+	schema := newSchema()
+	if err := schema.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if param := schema.Events[0].Fields[0].Param; param != "userID" {
+		t.Fatalf("param %s is wrong", param)
+	}
+
+	breaks := []func(schema *Schema){
+		func(schema *Schema) { schema.Package = "my-events" },
+		func(schema *Schema) { schema.Events[0].Name = "userLogin" },
+		func(schema *Schema) { schema.Events[0].Level = "fatal" },
+		func(schema *Schema) { schema.Events[0].Fields[0].Key = "" },
+		func(schema *Schema) { schema.Events[0].Fields[0].Type = "int32" },
+		func(schema *Schema) { schema.Events[0].Fields[0].Param = "user id" },
+		func(schema *Schema) { schema.Events[0].Fields[0].Param = "logger" },
+		func(schema *Schema) { schema.Events = append(schema.Events, schema.Events[0]) },
+		func(schema *Schema) {
+			schema.Events[0].Fields = append(schema.Events[0].Fields, Field{Key: "user-id", Type: "string"})
+		},
+	}
+
+	for i, breakSchema := range breaks {
+		schema = newSchema()
+		breakSchema(&schema)
+
+		if err := schema.validate(); err == nil {
+			t.Fatalf("schema %d should be invalid", i)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logruscompat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// Entry is a log entry with fields like logrus.Entry.
+// Entries are immutable after being created by methods like WithField, so they are safe to share.
+type Entry struct {
+	Logger  *Logger
+	Data    Fields
+	Time    time.Time
+	Level   Level
+	Message string
+	Context context.Context
+}
+
+func (e *Entry) clone() *Entry {
+	entry := *e
+	entry.Data = make(Fields, len(e.Data)+1)
+
+	for key, value := range e.Data {
+		entry.Data[key] = value
+	}
+
+	return &entry
+}
+
+// WithField returns a new entry with the field.
+func (e *Entry) WithField(key string, value any) *Entry {
+	entry := e.clone()
+	entry.Data[key] = value
+
+	return entry
+}
+
+// WithFields returns a new entry with fields.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	entry := e.clone()
+	for key, value := range fields {
+		entry.Data[key] = value
+	}
+
+	return entry
+}
+
+// WithError returns a new entry with err as the field named ErrorKey.
+func (e *Entry) WithError(err error) *Entry {
+	return e.WithField(ErrorKey, err)
+}
+
+// WithContext returns a new entry with ctx which will be passed to the handler.
+func (e *Entry) WithContext(ctx context.Context) *Entry {
+	entry := e.clone()
+	entry.Context = ctx
+
+	return entry
+}
+
+// WithTime returns a new entry with t as the time passed to hooks.
+// Logs are handled by the logit logger, so their time is still the time of logging in logit.
+func (e *Entry) WithTime(t time.Time) *Entry {
+	entry := e.clone()
+	entry.Time = t
+
+	return entry
+}
+
+func (e *Entry) context() context.Context {
+	if e.Context != nil {
+		return e.Context
+	}
+
+	return context.Background()
+}
+
+// log fires hooks and logs the entry in level with msg.
+// It syncs the logger and panics with the entry in panic level, and exits the process with code 1 in fatal level.
+func (e *Entry) log(level Level, msg string) {
+	ctx := e.context()
+	slogLevel := level.slogLevel()
+
+	entry := e.clone()
+	entry.Level = level
+	entry.Message = msg
+
+	if e.Logger.logger.Enabled(ctx, slogLevel) {
+		if entry.Time.IsZero() {
+			entry.Time = defaults.CurrentTime()
+		}
+
+		e.Logger.fireHooks(entry)
+		e.Logger.logger.Log(ctx, slogLevel, callerSkip, entry.Message, entry.attrs()...)
+	}
+
+	if level > FatalLevel {
+		return
+	}
+
+	if err := e.Logger.Sync(); err != nil {
+		defaults.HandleError("Logger.Sync", err)
+	}
+
+	if level == FatalLevel {
+		exit(1)
+		return
+	}
+
+	panic(entry)
+}
+
+// attrs returns fields of entry as attrs passed to logit.Logger.Log.
+func (e *Entry) attrs() []any {
+	keys := make([]string, 0, len(e.Data))
+	for key := range e.Data {
+		keys = append(keys, key)
+	}
+
+	// Sort keys so logs with the same fields are always the same.
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, e.Data[key]))
+	}
+
+	return attrs
+}
+
+// sprintln formats args like fmt.Sprintln but without the trailing newline.
+func sprintln(args []any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
+
+// Trace logs args in trace level like fmt.Sprint.
+func (e *Entry) Trace(args ...any) {
+	e.log(TraceLevel, fmt.Sprint(args...))
+}
+
+// Tracef logs a message formatted with format and args in trace level.
+func (e *Entry) Tracef(format string, args ...any) {
+	e.log(TraceLevel, fmt.Sprintf(format, args...))
+}
+
+// Traceln logs args in trace level like fmt.Sprintln.
+func (e *Entry) Traceln(args ...any) {
+	e.log(TraceLevel, sprintln(args))
+}
+
+// Debug logs args in debug level like fmt.Sprint.
+func (e *Entry) Debug(args ...any) {
+	e.log(DebugLevel, fmt.Sprint(args...))
+}
+
+// Debugf logs a message formatted with format and args in debug level.
+func (e *Entry) Debugf(format string, args ...any) {
+	e.log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Debugln logs args in debug level like fmt.Sprintln.
+func (e *Entry) Debugln(args ...any) {
+	e.log(DebugLevel, sprintln(args))
+}
+
+// Info logs args in info level like fmt.Sprint.
+func (e *Entry) Info(args ...any) {
+	e.log(InfoLevel, fmt.Sprint(args...))
+}
+
+// Infof logs a message formatted with format and args in info level.
+func (e *Entry) Infof(format string, args ...any) {
+	e.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Infoln logs args in info level like fmt.Sprintln.
+func (e *Entry) Infoln(args ...any) {
+	e.log(InfoLevel, sprintln(args))
+}
+
+// Print logs args in info level like fmt.Sprint.
+func (e *Entry) Print(args ...any) {
+	e.log(InfoLevel, fmt.Sprint(args...))
+}
+
+// Printf logs a message formatted with format and args in info level.
+func (e *Entry) Printf(format string, args ...any) {
+	e.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Println logs args in info level like fmt.Sprintln.
+func (e *Entry) Println(args ...any) {
+	e.log(InfoLevel, sprintln(args))
+}
+
+// Warn logs args in warn level like fmt.Sprint.
+func (e *Entry) Warn(args ...any) {
+	e.log(WarnLevel, fmt.Sprint(args...))
+}
+
+// Warnf logs a message formatted with format and args in warn level.
+func (e *Entry) Warnf(format string, args ...any) {
+	e.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Warnln logs args in warn level like fmt.Sprintln.
+func (e *Entry) Warnln(args ...any) {
+	e.log(WarnLevel, sprintln(args))
+}
+
+// Warning logs args in warn level like fmt.Sprint.
+func (e *Entry) Warning(args ...any) {
+	e.log(WarnLevel, fmt.Sprint(args...))
+}
+
+// Warningf logs a message formatted with format and args in warn level.
+func (e *Entry) Warningf(format string, args ...any) {
+	e.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Warningln logs args in warn level like fmt.Sprintln.
+func (e *Entry) Warningln(args ...any) {
+	e.log(WarnLevel, sprintln(args))
+}
+
+// Error logs args in error level like fmt.Sprint.
+func (e *Entry) Error(args ...any) {
+	e.log(ErrorLevel, fmt.Sprint(args...))
+}
+
+// Errorf logs a message formatted with format and args in error level.
+func (e *Entry) Errorf(format string, args ...any) {
+	e.log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Errorln logs args in error level like fmt.Sprintln.
+func (e *Entry) Errorln(args ...any) {
+	e.log(ErrorLevel, sprintln(args))
+}
+
+// Fatal logs args in fatal level like fmt.Sprint.
+// It syncs the logger and exits the process with code 1 after logging.
+func (e *Entry) Fatal(args ...any) {
+	e.log(FatalLevel, fmt.Sprint(args...))
+}
+
+// Fatalf logs a message formatted with format and args in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (e *Entry) Fatalf(format string, args ...any) {
+	e.log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatalln logs args in fatal level like fmt.Sprintln.
+// It syncs the logger and exits the process with code 1 after logging.
+func (e *Entry) Fatalln(args ...any) {
+	e.log(FatalLevel, sprintln(args))
+}
+
+// Panic logs args in panic level like fmt.Sprint.
+// It syncs the logger and panics with the entry after logging.
+func (e *Entry) Panic(args ...any) {
+	e.log(PanicLevel, fmt.Sprint(args...))
+}
+
+// Panicf logs a message formatted with format and args in panic level.
+// It syncs the logger and panics with the entry after logging.
+func (e *Entry) Panicf(format string, args ...any) {
+	e.log(PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// Panicln logs args in panic level like fmt.Sprintln.
+// It syncs the logger and panics with the entry after logging.
+func (e *Entry) Panicln(args ...any) {
+	e.log(PanicLevel, sprintln(args))
+}
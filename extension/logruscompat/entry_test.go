@@ -0,0 +1,101 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logruscompat
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntry$
+func TestEntry(t *testing.T) {
+	logger, buffer := newTestLogger(t, TraceLevel)
+
+	entry := logger.WithField("key", "value")
+	entry.WithField("number", 1).Trace("trace")
+	entry.WithContext(context.Background()).Debugf("debug %d", 2)
+	entry.WithTime(time.Unix(0, 0)).Infoln("info", 3)
+
+	if len(entry.Data) != 1 {
+		t.Fatalf("entry.Data %+v is modified", entry.Data)
+	}
+
+	want := []testEntry{
+		{Level: "TRACE", Msg: "trace", Key: "value", Number: 1},
+		{Level: "DEBUG", Msg: "debug 2", Key: "value"},
+		{Level: "INFO", Msg: "info 3", Key: "value"},
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != len(want) {
+		t.Fatalf("len(entries) %d != len(want) %d", len(entries), len(want))
+	}
+
+	for i, entry := range entries {
+		if entry != want[i] {
+			t.Fatalf("entry %+v != want %+v", entry, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntryPanic$
+func TestEntryPanic(t *testing.T) {
+	logger, buffer := newTestLogger(t, InfoLevel)
+
+	defer func() {
+		entry, ok := recover().(*Entry)
+		if !ok {
+			t.Fatalf("recover type %T is wrong", entry)
+		}
+
+		if entry.Level != PanicLevel || entry.Message != "panic 1" || entry.Data["key"] != "value" {
+			t.Fatalf("entry %+v is wrong", entry)
+		}
+
+		entries := parseTestEntries(t, buffer)
+		if len(entries) != 1 || entries[0].Msg != "panic 1" {
+			t.Fatalf("entries %+v is wrong", entries)
+		}
+	}()
+
+	logger.WithField("key", "value").Panicf("panic %d", 1)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntryFatal$
+func TestEntryFatal(t *testing.T) {
+	exitCodes := make([]int, 0, 4)
+	exit = func(code int) {
+		exitCodes = append(exitCodes, code)
+	}
+
+	defer func() {
+		exit = os.Exit
+	}()
+
+	logger, buffer := newTestLogger(t, InfoLevel)
+	logger.Fatal("fatal")
+	logger.WithField("key", "value").Fatalln("fatal", 1)
+
+	if len(exitCodes) != 2 || exitCodes[0] != 1 || exitCodes[1] != 1 {
+		t.Fatalf("exitCodes %+v is wrong", exitCodes)
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != 2 || entries[0].Level != "FATAL" || entries[1].Key != "value" {
+		t.Fatalf("entries %+v is wrong", entries)
+	}
+}
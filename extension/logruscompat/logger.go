@@ -0,0 +1,331 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logruscompat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// callerSkip is the count of frames between the caller of Logger or Entry and logit.Logger.Log.
+	callerSkip = 2
+)
+
+var (
+	// ErrorKey is the key of the error added by WithError.
+	ErrorKey = "error"
+
+	// exit is the function exiting the process, and it's a variable for testing.
+	exit = os.Exit
+)
+
+// Fields is a map of fields like logrus.Fields.
+type Fields map[string]any
+
+// Level is the level like logrus.Level.
+// The lower level is more severe, which is different from slog.Level.
+type Level uint32
+
+const (
+	PanicLevel Level = iota
+	FatalLevel
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+// AllLevels are all levels in order of severity.
+var AllLevels = []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel, InfoLevel, DebugLevel, TraceLevel}
+
+// String returns the name of level in lower case like logrus.
+func (l Level) String() string {
+	switch l {
+	case PanicLevel:
+		return "panic"
+	case FatalLevel:
+		return "fatal"
+	case ErrorLevel:
+		return "error"
+	case WarnLevel:
+		return "warning"
+	case InfoLevel:
+		return "info"
+	case DebugLevel:
+		return "debug"
+	case TraceLevel:
+		return "trace"
+	default:
+		return "unknown"
+	}
+}
+
+// slogLevel returns the slog level mapped from level.
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case PanicLevel:
+		return logit.LevelPanic
+	case FatalLevel:
+		return logit.LevelFatal
+	case ErrorLevel:
+		return slog.LevelError
+	case WarnLevel:
+		return slog.LevelWarn
+	case InfoLevel:
+		return slog.LevelInfo
+	case DebugLevel:
+		return slog.LevelDebug
+	default:
+		return logit.LevelTrace
+	}
+}
+
+// Hook is a hook fired before logging in its levels like logrus.Hook.
+// A logrus hook can be migrated by replacing logrus.Level and logrus.Entry with types in this package.
+type Hook interface {
+	Levels() []Level
+	Fire(entry *Entry) error
+}
+
+// Logger is a facade having the common method set of logrus' Logger backed by logit.
+// It's useful for switching the backend of a large codebase to logit first and migrating call sites gradually.
+// Fields are mapped to attrs of logs and levels are mapped to levels of logit.
+type Logger struct {
+	logger *logit.Logger
+
+	hooks map[Level][]Hook
+	lock  sync.RWMutex
+}
+
+// NewLogger returns a logger backed by logger.
+func NewLogger(logger *logit.Logger) *Logger {
+	l := &Logger{
+		logger: logger,
+		hooks:  make(map[Level][]Hook, len(AllLevels)),
+	}
+
+	return l
+}
+
+// Logger returns the logit logger backing the logger.
+func (l *Logger) Logger() *logit.Logger {
+	return l.logger
+}
+
+// AddHook adds hook to the logger so it will be fired before logging in its levels.
+func (l *Logger) AddHook(hook Hook) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, level := range hook.Levels() {
+		l.hooks[level] = append(l.hooks[level], hook)
+	}
+}
+
+// fireHooks fires all hooks in entry's level and reports errors to defaults.HandleError.
+func (l *Logger) fireHooks(entry *Entry) {
+	l.lock.RLock()
+	hooks := l.hooks[entry.Level]
+	l.lock.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook.Fire(entry); err != nil {
+			defaults.HandleError("Hook.Fire", err)
+		}
+	}
+}
+
+// IsLevelEnabled reports whether logs in level will be logged.
+func (l *Logger) IsLevelEnabled(level Level) bool {
+	return l.logger.Enabled(context.Background(), level.slogLevel())
+}
+
+// Sync syncs the logger and returns an error if failed.
+func (l *Logger) Sync() error {
+	return l.logger.Sync()
+}
+
+func (l *Logger) newEntry() *Entry {
+	return &Entry{Logger: l}
+}
+
+// WithField returns an entry with the field.
+func (l *Logger) WithField(key string, value any) *Entry {
+	return l.newEntry().WithField(key, value)
+}
+
+// WithFields returns an entry with fields.
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return l.newEntry().WithFields(fields)
+}
+
+// WithError returns an entry with err as the field named ErrorKey.
+func (l *Logger) WithError(err error) *Entry {
+	return l.newEntry().WithError(err)
+}
+
+// WithContext returns an entry with ctx which will be passed to the handler.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	return l.newEntry().WithContext(ctx)
+}
+
+// Trace logs args in trace level like fmt.Sprint.
+func (l *Logger) Trace(args ...any) {
+	l.newEntry().log(TraceLevel, fmt.Sprint(args...))
+}
+
+// Tracef logs a message formatted with format and args in trace level.
+func (l *Logger) Tracef(format string, args ...any) {
+	l.newEntry().log(TraceLevel, fmt.Sprintf(format, args...))
+}
+
+// Traceln logs args in trace level like fmt.Sprintln.
+func (l *Logger) Traceln(args ...any) {
+	l.newEntry().log(TraceLevel, sprintln(args))
+}
+
+// Debug logs args in debug level like fmt.Sprint.
+func (l *Logger) Debug(args ...any) {
+	l.newEntry().log(DebugLevel, fmt.Sprint(args...))
+}
+
+// Debugf logs a message formatted with format and args in debug level.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.newEntry().log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Debugln logs args in debug level like fmt.Sprintln.
+func (l *Logger) Debugln(args ...any) {
+	l.newEntry().log(DebugLevel, sprintln(args))
+}
+
+// Info logs args in info level like fmt.Sprint.
+func (l *Logger) Info(args ...any) {
+	l.newEntry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+// Infof logs a message formatted with format and args in info level.
+func (l *Logger) Infof(format string, args ...any) {
+	l.newEntry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Infoln logs args in info level like fmt.Sprintln.
+func (l *Logger) Infoln(args ...any) {
+	l.newEntry().log(InfoLevel, sprintln(args))
+}
+
+// Print logs args in info level like fmt.Sprint.
+func (l *Logger) Print(args ...any) {
+	l.newEntry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+// Printf logs a message formatted with format and args in info level.
+func (l *Logger) Printf(format string, args ...any) {
+	l.newEntry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Println logs args in info level like fmt.Sprintln.
+func (l *Logger) Println(args ...any) {
+	l.newEntry().log(InfoLevel, sprintln(args))
+}
+
+// Warn logs args in warn level like fmt.Sprint.
+func (l *Logger) Warn(args ...any) {
+	l.newEntry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+// Warnf logs a message formatted with format and args in warn level.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.newEntry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Warnln logs args in warn level like fmt.Sprintln.
+func (l *Logger) Warnln(args ...any) {
+	l.newEntry().log(WarnLevel, sprintln(args))
+}
+
+// Warning logs args in warn level like fmt.Sprint.
+func (l *Logger) Warning(args ...any) {
+	l.newEntry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+// Warningf logs a message formatted with format and args in warn level.
+func (l *Logger) Warningf(format string, args ...any) {
+	l.newEntry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Warningln logs args in warn level like fmt.Sprintln.
+func (l *Logger) Warningln(args ...any) {
+	l.newEntry().log(WarnLevel, sprintln(args))
+}
+
+// Error logs args in error level like fmt.Sprint.
+func (l *Logger) Error(args ...any) {
+	l.newEntry().log(ErrorLevel, fmt.Sprint(args...))
+}
+
+// Errorf logs a message formatted with format and args in error level.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.newEntry().log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Errorln logs args in error level like fmt.Sprintln.
+func (l *Logger) Errorln(args ...any) {
+	l.newEntry().log(ErrorLevel, sprintln(args))
+}
+
+// Fatal logs args in fatal level like fmt.Sprint.
+// It syncs the logger and exits the process with code 1 after logging.
+func (l *Logger) Fatal(args ...any) {
+	l.newEntry().log(FatalLevel, fmt.Sprint(args...))
+}
+
+// Fatalf logs a message formatted with format and args in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.newEntry().log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatalln logs args in fatal level like fmt.Sprintln.
+// It syncs the logger and exits the process with code 1 after logging.
+func (l *Logger) Fatalln(args ...any) {
+	l.newEntry().log(FatalLevel, sprintln(args))
+}
+
+// Panic logs args in panic level like fmt.Sprint.
+// It syncs the logger and panics with the entry after logging.
+func (l *Logger) Panic(args ...any) {
+	l.newEntry().log(PanicLevel, fmt.Sprint(args...))
+}
+
+// Panicf logs a message formatted with format and args in panic level.
+// It syncs the logger and panics with the entry after logging.
+func (l *Logger) Panicf(format string, args ...any) {
+	l.newEntry().log(PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// Panicln logs args in panic level like fmt.Sprintln.
+// It syncs the logger and panics with the entry after logging.
+func (l *Logger) Panicln(args ...any) {
+	l.newEntry().log(PanicLevel, sprintln(args))
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logruscompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+)
+
+type testEntry struct {
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Key    string `json:"key"`
+	Number int    `json:"number"`
+	Error  string `json:"error"`
+	Hooked bool   `json:"hooked"`
+	Source struct {
+		File string `json:"file"`
+	} `json:"source"`
+}
+
+func newTestLogger(t *testing.T, level Level) (*Logger, *bytes.Buffer) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithJsonHandler(), logit.WithLevel(level.slogLevel()), logit.WithSource())
+
+	return NewLogger(logger), buffer
+}
+
+func parseTestEntries(t *testing.T, buffer *bytes.Buffer) []testEntry {
+	var entries []testEntry
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n")) {
+		if len(line) <= 0 {
+			continue
+		}
+
+		var entry testEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasSuffix(filepath.Base(entry.Source.File), "_test.go") {
+			t.Fatalf("entry.Source.File %s is wrong", entry.Source.File)
+		}
+
+		entry.Source.File = ""
+		entries = append(entries, entry)
+	}
+
+	buffer.Reset()
+	return entries
+}
+
+type testHook struct {
+	levels []Level
+	fired  int
+	err    error
+}
+
+func (th *testHook) Levels() []Level {
+	return th.levels
+}
+
+func (th *testHook) Fire(entry *Entry) error {
+	th.fired++
+	entry.Data["hooked"] = true
+
+	return th.err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevel$
+func TestLevel(t *testing.T) {
+	names := []string{"panic", "fatal", "error", "warning", "info", "debug", "trace"}
+	for i, level := range AllLevels {
+		if level.String() != names[i] {
+			t.Fatalf("level.String() %s != names[i] %s", level.String(), names[i])
+		}
+	}
+
+	if Level(100).String() != "unknown" {
+		t.Fatalf("Level(100).String() %s is wrong", Level(100).String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLogger$
+func TestLogger(t *testing.T) {
+	logger, buffer := newTestLogger(t, DebugLevel)
+	logger.Trace("trace")
+	logger.Debug("debug", 1)
+	logger.Infof("info %d", 2)
+	logger.Print("print")
+	logger.Warningln("warning", 3)
+	logger.WithError(errors.New("failed")).Error("error")
+	logger.WithFields(Fields{"key": "value", "number": 4}).Warn("fields")
+
+	want := []testEntry{
+		{Level: "DEBUG", Msg: "debug1"},
+		{Level: "INFO", Msg: "info 2"},
+		{Level: "INFO", Msg: "print"},
+		{Level: "WARN", Msg: "warning 3"},
+		{Level: "ERROR", Msg: "error", Error: "failed"},
+		{Level: "WARN", Msg: "fields", Key: "value", Number: 4},
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != len(want) {
+		t.Fatalf("len(entries) %d != len(want) %d", len(entries), len(want))
+	}
+
+	for i, entry := range entries {
+		if entry != want[i] {
+			t.Fatalf("entry %+v != want %+v", entry, want[i])
+		}
+	}
+
+	if logger.IsLevelEnabled(TraceLevel) {
+		t.Fatal("trace level is enabled")
+	}
+
+	if !logger.IsLevelEnabled(DebugLevel) {
+		t.Fatal("debug level isn't enabled")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerAddHook$
+func TestLoggerAddHook(t *testing.T) {
+	errs := make([]error, 0, 4)
+	handleError := defaults.HandleError
+	defaults.HandleError = func(label string, err error) {
+		errs = append(errs, err)
+	}
+
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	logger, buffer := newTestLogger(t, InfoLevel)
+
+	hook := &testHook{levels: []Level{ErrorLevel, WarnLevel}, err: errors.New("hook failed")}
+	logger.AddHook(hook)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.WithField("key", "value").Error("error")
+
+	if hook.fired != 2 {
+		t.Fatalf("hook.fired %d != 2", hook.fired)
+	}
+
+	if len(errs) != 2 || errs[0] != hook.err {
+		t.Fatalf("errs %+v is wrong", errs)
+	}
+
+	want := []testEntry{
+		{Level: "INFO", Msg: "info"},
+		{Level: "WARN", Msg: "warn", Hooked: true},
+		{Level: "ERROR", Msg: "error", Key: "value", Hooked: true},
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != len(want) {
+		t.Fatalf("len(entries) %d != len(want) %d", len(entries), len(want))
+	}
+
+	for i, entry := range entries {
+		if entry != want[i] {
+			t.Fatalf("entry %+v != want %+v", entry, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerClose$
+func TestLoggerClose(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithJsonHandler(), logit.WithStats())
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	NewLogger(logger).WithField("key", "value").Info("closed")
+
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer %s should be empty after closing", buffer.String())
+	}
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("logger.Stats() should be ok")
+	}
+
+	if stats.Dropped != 1 {
+		t.Fatalf("stats.Dropped %d != 1", stats.Dropped)
+	}
+}
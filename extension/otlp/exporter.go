@@ -0,0 +1,47 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/FishGoddess/logit/extension/httpwriter"
+)
+
+const (
+	// scopeName is the name of instrumentation scope of logs exported.
+	scopeName = "github.com/FishGoddess/logit"
+)
+
+// NewExporter returns a writer exporting records written by otlp handler to endpoint over OTLP/HTTP in json.
+// The endpoint is the full url of logs, like "http://localhost:4318/v1/logs", and resource is the attrs of resource like "service.name".
+// Records are posted in batches with retries, and opts can customize them like the batch size, see httpwriter.Option.
+// Use logit.WithSyncTimer so records in batch will be exported periodically.
+func NewExporter(endpoint string, resource []slog.Attr, opts ...httpwriter.Option) *httpwriter.Writer {
+	// The attributes only have strings, numbers, bools and nested values, so marshaling them won't fail.
+	attributes, _ := json.Marshal(keyValues(resource, nil, nil))
+
+	prefix := `{"resourceLogs":[{"resource":{"attributes":` + string(attributes) + `},"scopeLogs":[{"scope":{"name":"` + scopeName + `"},"logRecords":[`
+	suffix := `]}]}]}`
+
+	exporterOpts := []httpwriter.Option{
+		httpwriter.WithHeader("Content-Type", "application/json"),
+		httpwriter.WithEnvelope(prefix, ",", suffix),
+	}
+
+	exporterOpts = append(exporterOpts, opts...)
+	return httpwriter.New(endpoint, exporterOpts...)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/extension/httpwriter"
+)
+
+type testRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []keyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []logRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewExporter$
+func TestNewExporter(t *testing.T) {
+	var requests []testRequest
+	var contentTypes []string
+	var lock sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var request testRequest
+		if err = json.Unmarshal(body, &request); err != nil {
+			t.Errorf("unmarshal body %s failed: %+v", body, err)
+			return
+		}
+
+		requests = append(requests, request)
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+	}))
+
+	defer server.Close()
+
+	exporter := NewExporter(server.URL+"/v1/logs", []slog.Attr{slog.String("service.name", "app")}, httpwriter.WithBatchSize(2))
+
+	logger := logit.NewLogger(logit.WithHandler(Handler), logit.WithWriter(exporter))
+	logger.Info("msg1")
+	logger.Warn("msg2")
+	logger.Error("msg3")
+	logger.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(requests) != 2 {
+		t.Fatalf("len(requests) %d != 2", len(requests))
+	}
+
+	for i, request := range requests {
+		if contentTypes[i] != "application/json" {
+			t.Fatalf("contentTypes[%d] %s != 'application/json'", i, contentTypes[i])
+		}
+
+		resourceLogs := request.ResourceLogs[0]
+		if attr := resourceLogs.Resource.Attributes[0]; attr.Key != "service.name" || attr.Value["stringValue"] != "app" {
+			t.Fatalf("attr %+v is wrong", attr)
+		}
+
+		if name := resourceLogs.ScopeLogs[0].Scope.Name; name != scopeName {
+			t.Fatalf("name %s != scopeName %s", name, scopeName)
+		}
+	}
+
+	records := append(requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords, requests[1].ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	wants := []string{"msg1", "msg2", "msg3"}
+
+	if len(records) != len(wants) {
+		t.Fatalf("len(records) %d != len(wants) %d", len(records), len(wants))
+	}
+
+	for i, record := range records {
+		if record.Body["stringValue"] != wants[i] {
+			t.Fatalf("record.Body %+v != want %s", record.Body, wants[i])
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp provides a handler converting records to the OpenTelemetry log data model and an exporter posting them over OTLP/HTTP,
+// so logit can feed an OpenTelemetry collector directly:
+//
+//	exporter := otlp.NewExporter("http://localhost:4318/v1/logs", []slog.Attr{slog.String("service.name", "app")})
+//	logger := logit.NewLogger(logit.WithHandler(otlp.Handler), logit.WithWriter(exporter), logit.WithSyncTimer(time.Second))
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/handler"
+)
+
+const (
+	// Handler is the name of otlp handler registered to logit, see logit.WithHandler.
+	Handler = "otlp"
+)
+
+func init() {
+	if err := handler.Register(Handler, NewHandler); err != nil {
+		panic(err)
+	}
+}
+
+type keyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano,omitempty"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 map[string]any `json:"body"`
+	Attributes           []keyValue     `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+// severityNumber converts level to the severity number of OpenTelemetry, which is 9 for info and 1 to 24 in total.
+func severityNumber(level slog.Level) int {
+	return min(max(int(level)+9, 1), 24)
+}
+
+// anyValue converts value to the AnyValue of OpenTelemetry in json.
+// Int64 values are encoded as strings as the json mapping of protobuf requires.
+func anyValue(value slog.Value, replaceAttr func(groups []string, attr slog.Attr) slog.Attr, groups []string) map[string]any {
+	switch value.Kind() {
+	case slog.KindString:
+		return map[string]any{"stringValue": value.String()}
+	case slog.KindInt64:
+		return map[string]any{"intValue": strconv.FormatInt(value.Int64(), 10)}
+	case slog.KindUint64:
+		return map[string]any{"intValue": strconv.FormatUint(value.Uint64(), 10)}
+	case slog.KindFloat64:
+		// Json doesn't support NaN and Inf, so they're encoded as strings.
+		if f := value.Float64(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return map[string]any{"stringValue": strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+
+		return map[string]any{"doubleValue": value.Float64()}
+	case slog.KindBool:
+		return map[string]any{"boolValue": value.Bool()}
+	case slog.KindDuration:
+		return map[string]any{"stringValue": value.Duration().String()}
+	case slog.KindTime:
+		return map[string]any{"stringValue": value.Time().Format(time.RFC3339Nano)}
+	case slog.KindGroup:
+		values := keyValues(value.Group(), replaceAttr, groups)
+		return map[string]any{"kvlistValue": map[string]any{"values": values}}
+	default:
+		switch v := value.Any().(type) {
+		case []byte:
+			return map[string]any{"bytesValue": v}
+		case error:
+			return map[string]any{"stringValue": v.Error()}
+		default:
+			return map[string]any{"stringValue": fmt.Sprintf("%+v", v)}
+		}
+	}
+}
+
+// keyValues converts attrs in groups to the KeyValues of OpenTelemetry.
+// Attrs in groups are nested in kvlist values, and attrs having empty keys are dropped like slog.
+func keyValues(attrs []slog.Attr, replaceAttr func(groups []string, attr slog.Attr) slog.Attr, groups []string) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		if attr.Value.Kind() == slog.KindGroup {
+			if attr.Key == "" {
+				kvs = append(kvs, keyValues(attr.Value.Group(), replaceAttr, groups)...)
+				continue
+			}
+
+			nested := keyValues(attr.Value.Group(), replaceAttr, append(slices.Clip(groups), attr.Key))
+			if len(nested) > 0 {
+				kvs = append(kvs, keyValue{Key: attr.Key, Value: map[string]any{"kvlistValue": map[string]any{"values": nested}}})
+			}
+
+			continue
+		}
+
+		if replaceAttr != nil {
+			attr = replaceAttr(groups, attr)
+			attr.Value = attr.Value.Resolve()
+		}
+
+		if attr.Key == "" {
+			continue
+		}
+
+		kvs = append(kvs, keyValue{Key: attr.Key, Value: anyValue(attr.Value, replaceAttr, append(slices.Clip(groups), attr.Key))})
+	}
+
+	return kvs
+}
+
+// groupOrAttrs is a group or attrs added by WithGroup or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// nestAttrs nests attrs in groups and attrs of goas in order.
+func nestAttrs(goas []groupOrAttrs, attrs []slog.Attr) []slog.Attr {
+	if len(goas) <= 0 {
+		return attrs
+	}
+
+	goa := goas[0]
+	if goa.group == "" {
+		return append(slices.Clip(goa.attrs), nestAttrs(goas[1:], attrs)...)
+	}
+
+	nested := nestAttrs(goas[1:], attrs)
+	if len(nested) <= 0 {
+		return nil
+	}
+
+	return []slog.Attr{{Key: goa.group, Value: slog.GroupValue(nested...)}}
+}
+
+type otlpHandler struct {
+	w    io.Writer
+	opts slog.HandlerOptions
+	goas []groupOrAttrs
+	lock *sync.Mutex
+}
+
+// NewHandler creates an otlp handler with w and opts.
+// This handler writes each record as a LogRecord of OpenTelemetry in json in one write, without '\n'.
+// Attrs in groups are nested in kvlist values, and the trace id and span id in context are set to the record, see logit.StartSpan.
+// The replaceAttr in opts won't be called with time, level, message and source, which are fields of LogRecord instead of attrs.
+// Use it with NewExporter, which wraps the records in batch as a request of OTLP/HTTP.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+
+	handler := &otlpHandler{
+		w:    w,
+		opts: *opts,
+		lock: &sync.Mutex{},
+	}
+
+	return handler
+}
+
+// WithAttrs returns a new handler with attrs.
+func (oh *otlpHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return oh
+	}
+
+	handler := *oh
+	handler.goas = append(slices.Clip(oh.goas), groupOrAttrs{attrs: attrs})
+
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (oh *otlpHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return oh
+	}
+
+	handler := *oh
+	handler.goas = append(slices.Clip(oh.goas), groupOrAttrs{group: name})
+
+	return &handler
+}
+
+// Enabled reports whether the logger should ignore logs whose level is lower than passed level.
+func (oh *otlpHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= oh.opts.Level.Level()
+}
+
+func (oh *otlpHandler) newLogRecord(ctx context.Context, record slog.Record) logRecord {
+	lr := logRecord{
+		ObservedTimeUnixNano: strconv.FormatInt(defaults.CurrentTime().UnixNano(), 10),
+		SeverityNumber:       severityNumber(record.Level),
+		SeverityText:         handler.LevelName(record.Level),
+		Body:                 map[string]any{"stringValue": record.Message},
+	}
+
+	if !record.Time.IsZero() {
+		lr.TimeUnixNano = strconv.FormatInt(record.Time.UnixNano(), 10)
+	}
+
+	if ctx != nil {
+		lr.TraceID, lr.SpanID, _ = logit.TraceIDs(ctx)
+	}
+
+	if oh.opts.AddSource && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+
+		lr.Attributes = append(lr.Attributes,
+			keyValue{Key: "code.filepath", Value: map[string]any{"stringValue": frame.File}},
+			keyValue{Key: "code.lineno", Value: map[string]any{"intValue": strconv.Itoa(frame.Line)}},
+			keyValue{Key: "code.function", Value: map[string]any{"stringValue": frame.Function}},
+		)
+	}
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	attrs = nestAttrs(oh.goas, attrs)
+	lr.Attributes = append(lr.Attributes, keyValues(attrs, oh.opts.ReplaceAttr, nil)...)
+
+	return lr
+}
+
+// Handle handles one record and returns an error if failed.
+func (oh *otlpHandler) Handle(ctx context.Context, record slog.Record) error {
+	bs, err := json.Marshal(oh.newLogRecord(ctx, record))
+	if err != nil {
+		return err
+	}
+
+	oh.lock.Lock()
+	defer oh.lock.Unlock()
+
+	_, err = oh.w.Write(bs)
+	return err
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSeverityNumber$
+func TestSeverityNumber(t *testing.T) {
+	levels := []slog.Level{logit.LevelTrace - 4, logit.LevelTrace, slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, logit.LevelFatal}
+	wants := []int{1, 1, 5, 9, 13, 17, 24}
+
+	for i, level := range levels {
+		if got := severityNumber(level); got != wants[i] {
+			t.Fatalf("level %s: got %d != want %d", level, got, wants[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHandler$
+func TestHandler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	defaults.CurrentTime = func() time.Time { return now }
+	defer func() { defaults.CurrentTime = time.Now }()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	handler := NewHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == "password" {
+			return slog.String(attr.Key, "***")
+		}
+
+		return attr
+	}})
+
+	logger := slog.New(handler).With("service", "app").WithGroup("request").With("id", 1)
+
+	record := slog.NewRecord(now, slog.LevelInfo, "msg", 0)
+	record.Add("password", "123456", "cost", time.Second, "err", errors.New("err"), "nan", math.NaN())
+
+	if err := logger.Handler().WithGroup("empty").Handle(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"timeUnixNano":"1700000000000000000","observedTimeUnixNano":"1700000000000000000","severityNumber":9,"severityText":"INFO","body":{"stringValue":"msg"},` +
+		`"attributes":[{"key":"service","value":{"stringValue":"app"}},{"key":"request","value":{"kvlistValue":{"values":[{"key":"id","value":{"intValue":"1"}},` +
+		`{"key":"empty","value":{"kvlistValue":{"values":[{"key":"password","value":{"stringValue":"***"}},{"key":"cost","value":{"stringValue":"1s"}},` +
+		`{"key":"err","value":{"stringValue":"err"}},{"key":"nan","value":{"stringValue":"NaN"}}]}}}]}}}]}`
+
+	if got := buffer.String(); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	buffer.Reset()
+	logger.WithGroup("empty").Warn("msg")
+
+	if got := buffer.String(); !strings.Contains(got, `"severityNumber":13,"severityText":"WARN"`) || strings.Contains(got, "empty") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	slog.New(handler).Debug("msg")
+
+	if got := buffer.String(); got != "" {
+		t.Fatalf("got %s != ''", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHandlerTraceAndSource$
+func TestHandlerTraceAndSource(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := slog.New(NewHandler(buffer, &slog.HandlerOptions{AddSource: true}))
+	ctx := logit.StartSpan(context.Background())
+	logger.InfoContext(ctx, "msg", "bytes", []byte("abc"))
+
+	traceID, spanID, _ := logit.TraceIDs(ctx)
+
+	got := buffer.String()
+	for _, want := range []string{`"traceId":"` + traceID + `"`, `"spanId":"` + spanID + `"`, `{"key":"code.filepath","value":{"stringValue":"`, `handler_test.go"}}`, `{"key":"bytes","value":{"bytesValue":"YWJj"}}`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("got %s doesn't contain %s", got, want)
+		}
+	}
+}
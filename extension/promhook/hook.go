@@ -0,0 +1,142 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/handler"
+)
+
+const (
+	// contentType is the content type of the Prometheus text format.
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// Hook is a logit.Hook counting records by level and errors of handling records.
+// The counters are exported in the Prometheus text format, so Prometheus can scrape them from Hook as an http.Handler,
+// and dashboards can show the rate of error logs directly without parsing logs.
+// Records skipped by logit.ErrSkipRecord aren't counted.
+// Use Records and HandleErrors if you want to export the counters with your own metrics library.
+type Hook struct {
+	namespace string
+
+	// records stores the count of records by level, and the values are *atomic.Uint64.
+	records      sync.Map
+	handleErrors atomic.Uint64
+}
+
+// New returns a new hook with namespace which is the prefix of metric names.
+// The namespace is "logit" if it's empty, so the metrics are logit_log_records_total and logit_log_handle_errors_total.
+func New(namespace string) *Hook {
+	if namespace == "" {
+		namespace = "logit"
+	}
+
+	hook := &Hook{
+		namespace: namespace,
+	}
+
+	return hook
+}
+
+// Before does nothing because records are counted after being handled.
+func (h *Hook) Before(ctx context.Context, record *slog.Record) error {
+	return nil
+}
+
+// After counts the record by its level and counts the error if it's not nil.
+func (h *Hook) After(ctx context.Context, record slog.Record, err error) {
+	if errors.Is(err, logit.ErrSkipRecord) {
+		return
+	}
+
+	counter, ok := h.records.Load(record.Level)
+	if !ok {
+		counter, _ = h.records.LoadOrStore(record.Level, new(atomic.Uint64))
+	}
+
+	counter.(*atomic.Uint64).Add(1)
+
+	if err != nil {
+		h.handleErrors.Add(1)
+	}
+}
+
+// Records returns the count of records by level.
+func (h *Hook) Records() map[slog.Level]uint64 {
+	records := make(map[slog.Level]uint64, 8)
+
+	h.records.Range(func(key, value any) bool {
+		records[key.(slog.Level)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+
+	return records
+}
+
+// HandleErrors returns the count of errors of handling records.
+func (h *Hook) HandleErrors() uint64 {
+	return h.handleErrors.Load()
+}
+
+// WriteTo writes all metrics to writer in the Prometheus text format.
+// Levels are output with their registered names, see logit.RegisterLevel.
+func (h *Hook) WriteTo(writer io.Writer) (int64, error) {
+	records := h.Records()
+
+	levels := make([]slog.Level, 0, len(records))
+	for level := range records {
+		levels = append(levels, level)
+	}
+
+	slices.Sort(levels)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# HELP %s_log_records_total Count of records logged by level.\n", h.namespace)
+	fmt.Fprintf(&builder, "# TYPE %s_log_records_total counter\n", h.namespace)
+
+	for _, level := range levels {
+		name := labelReplacer.Replace(handler.LevelName(level))
+		fmt.Fprintf(&builder, "%s_log_records_total{level=\"%s\"} %d\n", h.namespace, name, records[level])
+	}
+
+	fmt.Fprintf(&builder, "# HELP %s_log_handle_errors_total Count of errors of handling records.\n", h.namespace)
+	fmt.Fprintf(&builder, "# TYPE %s_log_handle_errors_total counter\n", h.namespace)
+	fmt.Fprintf(&builder, "%s_log_handle_errors_total %d\n", h.namespace, h.HandleErrors())
+
+	n, err := io.WriteString(writer, builder.String())
+	return int64(n), err
+}
+
+// ServeHTTP writes all metrics to response in the Prometheus text format, so Hook can be scraped by Prometheus.
+func (h *Hook) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", contentType)
+	h.WriteTo(response)
+}
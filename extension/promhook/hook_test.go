@@ -0,0 +1,110 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit"
+)
+
+type failedWriter struct {
+	bytes.Buffer
+}
+
+func (fw *failedWriter) Write(p []byte) (n int, err error) {
+	if strings.Contains(string(p), "fail") {
+		return 0, errors.New("fail")
+	}
+
+	return fw.Buffer.Write(p)
+}
+
+type skipHook struct{}
+
+func (skipHook) Before(ctx context.Context, record *slog.Record) error {
+	if record.Message == "skip" {
+		return logit.ErrSkipRecord
+	}
+
+	return nil
+}
+
+func (skipHook) After(ctx context.Context, record slog.Record, err error) {}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHook$
+func TestHook(t *testing.T) {
+	hook := New("")
+
+	logger := logit.NewLogger(logit.WithWriter(new(failedWriter)), logit.WithTraceLevel(), logit.WithHooks(hook, skipHook{}))
+	logger.Trace("msg")
+	logger.Info("msg")
+	logger.Info("msg")
+	logger.Info("skip")
+	logger.Error("fail")
+	logger.Close()
+
+	records := hook.Records()
+	if len(records) != 3 || records[logit.LevelTrace] != 1 || records[slog.LevelInfo] != 2 || records[slog.LevelError] != 1 {
+		t.Fatalf("records %+v is wrong", records)
+	}
+
+	if hook.HandleErrors() != 1 {
+		t.Fatalf("hook.HandleErrors() %d != 1", hook.HandleErrors())
+	}
+
+	recorder := httptest.NewRecorder()
+	hook.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := recorder.Header().Get("Content-Type"); got != contentType {
+		t.Fatalf("got %s != contentType %s", got, contentType)
+	}
+
+	want := `# HELP logit_log_records_total Count of records logged by level.
+# TYPE logit_log_records_total counter
+logit_log_records_total{level="TRACE"} 1
+logit_log_records_total{level="INFO"} 2
+logit_log_records_total{level="ERROR"} 1
+# HELP logit_log_handle_errors_total Count of errors of handling records.
+# TYPE logit_log_handle_errors_total counter
+logit_log_handle_errors_total 1
+`
+
+	if got := recorder.Body.String(); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHookWriteTo$
+func TestHookWriteTo(t *testing.T) {
+	hook := New("app")
+	hook.After(context.Background(), slog.NewRecord(time.Now(), slog.Level(3), "msg", 0), nil)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	if _, err := hook.WriteTo(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); !strings.Contains(got, "app_log_records_total{level=\"INFO+3\"} 1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
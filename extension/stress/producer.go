@@ -0,0 +1,184 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stress
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit"
+)
+
+const (
+	// marker is the prefix of messages logged by producers, so they can be found in output.
+	marker = "stress:"
+
+	// payloadChars are the chars used in payloads, which won't be escaped or quoted by handlers.
+	payloadChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Producer describes goroutines producing logs to a logger.
+type Producer struct {
+	// Name is the name of producer and will be carried by its logs.
+	// It should be unique and only contain letters, digits and underscores.
+	Name string
+
+	// Goroutines is the count of goroutines producing logs, which is 1 if it's not positive.
+	Goroutines int
+
+	// Logs is the count of logs produced by each goroutine.
+	Logs int
+
+	// Rate is the count of logs produced by each goroutine per second.
+	// Logs will be produced as fast as possible if it's not positive.
+	Rate int
+
+	// PayloadSize is the size of payload carried by each log.
+	PayloadSize int
+
+	// Levels are the levels of logs used in turn, which is slog.LevelInfo if empty.
+	// Logs of levels not enabled by logger will be skipped and won't be expected in output.
+	Levels []slog.Level
+}
+
+func (p Producer) goroutines() int {
+	if p.Goroutines <= 0 {
+		return 1
+	}
+
+	return p.Goroutines
+}
+
+func (p Producer) level(seq int) slog.Level {
+	if len(p.Levels) <= 0 {
+		return slog.LevelInfo
+	}
+
+	return p.Levels[seq%len(p.Levels)]
+}
+
+// Report reports what have been produced by Run, which is used for verifying output.
+type Report struct {
+	// Producers are the producers run.
+	Producers []Producer
+
+	// Sent is the count of logs sent to logger.
+	Sent uint64
+
+	// Skipped is the count of logs skipped because their levels aren't enabled by logger.
+	Skipped uint64
+
+	// Duration is the duration of producing.
+	Duration time.Duration
+
+	// skipped records sequences skipped by streams.
+	skipped map[stream]map[int]struct{}
+}
+
+// stream is the logs produced by one goroutine of a producer.
+type stream struct {
+	producer  string
+	goroutine int
+}
+
+func newPayload(size int, seed int) string {
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = payloadChars[(seed+i)%len(payloadChars)]
+	}
+
+	return string(payload)
+}
+
+func checksum(body string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(body)))
+}
+
+// newMessage returns a message carrying the stream, sequence and payload with a checksum of them.
+// It looks like "stress:name:0:1:payload:checksum".
+func newMessage(s stream, seq int, payload string) string {
+	body := s.producer + ":" + strconv.Itoa(s.goroutine) + ":" + strconv.Itoa(seq) + ":" + payload
+	return marker + body + ":" + checksum(body)
+}
+
+// Run runs producers against logger concurrently and returns a report after all logs are produced.
+// The logger will be synced after producing, so all logs should be in output if nothing is lost.
+// Use Verify to validate output with the report.
+func Run(logger *logit.Logger, producers ...Producer) Report {
+	report := Report{
+		Producers: producers,
+		skipped:   make(map[stream]map[int]struct{}, 16),
+	}
+
+	var sent atomic.Uint64
+	var wg sync.WaitGroup
+
+	ctx := context.Background()
+	slogger := logger.Slog()
+	begin := time.Now()
+
+	for _, producer := range producers {
+		for goroutine := 0; goroutine < producer.goroutines(); goroutine++ {
+			s := stream{producer: producer.Name, goroutine: goroutine}
+			skipped := make(map[int]struct{})
+			report.skipped[s] = skipped
+
+			wg.Add(1)
+			go func(producer Producer, s stream, skipped map[int]struct{}) {
+				defer wg.Done()
+
+				var ticker *time.Ticker
+				if producer.Rate > 0 {
+					ticker = time.NewTicker(time.Second / time.Duration(producer.Rate))
+					defer ticker.Stop()
+				}
+
+				for seq := 0; seq < producer.Logs; seq++ {
+					if ticker != nil {
+						<-ticker.C
+					}
+
+					level := producer.level(seq)
+					if !slogger.Enabled(ctx, level) {
+						skipped[seq] = struct{}{}
+						continue
+					}
+
+					payload := newPayload(producer.PayloadSize, s.goroutine+seq)
+					slogger.Log(ctx, level, newMessage(s, seq, payload))
+					sent.Add(1)
+				}
+			}(producer, s, skipped)
+		}
+	}
+
+	wg.Wait()
+	logger.Sync()
+
+	report.Sent = sent.Load()
+	report.Duration = time.Since(begin)
+
+	for _, skipped := range report.skipped {
+		report.Skipped += uint64(len(skipped))
+	}
+
+	return report
+}
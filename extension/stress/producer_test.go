@@ -0,0 +1,69 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stress
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewMessage$
+func TestNewMessage(t *testing.T) {
+	s := stream{producer: "api", goroutine: 2}
+	msg := newMessage(s, 7, newPayload(4, 0))
+
+	if !strings.HasPrefix(msg, "stress:api:2:7:abcd:") || len(msg) != len("stress:api:2:7:abcd:")+checksumSize {
+		t.Fatalf("msg %s is wrong", msg)
+	}
+
+	if payload := newPayload(3, len(payloadChars)-1); payload != "9ab" {
+		t.Fatalf("payload %s is wrong", payload)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRun$
+func TestRun(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithInfoLevel())
+
+	producer := Producer{
+		Name:        "api",
+		Goroutines:  1,
+		Logs:        10,
+		Rate:        1000,
+		PayloadSize: 16,
+		Levels:      []slog.Level{slog.LevelDebug, slog.LevelInfo},
+	}
+
+	report := Run(logger, producer)
+	if report.Sent != 5 || report.Skipped != 5 || report.Duration <= 0 {
+		t.Fatalf("report %+v is wrong", report)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("len(lines) %d != 5", len(lines))
+	}
+
+	for _, line := range lines {
+		if !strings.Contains(line, "INFO") || !strings.Contains(line, "stress:api:0:") {
+			t.Fatalf("line %s is wrong", line)
+		}
+	}
+}
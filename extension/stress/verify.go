@@ -0,0 +1,253 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// checksumSize is the size of checksum in hex.
+	checksumSize = 8
+)
+
+// Result is the result of verifying output.
+type Result struct {
+	// Lines is the count of lines in output.
+	Lines uint64
+
+	// Records is the count of records verified, including duplicated ones.
+	Records uint64
+
+	// Lost is the count of records sent but not found in output.
+	Lost uint64
+
+	// Duplicated is the count of records found more than once.
+	Duplicated uint64
+
+	// Corrupted is the count of records broken, like a partial record or a record with wrong checksum.
+	Corrupted uint64
+
+	// Interleaved is the count of lines having more than one record, which means records are written concurrently without atomicity.
+	Interleaved uint64
+
+	// Unknown is the count of records not sent by producers in report.
+	Unknown uint64
+
+	// Foreign is the count of lines without any records, which are logged by others or torn from records.
+	Foreign uint64
+}
+
+// Err returns an error describing all problems found in output, or nil if output is integral.
+// Lost records are ignored if allowLoss is true, which is expected for lossy writers or sampling.
+func (r Result) Err(allowLoss bool) error {
+	var errs []error
+	if r.Lost > 0 && !allowLoss {
+		errs = append(errs, fmt.Errorf("logit: %d records lost", r.Lost))
+	}
+
+	if r.Duplicated > 0 {
+		errs = append(errs, fmt.Errorf("logit: %d records duplicated", r.Duplicated))
+	}
+
+	if r.Corrupted > 0 {
+		errs = append(errs, fmt.Errorf("logit: %d records corrupted", r.Corrupted))
+	}
+
+	if r.Interleaved > 0 {
+		errs = append(errs, fmt.Errorf("logit: %d lines interleaved", r.Interleaved))
+	}
+
+	if r.Unknown > 0 {
+		errs = append(errs, fmt.Errorf("logit: %d records unknown", r.Unknown))
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseRecord parses a record after the marker and returns its stream and sequence.
+// It reports false if the record is broken.
+func parseRecord(record string) (stream, int, bool) {
+	parts := strings.SplitN(record, ":", 5)
+	if len(parts) < 5 || len(parts[4]) < checksumSize {
+		return stream{}, 0, false
+	}
+
+	body := strings.Join(parts[:4], ":")
+	if parts[4][:checksumSize] != checksum(body) {
+		return stream{}, 0, false
+	}
+
+	goroutine, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return stream{}, 0, false
+	}
+
+	seq, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return stream{}, 0, false
+	}
+
+	return stream{producer: parts[0], goroutine: goroutine}, seq, true
+}
+
+type verifier struct {
+	report Report
+	result Result
+
+	// seen records the sequences seen by streams.
+	seen map[stream][]bool
+}
+
+func newVerifier(report Report) *verifier {
+	v := &verifier{
+		report: report,
+		seen:   make(map[stream][]bool, 16),
+	}
+
+	for _, producer := range report.Producers {
+		for goroutine := 0; goroutine < producer.goroutines(); goroutine++ {
+			s := stream{producer: producer.Name, goroutine: goroutine}
+			v.seen[s] = make([]bool, producer.Logs)
+		}
+	}
+
+	return v
+}
+
+func (v *verifier) verifyRecord(record string) {
+	s, seq, ok := parseRecord(record)
+	if !ok {
+		v.result.Corrupted++
+		return
+	}
+
+	v.result.Records++
+
+	seen, ok := v.seen[s]
+	if !ok || seq < 0 || seq >= len(seen) {
+		v.result.Unknown++
+		return
+	}
+
+	if _, skipped := v.report.skipped[s][seq]; skipped {
+		v.result.Unknown++
+		return
+	}
+
+	if seen[seq] {
+		v.result.Duplicated++
+		return
+	}
+
+	seen[seq] = true
+}
+
+func (v *verifier) verifyLine(line string) {
+	v.result.Lines++
+
+	records := strings.Split(line, marker)
+	if len(records) <= 1 {
+		v.result.Foreign++
+		return
+	}
+
+	if len(records) > 2 {
+		v.result.Interleaved++
+	}
+
+	for _, record := range records[1:] {
+		v.verifyRecord(record)
+	}
+}
+
+func (v *verifier) countLost() {
+	for s, seen := range v.seen {
+		skipped := v.report.skipped[s]
+
+		for seq, ok := range seen {
+			if _, isSkipped := skipped[seq]; !ok && !isSkipped {
+				v.result.Lost++
+			}
+		}
+	}
+}
+
+// Verify reads output from reader and verifies records in it with report returned by Run.
+// Each line should have one record, and records should be complete and neither lost nor duplicated.
+// It works with all handlers which keep messages unchanged, like tape, text and json.
+func Verify(reader io.Reader, report Report) (Result, error) {
+	v := newVerifier(report)
+	bufReader := bufio.NewReader(reader)
+
+	for {
+		line, err := bufReader.ReadString('\n')
+		if line != "" {
+			v.verifyLine(strings.TrimSuffix(line, "\n"))
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return v.result, err
+		}
+	}
+
+	v.countLost()
+	return v.result, nil
+}
+
+// JSONLine reports whether line is a complete json object, which is the output of json handler.
+func JSONLine(line []byte) bool {
+	line = bytes.TrimSpace(line)
+	return len(line) > 0 && line[0] == '{' && json.Valid(line)
+}
+
+// TornLines reads output from reader and returns the line numbers of torn lines, which start from 1.
+// A line is torn if it isn't valid, like a partial record or records interleaved, see JSONLine.
+// Unlike Verify, it works with any output, so you can check the output of production in place.
+func TornLines(reader io.Reader, valid func(line []byte) bool) ([]uint64, error) {
+	var torn []uint64
+	var number uint64
+
+	bufReader := bufio.NewReader(reader)
+	for {
+		line, err := bufReader.ReadBytes('\n')
+		if len(line) > 0 {
+			number++
+
+			if !valid(bytes.TrimSuffix(line, []byte{'\n'})) {
+				torn = append(torn, number)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return torn, nil
+		}
+
+		if err != nil {
+			return torn, err
+		}
+	}
+}
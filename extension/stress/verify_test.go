@@ -0,0 +1,160 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stress
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/writer"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseRecord$
+func TestParseRecord(t *testing.T) {
+	record := strings.TrimPrefix(newMessage(stream{producer: "api", goroutine: 1}, 2, "xyz"), marker)
+
+	s, seq, ok := parseRecord(record + `","level":"INFO"}`)
+	if !ok || s.producer != "api" || s.goroutine != 1 || seq != 2 {
+		t.Fatalf("s %+v, seq %d or ok %+v is wrong", s, seq, ok)
+	}
+
+	broken := []string{
+		record[:len(record)-1],
+		strings.Replace(record, "xyz", "xyy", 1),
+		"api:1:2",
+	}
+
+	for _, record := range broken {
+		if _, _, ok := parseRecord(record); ok {
+			t.Fatalf("record %s should be broken", record)
+		}
+	}
+}
+
+func runToFile(t *testing.T, opts ...logit.Option) (Report, string) {
+	path := filepath.Join(t.TempDir(), "stress.log")
+
+	opts = append([]logit.Option{logit.WithFile(path)}, opts...)
+	logger := logit.NewLogger(opts...)
+
+	producers := []Producer{
+		{Name: "api", Goroutines: 4, Logs: 200, PayloadSize: 64},
+		{Name: "db", Goroutines: 2, Logs: 100, PayloadSize: 1024},
+	}
+
+	report := Run(logger, producers...)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return report, string(data)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestVerify$
+func TestVerify(t *testing.T) {
+	handlers := []logit.Option{logit.WithTapeHandler(), logit.WithTextHandler(), logit.WithJsonHandler()}
+
+	for _, handler := range handlers {
+		report, output := runToFile(t, handler, logit.WithBuffer(4096))
+
+		result, err := Verify(strings.NewReader(output), report)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Records != 1000 || result.Lines != 1000 || result.Err(false) != nil {
+			t.Fatalf("result %+v is wrong", result)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestVerifyBroken$
+func TestVerifyBroken(t *testing.T) {
+	report, output := runToFile(t)
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+
+	// Lose lines[0], duplicate lines[1], interleave lines[2] with lines[3] and tear lines[4].
+	broken := []string{
+		lines[1],
+		lines[1],
+		lines[2][:len(lines[2])/2] + lines[3] + lines[2][len(lines[2])/2:],
+		lines[4][:len(lines[4])/2],
+		"foreign line",
+	}
+
+	broken = append(broken, lines[5:]...)
+	output = strings.Join(broken, "\n")
+
+	result, err := Verify(strings.NewReader(output), report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Lost != 3 || result.Duplicated != 1 || result.Interleaved != 1 || result.Corrupted != 2 || result.Foreign != 1 {
+		t.Fatalf("result %+v is wrong", result)
+	}
+
+	if err = result.Err(true); err == nil || strings.Contains(err.Error(), "lost") {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if err = result.Err(false); err == nil || !strings.Contains(err.Error(), "3 records lost") {
+		t.Fatalf("err %+v is wrong", err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTornLines$
+func TestTornLines(t *testing.T) {
+	report, output := runToFile(t, logit.WithJsonHandler(), logit.WithAtomicWrites(writer.PipeBuf))
+
+	result, err := Verify(strings.NewReader(output), report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = result.Err(false); err != nil {
+		t.Fatal(err)
+	}
+
+	torn, err := TornLines(strings.NewReader(output), JSONLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(torn) != 0 {
+		t.Fatalf("torn %+v should be empty", torn)
+	}
+
+	lines := strings.Split(output, "\n")
+	output = lines[0] + "\n" + lines[1][:10] + lines[2] + "\n" + lines[3] + "\n{\"msg\":"
+
+	torn, err = TornLines(strings.NewReader(output), JSONLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(torn) != "[2 4]" {
+		t.Fatalf("torn %+v is wrong", torn)
+	}
+}
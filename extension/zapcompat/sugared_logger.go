@@ -0,0 +1,265 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zapcompat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// callerSkip is the count of frames between the caller of SugaredLogger and logit.Logger.Log.
+	callerSkip = 2
+)
+
+var (
+	// exit is the function exiting the process, and it's a variable for testing.
+	exit = os.Exit
+)
+
+// SugaredLogger is a facade having the common method set of zap's SugaredLogger backed by logit.
+// It's useful for switching the backend of a large codebase to logit first and migrating call sites gradually.
+// Methods like Infof and Infow are mapped to logs of logit, and the keys and values are mapped to attrs.
+// Notice that zap.Field isn't supported because logit doesn't depend on zap, so you should pass keys and values instead.
+type SugaredLogger struct {
+	logger *logit.Logger
+}
+
+// NewSugaredLogger returns a sugared logger backed by logger.
+func NewSugaredLogger(logger *logit.Logger) *SugaredLogger {
+	return &SugaredLogger{logger: logger}
+}
+
+// Logger returns the logit logger backing the sugared logger.
+func (sl *SugaredLogger) Logger() *logit.Logger {
+	return sl.logger
+}
+
+// With returns a new sugared logger with keys and values.
+func (sl *SugaredLogger) With(keysAndValues ...any) *SugaredLogger {
+	if len(keysAndValues) <= 0 {
+		return sl
+	}
+
+	return NewSugaredLogger(sl.logger.With(keysAndValues...))
+}
+
+// Named returns a new sugared logger with name appended to the logger name.
+func (sl *SugaredLogger) Named(name string) *SugaredLogger {
+	if name == "" {
+		return sl
+	}
+
+	return NewSugaredLogger(sl.logger.Named(name))
+}
+
+// Sync syncs the logger and returns an error if failed.
+func (sl *SugaredLogger) Sync() error {
+	return sl.logger.Sync()
+}
+
+func (sl *SugaredLogger) log(level slog.Level, msg string, keysAndValues []any) {
+	sl.logger.Log(context.Background(), level, callerSkip, msg, keysAndValues...)
+}
+
+func (sl *SugaredLogger) panic(msg string) {
+	if err := sl.logger.Sync(); err != nil {
+		defaults.HandleError("SugaredLogger.logger.Sync", err)
+	}
+
+	panic(msg)
+}
+
+func (sl *SugaredLogger) exit() {
+	if err := sl.logger.Sync(); err != nil {
+		defaults.HandleError("SugaredLogger.logger.Sync", err)
+	}
+
+	exit(1)
+}
+
+// sprintln formats args like fmt.Sprintln but without the trailing newline.
+func sprintln(args []any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
+
+// Debug logs args in debug level like fmt.Sprint.
+func (sl *SugaredLogger) Debug(args ...any) {
+	sl.log(slog.LevelDebug, fmt.Sprint(args...), nil)
+}
+
+// Info logs args in info level like fmt.Sprint.
+func (sl *SugaredLogger) Info(args ...any) {
+	sl.log(slog.LevelInfo, fmt.Sprint(args...), nil)
+}
+
+// Warn logs args in warn level like fmt.Sprint.
+func (sl *SugaredLogger) Warn(args ...any) {
+	sl.log(slog.LevelWarn, fmt.Sprint(args...), nil)
+}
+
+// Error logs args in error level like fmt.Sprint.
+func (sl *SugaredLogger) Error(args ...any) {
+	sl.log(slog.LevelError, fmt.Sprint(args...), nil)
+}
+
+// DPanic logs args in error level like fmt.Sprint.
+// It won't panic because logit doesn't have a development mode.
+func (sl *SugaredLogger) DPanic(args ...any) {
+	sl.log(slog.LevelError, fmt.Sprint(args...), nil)
+}
+
+// Panic logs args in panic level like fmt.Sprint.
+// It syncs the logger and panics with the message after logging.
+func (sl *SugaredLogger) Panic(args ...any) {
+	msg := fmt.Sprint(args...)
+	sl.log(logit.LevelPanic, msg, nil)
+	sl.panic(msg)
+}
+
+// Fatal logs args in fatal level like fmt.Sprint.
+// It syncs the logger and exits the process with code 1 after logging.
+func (sl *SugaredLogger) Fatal(args ...any) {
+	sl.log(logit.LevelFatal, fmt.Sprint(args...), nil)
+	sl.exit()
+}
+
+// Debugf logs a message formatted with template and args in debug level.
+func (sl *SugaredLogger) Debugf(template string, args ...any) {
+	sl.log(slog.LevelDebug, fmt.Sprintf(template, args...), nil)
+}
+
+// Infof logs a message formatted with template and args in info level.
+func (sl *SugaredLogger) Infof(template string, args ...any) {
+	sl.log(slog.LevelInfo, fmt.Sprintf(template, args...), nil)
+}
+
+// Warnf logs a message formatted with template and args in warn level.
+func (sl *SugaredLogger) Warnf(template string, args ...any) {
+	sl.log(slog.LevelWarn, fmt.Sprintf(template, args...), nil)
+}
+
+// Errorf logs a message formatted with template and args in error level.
+func (sl *SugaredLogger) Errorf(template string, args ...any) {
+	sl.log(slog.LevelError, fmt.Sprintf(template, args...), nil)
+}
+
+// DPanicf logs a message formatted with template and args in error level.
+// It won't panic because logit doesn't have a development mode.
+func (sl *SugaredLogger) DPanicf(template string, args ...any) {
+	sl.log(slog.LevelError, fmt.Sprintf(template, args...), nil)
+}
+
+// Panicf logs a message formatted with template and args in panic level.
+// It syncs the logger and panics with the message after logging.
+func (sl *SugaredLogger) Panicf(template string, args ...any) {
+	msg := fmt.Sprintf(template, args...)
+	sl.log(logit.LevelPanic, msg, nil)
+	sl.panic(msg)
+}
+
+// Fatalf logs a message formatted with template and args in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (sl *SugaredLogger) Fatalf(template string, args ...any) {
+	sl.log(logit.LevelFatal, fmt.Sprintf(template, args...), nil)
+	sl.exit()
+}
+
+// Debugw logs msg with keys and values in debug level.
+func (sl *SugaredLogger) Debugw(msg string, keysAndValues ...any) {
+	sl.log(slog.LevelDebug, msg, keysAndValues)
+}
+
+// Infow logs msg with keys and values in info level.
+func (sl *SugaredLogger) Infow(msg string, keysAndValues ...any) {
+	sl.log(slog.LevelInfo, msg, keysAndValues)
+}
+
+// Warnw logs msg with keys and values in warn level.
+func (sl *SugaredLogger) Warnw(msg string, keysAndValues ...any) {
+	sl.log(slog.LevelWarn, msg, keysAndValues)
+}
+
+// Errorw logs msg with keys and values in error level.
+func (sl *SugaredLogger) Errorw(msg string, keysAndValues ...any) {
+	sl.log(slog.LevelError, msg, keysAndValues)
+}
+
+// DPanicw logs msg with keys and values in error level.
+// It won't panic because logit doesn't have a development mode.
+func (sl *SugaredLogger) DPanicw(msg string, keysAndValues ...any) {
+	sl.log(slog.LevelError, msg, keysAndValues)
+}
+
+// Panicw logs msg with keys and values in panic level.
+// It syncs the logger and panics with msg after logging.
+func (sl *SugaredLogger) Panicw(msg string, keysAndValues ...any) {
+	sl.log(logit.LevelPanic, msg, keysAndValues)
+	sl.panic(msg)
+}
+
+// Fatalw logs msg with keys and values in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (sl *SugaredLogger) Fatalw(msg string, keysAndValues ...any) {
+	sl.log(logit.LevelFatal, msg, keysAndValues)
+	sl.exit()
+}
+
+// Debugln logs args in debug level like fmt.Sprintln.
+func (sl *SugaredLogger) Debugln(args ...any) {
+	sl.log(slog.LevelDebug, sprintln(args), nil)
+}
+
+// Infoln logs args in info level like fmt.Sprintln.
+func (sl *SugaredLogger) Infoln(args ...any) {
+	sl.log(slog.LevelInfo, sprintln(args), nil)
+}
+
+// Warnln logs args in warn level like fmt.Sprintln.
+func (sl *SugaredLogger) Warnln(args ...any) {
+	sl.log(slog.LevelWarn, sprintln(args), nil)
+}
+
+// Errorln logs args in error level like fmt.Sprintln.
+func (sl *SugaredLogger) Errorln(args ...any) {
+	sl.log(slog.LevelError, sprintln(args), nil)
+}
+
+// DPanicln logs args in error level like fmt.Sprintln.
+// It won't panic because logit doesn't have a development mode.
+func (sl *SugaredLogger) DPanicln(args ...any) {
+	sl.log(slog.LevelError, sprintln(args), nil)
+}
+
+// Panicln logs args in panic level like fmt.Sprintln.
+// It syncs the logger and panics with the message after logging.
+func (sl *SugaredLogger) Panicln(args ...any) {
+	msg := sprintln(args)
+	sl.log(logit.LevelPanic, msg, nil)
+	sl.panic(msg)
+}
+
+// Fatalln logs args in fatal level like fmt.Sprintln.
+// It syncs the logger and exits the process with code 1 after logging.
+func (sl *SugaredLogger) Fatalln(args ...any) {
+	sl.log(logit.LevelFatal, sprintln(args), nil)
+	sl.exit()
+}
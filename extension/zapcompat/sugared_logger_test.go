@@ -0,0 +1,219 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zapcompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+)
+
+type testEntry struct {
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Logger string `json:"logger"`
+	Key    string `json:"key"`
+	Number int    `json:"number"`
+	Source struct {
+		File string `json:"file"`
+	} `json:"source"`
+}
+
+func newTestSugaredLogger(t *testing.T) (*SugaredLogger, *bytes.Buffer) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithJsonHandler(), logit.WithLevel(logit.LevelTrace), logit.WithSource())
+
+	return NewSugaredLogger(logger), buffer
+}
+
+func parseTestEntries(t *testing.T, buffer *bytes.Buffer) []testEntry {
+	var entries []testEntry
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n")) {
+		var entry testEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	buffer.Reset()
+	return entries
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSugaredLogger$
+func TestSugaredLogger(t *testing.T) {
+	logger, buffer := newTestSugaredLogger(t)
+	logger.Debug("debug", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnw("warn", "key", "value", "number", 3)
+	logger.Errorln("error", 4)
+	logger.DPanic("dpanic")
+
+	want := []testEntry{
+		{Level: "DEBUG", Msg: "debug1"},
+		{Level: "INFO", Msg: "info 2"},
+		{Level: "WARN", Msg: "warn", Key: "value", Number: 3},
+		{Level: "ERROR", Msg: "error 4"},
+		{Level: "ERROR", Msg: "dpanic"},
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != len(want) {
+		t.Fatalf("len(entries) %d != len(want) %d", len(entries), len(want))
+	}
+
+	for i, entry := range entries {
+		if filepath.Base(entry.Source.File) != "sugared_logger_test.go" {
+			t.Fatalf("entry.Source.File %s is wrong", entry.Source.File)
+		}
+
+		entry.Source.File = ""
+		if entry != want[i] {
+			t.Fatalf("entry %+v != want %+v", entry, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSugaredLoggerWith$
+func TestSugaredLoggerWith(t *testing.T) {
+	logger, buffer := newTestSugaredLogger(t)
+
+	if logger.With() != logger {
+		t.Fatal("logger.With() != logger")
+	}
+
+	if logger.Named("") != logger {
+		t.Fatal("logger.Named(\"\") != logger")
+	}
+
+	logger.With("key", "value").Named("server").Named("http").Infow("with", "number", 1)
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) %d != 1", len(entries))
+	}
+
+	want := testEntry{Level: "INFO", Msg: "with", Logger: "server.http", Key: "value", Number: 1}
+	entries[0].Source.File = ""
+
+	if entries[0] != want {
+		t.Fatalf("entries[0] %+v != want %+v", entries[0], want)
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSugaredLoggerClose$
+func TestSugaredLoggerClose(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := logit.NewLogger(logit.WithWriter(buffer), logit.WithJsonHandler(), logit.WithStats())
+	sugared := NewSugaredLogger(logger)
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sugared.Infow("closed", "key", "value")
+
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer %s should be empty after closing", buffer.String())
+	}
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("logger.Stats() should be ok")
+	}
+
+	if stats.Dropped != 1 {
+		t.Fatalf("stats.Dropped %d != 1", stats.Dropped)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSugaredLoggerPanic$
+func TestSugaredLoggerPanic(t *testing.T) {
+	logger, buffer := newTestSugaredLogger(t)
+
+	testCases := []func(){
+		func() { logger.Panic("panic", 1) },
+		func() { logger.Panicf("panic %d", 1) },
+		func() { logger.Panicw("panic 1", "key", "value") },
+		func() { logger.Panicln("panic", 1) },
+	}
+
+	for _, testCase := range testCases {
+		func() {
+			defer func() {
+				if r := recover(); r != "panic1" && r != "panic 1" {
+					t.Fatalf("r %+v is wrong", r)
+				}
+			}()
+
+			testCase()
+		}()
+	}
+
+	entries := parseTestEntries(t, buffer)
+	if len(entries) != len(testCases) {
+		t.Fatalf("len(entries) %d != len(testCases) %d", len(entries), len(testCases))
+	}
+
+	for _, entry := range entries {
+		if entry.Level != "PANIC" {
+			t.Fatalf("entry.Level %s != PANIC", entry.Level)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSugaredLoggerFatal$
+func TestSugaredLoggerFatal(t *testing.T) {
+	exitCodes := make([]int, 0, 4)
+	exit = func(code int) {
+		exitCodes = append(exitCodes, code)
+	}
+
+	defer func() {
+		exit = os.Exit
+	}()
+
+	logger, buffer := newTestSugaredLogger(t)
+	logger.Fatal("fatal")
+	logger.Fatalf("fatal %d", 1)
+	logger.Fatalw("fatal", "key", "value")
+	logger.Fatalln("fatal", 1)
+
+	if len(exitCodes) != 4 {
+		t.Fatalf("len(exitCodes) %d != 4", len(exitCodes))
+	}
+
+	for _, code := range exitCodes {
+		if code != 1 {
+			t.Fatalf("code %d != 1", code)
+		}
+	}
+
+	for _, entry := range parseTestEntries(t, buffer) {
+		if entry.Level != "FATAL" {
+			t.Fatalf("entry.Level %s != FATAL", entry.Level)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fields provides typed attrs for common domains, so logs from different services have consistent keys and value types.
+package fields
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/FishGoddess/logit"
+)
+
+// Keys of attrs returned by functions in this package.
+// Use them directly if you need attrs in other types, so keys are still consistent.
+const (
+	KeyHTTPMethod = "http_method"
+	KeyHTTPPath   = "http_path"
+	KeyHTTPStatus = "http_status"
+	KeyClientIP   = "client_ip"
+	KeyUserID     = "user_id"
+	KeyRequestID  = "request_id"
+)
+
+// HTTPMethod returns an attr of http method like "GET", whose key is KeyHTTPMethod.
+func HTTPMethod(method string) slog.Attr {
+	return slog.String(KeyHTTPMethod, method)
+}
+
+// HTTPPath returns an attr of http path like "/users", whose key is KeyHTTPPath.
+func HTTPPath(path string) slog.Attr {
+	return slog.String(KeyHTTPPath, path)
+}
+
+// HTTPStatus returns an attr of http status code like 200, whose key is KeyHTTPStatus.
+func HTTPStatus(code int) slog.Attr {
+	return slog.Int(KeyHTTPStatus, code)
+}
+
+// ClientIP returns an attr of client ip, whose key is KeyClientIP.
+func ClientIP(ip string) slog.Attr {
+	return slog.String(KeyClientIP, ip)
+}
+
+// UserID returns an attr of user id, whose key is KeyUserID.
+// The id is a string so ids in different types from different services can be searched in the same way.
+func UserID(id string) slog.Attr {
+	return slog.String(KeyUserID, id)
+}
+
+// RequestID returns an attr of request id, whose key is KeyRequestID.
+func RequestID(id string) slog.Attr {
+	return slog.String(KeyRequestID, id)
+}
+
+// Duration returns an attr of d in milliseconds with key, like 1.5 of 1500µs.
+// Handlers output durations in different ways, like "1.5ms" in text and 1500000 in json,
+// so durations are converted to milliseconds in float to be comparable in all handlers.
+func Duration(key string, d time.Duration) slog.Attr {
+	return slog.Float64(key, float64(d)/float64(time.Millisecond))
+}
+
+// Err returns an attr of err, see logit.Err.
+func Err(err error) slog.Attr {
+	return logit.Err(err)
+}
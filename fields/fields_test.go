@@ -0,0 +1,53 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fields
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFields$
+func TestFields(t *testing.T) {
+	testCases := []struct {
+		attr slog.Attr
+		want string
+	}{
+		{attr: HTTPMethod("GET"), want: "http_method=GET"},
+		{attr: HTTPPath("/users"), want: "http_path=/users"},
+		{attr: HTTPStatus(200), want: "http_status=200"},
+		{attr: ClientIP("127.0.0.1"), want: "client_ip=127.0.0.1"},
+		{attr: UserID("123"), want: "user_id=123"},
+		{attr: RequestID("abc"), want: "request_id=abc"},
+		{attr: Duration("cost", 1500*time.Microsecond), want: "cost=1.5"},
+		{attr: Err(errors.New("failed")), want: "error=[msg=failed type=*errors.errorString]"},
+	}
+
+	for _, testCase := range testCases {
+		if got := testCase.attr.String(); got != testCase.want {
+			t.Fatalf("got %s != want %s", got, testCase.want)
+		}
+	}
+
+	if kind := HTTPStatus(200).Value.Kind(); kind != slog.KindInt64 {
+		t.Fatalf("kind %s is wrong", kind)
+	}
+
+	if kind := Duration("cost", time.Second).Value.Kind(); kind != slog.KindFloat64 {
+		t.Fatalf("kind %s is wrong", kind)
+	}
+}
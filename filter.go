@@ -0,0 +1,229 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+)
+
+// FilterRule suppresses records matching all of its conditions, which is useful for muting noisy components.
+// Keys of attrs in groups are joined with ".", like "http.status".
+type FilterRule struct {
+	// Message is a regular expression matching messages of records, and an empty string matches all messages.
+	Message string
+
+	// Attrs are attrs which records must have, and values of attrs are compared in string form.
+	// Attrs added by With and WithGroup are included.
+	Attrs map[string]string
+
+	// ForbiddenAttrs are attrs which records must not have, and values of attrs are compared in string form.
+	ForbiddenAttrs map[string]string
+
+	// MaxLevel is the max level of records suppressed, so records higher than it are always kept.
+	// The zero value is slog.LevelInfo, which means warn and error logs won't be lost by default.
+	MaxLevel slog.Level
+}
+
+type filterRule struct {
+	message        *regexp.Regexp
+	attrs          map[string]string
+	forbiddenAttrs map[string]string
+	maxLevel       slog.Level
+}
+
+// filter suppresses records matching one of rules.
+type filter struct {
+	rules []filterRule
+
+	// keys are keys of attrs used by rules, so values of other attrs won't be collected.
+	keys map[string]struct{}
+
+	// maxLevel is the max level of all rules, so records higher than it won't be checked.
+	maxLevel slog.Level
+}
+
+// newFilter creates a filter with rules, and an error will be returned if one of messages is an invalid pattern.
+func newFilter(rules []FilterRule) (*filter, error) {
+	f := &filter{
+		rules: make([]filterRule, 0, len(rules)),
+		keys:  make(map[string]struct{}, 4),
+	}
+
+	for i, rule := range rules {
+		var message *regexp.Regexp
+		if rule.Message != "" {
+			pattern, err := regexp.Compile(rule.Message)
+			if err != nil {
+				return nil, fmt.Errorf("logit: filter message %s is invalid: %w", rule.Message, err)
+			}
+
+			message = pattern
+		}
+
+		for key := range rule.Attrs {
+			f.keys[key] = struct{}{}
+		}
+
+		for key := range rule.ForbiddenAttrs {
+			f.keys[key] = struct{}{}
+		}
+
+		if i == 0 || rule.MaxLevel > f.maxLevel {
+			f.maxLevel = rule.MaxLevel
+		}
+
+		f.rules = append(f.rules, filterRule{
+			message:        message,
+			attrs:          maps.Clone(rule.Attrs),
+			forbiddenAttrs: maps.Clone(rule.ForbiddenAttrs),
+			maxLevel:       rule.MaxLevel,
+		})
+	}
+
+	return f, nil
+}
+
+// collect collects values of attrs used by rules to values, and keys in groups are joined with prefix.
+func (f *filter) collect(values map[string]string, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	key := attr.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		// Attrs of a group having an empty key are inlined.
+		if attr.Key == "" {
+			key = prefix
+		}
+
+		for _, groupAttr := range attr.Value.Group() {
+			f.collect(values, key, groupAttr)
+		}
+
+		return
+	}
+
+	if _, ok := f.keys[key]; ok {
+		values[key] = attr.Value.String()
+	}
+}
+
+func (f *filter) matched(rule filterRule, level slog.Level, msg string, values map[string]string) bool {
+	if level > rule.maxLevel {
+		return false
+	}
+
+	if rule.message != nil && !rule.message.MatchString(msg) {
+		return false
+	}
+
+	for key, value := range rule.attrs {
+		if got, ok := values[key]; !ok || got != value {
+			return false
+		}
+	}
+
+	for key, value := range rule.forbiddenAttrs {
+		if got, ok := values[key]; ok && got == value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// suppressed reports whether the record should be suppressed, and values are attrs added by WithAttrs.
+func (f *filter) suppressed(record slog.Record, prefix string, values map[string]string) bool {
+	if record.Level > f.maxLevel {
+		return false
+	}
+
+	if len(f.keys) > 0 {
+		values = maps.Clone(values)
+		if values == nil {
+			values = make(map[string]string, len(f.keys))
+		}
+
+		record.Attrs(func(attr slog.Attr) bool {
+			f.collect(values, prefix, attr)
+			return true
+		})
+	}
+
+	for _, rule := range f.rules {
+		if f.matched(rule, record.Level, record.Message, values) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterHandler suppresses records matching rules of filter before handling them.
+// Values of attrs added by WithAttrs are collected when adding them, so the noisy loggers created by With can be muted.
+type filterHandler struct {
+	slog.Handler
+
+	filter *filter
+	prefix string
+	values map[string]string
+}
+
+func newFilterHandler(handler slog.Handler, filter *filter, prefix string, values map[string]string) slog.Handler {
+	return filterHandler{Handler: handler, filter: filter, prefix: prefix, values: values}
+}
+
+func (fh filterHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fh.filter.suppressed(record, fh.prefix, fh.values) {
+		return nil
+	}
+
+	return fh.Handler.Handle(ctx, record)
+}
+
+func (fh filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	values := fh.values
+	if len(fh.filter.keys) > 0 {
+		values = maps.Clone(fh.values)
+		if values == nil {
+			values = make(map[string]string, len(attrs))
+		}
+
+		for _, attr := range attrs {
+			fh.filter.collect(values, fh.prefix, attr)
+		}
+	}
+
+	return newFilterHandler(fh.Handler.WithAttrs(attrs), fh.filter, fh.prefix, values)
+}
+
+func (fh filterHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return fh
+	}
+
+	prefix := name
+	if fh.prefix != "" {
+		prefix = fh.prefix + "." + name
+	}
+
+	return newFilterHandler(fh.Handler.WithGroup(name), fh.filter, prefix, fh.values)
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewFilter$
+func TestNewFilter(t *testing.T) {
+	rules := []FilterRule{
+		{Message: "^health", Attrs: map[string]string{"path": "/ping"}, MaxLevel: slog.LevelDebug},
+		{ForbiddenAttrs: map[string]string{"http.status": "500"}, MaxLevel: slog.LevelWarn},
+	}
+
+	f, err := newFilter(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.rules) != len(rules) || f.maxLevel != slog.LevelWarn {
+		t.Fatalf("f %+v is wrong", f)
+	}
+
+	if len(f.keys) != 2 {
+		t.Fatalf("f.keys %+v is wrong", f.keys)
+	}
+
+	if _, err = newFilter([]FilterRule{{Message: "[a-"}}); err == nil {
+		t.Fatal("new filter with invalid message should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFilter$
+func TestFilter(t *testing.T) {
+	f, err := newFilter([]FilterRule{
+		{Message: "^health", Attrs: map[string]string{"component": "probe"}},
+		{Attrs: map[string]string{"http.path": "/ping"}, ForbiddenAttrs: map[string]string{"http.status": "500"}, MaxLevel: slog.LevelWarn},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRecord := func(level slog.Level, msg string, args ...any) slog.Record {
+		record := slog.NewRecord(time.Time{}, level, msg, 0)
+		record.Add(args...)
+
+		return record
+	}
+
+	testCases := []struct {
+		record     slog.Record
+		values     map[string]string
+		suppressed bool
+	}{
+		{record: newRecord(slog.LevelInfo, "health check"), values: map[string]string{"component": "probe"}, suppressed: true},
+		{record: newRecord(slog.LevelDebug, "health check", "component", "probe"), suppressed: true},
+		{record: newRecord(slog.LevelWarn, "health check", "component", "probe"), suppressed: false},
+		{record: newRecord(slog.LevelInfo, "check health", "component", "probe"), suppressed: false},
+		{record: newRecord(slog.LevelInfo, "health check", "component", "db"), suppressed: false},
+		{record: newRecord(slog.LevelWarn, "request", slog.Group("http", "path", "/ping", "status", 200)), suppressed: true},
+		{record: newRecord(slog.LevelWarn, "request", slog.Group("http", "path", "/ping", "status", 500)), suppressed: false},
+		{record: newRecord(slog.LevelError, "request", slog.Group("http", "path", "/ping", "status", 200)), suppressed: false},
+		{record: newRecord(slog.LevelInfo, "request", "path", "/ping"), suppressed: false},
+	}
+
+	for i, testCase := range testCases {
+		if suppressed := f.suppressed(testCase.record, "", testCase.values); suppressed != testCase.suppressed {
+			t.Fatalf("case %d: suppressed %+v != testCase.suppressed %+v", i, suppressed, testCase.suppressed)
+		}
+	}
+
+	record := newRecord(slog.LevelInfo, "request", "path", "/ping", "status", 200)
+	if !f.suppressed(record, "http", nil) {
+		t.Fatal("record in group http should be suppressed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFilterHandler$
+func TestFilterHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	rule := FilterRule{Attrs: map[string]string{"component": "noisy", "sub.key": "value"}}
+	logger := NewLogger(WithWriter(buffer), WithHandler("text"), WithFilters(rule))
+
+	noisy := logger.With("component", "noisy").WithGroup("sub")
+	noisy.Info("muted", "key", "value")
+	noisy.Info("kept", "key", "other")
+	noisy.Error("error", "key", "value")
+	noisy.With("key", "value").Info("muted")
+	logger.Info("kept", slog.Group("sub", "key", "value"))
+
+	got := buffer.String()
+	if strings.Contains(got, "muted") {
+		t.Fatalf("got %s shouldn't contain muted records", got)
+	}
+
+	if strings.Count(got, "msg=kept") != 2 || !strings.Contains(got, "msg=error") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
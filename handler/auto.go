@@ -0,0 +1,49 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// AutoName returns the name of handler which fits w best.
+// It's console for terminals, json for stdout and stderr which aren't terminals like pipes in CI,
+// and tape for files and other writers.
+func AutoName(w io.Writer) string {
+	if isCharDevice(w) {
+		return Console
+	}
+
+	if w == os.Stdout || w == os.Stderr {
+		return Json
+	}
+
+	return Tape
+}
+
+// NewAutoHandler creates a handler picked by w with opts.
+// See AutoName.
+func NewAutoHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	switch AutoName(w) {
+	case Console:
+		return NewConsoleHandler(w, opts)
+	case Json:
+		return newJSONHandler(w, opts)
+	default:
+		return NewTapeHandler(w, opts)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAutoName$
+func TestAutoName(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	if name := AutoName(buffer); name != Tape {
+		t.Fatalf("name %s != Tape", name)
+	}
+
+	if _, ok := NewAutoHandler(buffer, nil).(*tapeHandler); !ok {
+		t.Fatal("handler of buffer isn't tape handler")
+	}
+
+	for _, std := range []*os.File{os.Stdout, os.Stderr} {
+		want := Json
+		if isCharDevice(std) {
+			want = Console
+		}
+
+		if name := AutoName(std); name != want {
+			t.Fatalf("name %s != want %s", name, want)
+		}
+	}
+
+	// The null device is a char device like terminals.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer devNull.Close()
+
+	if !isCharDevice(devNull) {
+		t.Skip("null device isn't a char device")
+	}
+
+	// NO_COLOR only disables colors and the terminal still uses console handler.
+	t.Setenv("NO_COLOR", "1")
+
+	if name := AutoName(devNull); name != Console {
+		t.Fatalf("name %s != Console", name)
+	}
+
+	if _, ok := NewAutoHandler(devNull, nil).(*consoleHandler); !ok {
+		t.Fatal("handler of terminal isn't console handler")
+	}
+
+	newHandler, err := Get(Auto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := newHandler(devNull, &slog.HandlerOptions{}).(*consoleHandler); !ok {
+		t.Fatal("handler of terminal isn't console handler")
+	}
+}
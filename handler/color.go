@@ -0,0 +1,157 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape codes of colors used by handlers.
+const (
+	colorReset   = "\033[0m"
+	colorFaint   = "\033[2m"
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorCyan    = "\033[36m"
+	colorMagenta = "\033[1;35m"
+)
+
+var (
+	// colorNames is the names of colors which can be parsed by ParseColor.
+	colorNames = map[string]Color{
+		"none":           "",
+		"faint":          colorFaint,
+		"black":          "\033[30m",
+		"red":            colorRed,
+		"green":          colorGreen,
+		"yellow":         colorYellow,
+		"blue":           colorBlue,
+		"magenta":        "\033[35m",
+		"cyan":           colorCyan,
+		"white":          "\033[37m",
+		"bright_black":   "\033[90m",
+		"bright_red":     "\033[91m",
+		"bright_green":   "\033[92m",
+		"bright_yellow":  "\033[93m",
+		"bright_blue":    "\033[94m",
+		"bright_magenta": "\033[95m",
+		"bright_cyan":    "\033[96m",
+		"bright_white":   "\033[97m",
+	}
+)
+
+// Color is an ANSI escape code of the foreground color, and an empty color means no color.
+// See ParseColor, Color256 and ColorRGB.
+type Color string
+
+// Color256 returns the color of code in the 256-color palette, which is supported by most terminals.
+func Color256(code uint8) Color {
+	return Color("\033[38;5;" + strconv.Itoa(int(code)) + "m")
+}
+
+// ColorRGB returns the true color of r, g and b, which needs a terminal supporting 24-bit colors.
+func ColorRGB(r uint8, g uint8, b uint8) Color {
+	return Color(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// ParseColor parses s to a color and returns an error if failed.
+// The s can be a name like "red" and "bright_blue", a code in the 256-color palette like "208",
+// or a true color in hex like "#ff8700". Names are case-insensitive and "none" means no color.
+func ParseColor(s string) (Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if color, ok := colorNames[name]; ok {
+		return color, nil
+	}
+
+	if rgb, ok := strings.CutPrefix(name, "#"); ok && len(rgb) == 6 {
+		bs, err := hex.DecodeString(rgb)
+		if err != nil {
+			return "", fmt.Errorf("logit: color %s is invalid: %w", s, err)
+		}
+
+		return ColorRGB(bs[0], bs[1], bs[2]), nil
+	}
+
+	code, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return "", fmt.Errorf("logit: color %s isn't a name, a code in 0-255 or a hex like #ff8700", s)
+	}
+
+	return Color256(uint8(code)), nil
+}
+
+// isTerminal reports whether w is a terminal, which means colors can be displayed.
+// It returns false if NO_COLOR is set, see https://no-color.org.
+func isTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isCharDevice(w)
+}
+
+// isCharDevice reports whether w is a char device like terminals.
+func isCharDevice(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// levelColor returns the color of level.
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError+4:
+		return colorMagenta
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorGreen
+	case level >= slog.LevelDebug:
+		return colorBlue
+	default:
+		return colorFaint
+	}
+}
+
+// appendColored appends s to bs in color if color isn't empty.
+func appendColored(bs []byte, color string, s string) []byte {
+	if color == "" {
+		return append(bs, s...)
+	}
+
+	bs = append(bs, color...)
+	bs = append(bs, s...)
+	bs = append(bs, colorReset...)
+
+	return bs
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestIsTerminal$
+func TestIsTerminal(t *testing.T) {
+	if isTerminal(bytes.NewBuffer(nil)) {
+		t.Fatal("buffer is terminal")
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "test.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	if isTerminal(file) {
+		t.Fatal("file is terminal")
+	}
+
+	// The null device is a char device like terminals.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer devNull.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if !isTerminal(devNull) {
+		t.Skip("null device isn't a char device")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if isTerminal(devNull) {
+		t.Fatal("NO_COLOR doesn't work")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelColor$
+func TestLevelColor(t *testing.T) {
+	colors := map[slog.Level]string{
+		slog.LevelDebug - 4: colorFaint,
+		slog.LevelDebug:     colorBlue,
+		slog.LevelInfo:      colorGreen,
+		slog.LevelInfo + 2:  colorGreen,
+		slog.LevelWarn:      colorYellow,
+		slog.LevelError:     colorRed,
+		slog.LevelError + 4: colorMagenta,
+		slog.LevelError + 8: colorMagenta,
+	}
+
+	for level, want := range colors {
+		if color := levelColor(level); color != want {
+			t.Fatalf("level %s color %q != want %q", level, color, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAppendColored$
+func TestAppendColored(t *testing.T) {
+	if got := string(appendColored([]byte("a"), "", "b")); got != "ab" {
+		t.Fatalf("got %q != ab", got)
+	}
+
+	if got := string(appendColored([]byte("a"), colorRed, "b")); got != "a"+colorRed+"b"+colorReset {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseColor$
+func TestParseColor(t *testing.T) {
+	testCases := map[string]Color{
+		"none":        "",
+		"red":         colorRed,
+		"Bright_Blue": "\033[94m",
+		"208":         "\033[38;5;208m",
+		"0":           Color256(0),
+		"#FF8700":     "\033[38;2;255;135;0m",
+		" #000000 ":   ColorRGB(0, 0, 0),
+	}
+
+	for s, want := range testCases {
+		color, err := ParseColor(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if color != want {
+			t.Fatalf("color %q of %q != want %q", color, s, want)
+		}
+	}
+
+	for _, s := range []string{"", "pink", "256", "-1", "#ff87", "#gg8700"} {
+		if _, err := ParseColor(s); err == nil {
+			t.Fatalf("color %q should be invalid", s)
+		}
+	}
+}
@@ -0,0 +1,656 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/url"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	consoleTimeFormat      = "15:04:05.000"
+	consoleValueTimeFormat = "2006-01-02 15:04:05.000"
+
+	// consoleLevelWidth is the min width of levels, so levels like "INFO" and "ERROR" are aligned.
+	consoleLevelWidth = 5
+
+	// consoleMessageWidth is the min width of messages followed by attrs, so attrs of short messages are aligned.
+	consoleMessageWidth = 40
+
+	// consoleIndent is the indent of multi-line values placed under logs.
+	consoleIndent = "    "
+
+	// consoleLoggerKey is the key of logger names added by logit.Logger.Named, which are written in the logger column.
+	consoleLoggerKey = "logger"
+
+	// osc8Start and osc8End wrap links of hyperlinks, see https://gist.github.com/egmontkob/eb114294efbcd5adb1944c9f3cb5feda.
+	osc8Start = "\033]8;;"
+	osc8End   = "\033\\"
+)
+
+type consoleHandler struct {
+	w    io.Writer
+	opts slog.HandlerOptions
+
+	// color is true if w is a terminal.
+	color bool
+
+	// levelColors and levelIcons customize levels, see WithConsoleLevelColors and WithConsoleLevelIcons.
+	levelColors map[slog.Level]Color
+	levelIcons  map[slog.Level]string
+
+	// levelWidth, loggerWidth and messageWidth are the widths of columns, and 0 means the default layout.
+	// See WithConsoleLevelWidth, WithConsoleLoggerWidth and WithConsoleMessageWidth.
+	levelWidth   int
+	loggerWidth  int
+	messageWidth int
+
+	// logger is the logger name added by WithAttrs, which is written in the logger column.
+	logger string
+
+	// prefix is the groups joined with '.', like "a.b.".
+	prefix string
+	groups []string
+
+	// inline is the attrs added by WithAttrs in the line of logs,
+	// and block is the attrs having multi-line values placed under logs.
+	inline []byte
+	block  []byte
+
+	lock *sync.Mutex
+}
+
+// ConsoleOption is an option for creating console handlers.
+type ConsoleOption func(ch *consoleHandler)
+
+// WithConsoleLevelColors sets the colors of levels, and levels not in colors use the default colors.
+// It's useful if the default colors are hard to read on your terminal theme, like light themes.
+// Colors are still used only if w is a terminal, see Color and ParseColor.
+func WithConsoleLevelColors(colors map[slog.Level]Color) ConsoleOption {
+	return func(ch *consoleHandler) {
+		ch.levelColors = colors
+	}
+}
+
+// WithConsoleLevelIcons sets the icons of levels like emojis, which are written before levels.
+// Levels not in icons have no icons, and icons are written even if w isn't a terminal.
+func WithConsoleLevelIcons(icons map[slog.Level]string) ConsoleOption {
+	return func(ch *consoleHandler) {
+		ch.levelIcons = icons
+	}
+}
+
+// WithConsoleLevelWidth sets the fixed width of levels, so longer levels are cut and shorter levels are padded.
+// Levels are only padded to 5 by default, which is the width of "DEBUG" and "ERROR".
+func WithConsoleLevelWidth(width int) ConsoleOption {
+	return func(ch *consoleHandler) {
+		ch.levelWidth = width
+	}
+}
+
+// WithConsoleLoggerWidth writes logger names in a column of width after levels instead of attrs.
+// Longer names keep their last runes like "db.pool" of "app.db.pool", and logs without names leave the column blank.
+// Logger names are the values of "logger" attrs added by logit.Logger.Named.
+func WithConsoleLoggerWidth(width int) ConsoleOption {
+	return func(ch *consoleHandler) {
+		ch.loggerWidth = width
+	}
+}
+
+// WithConsoleMessageWidth sets the max width of messages, so longer messages are wrapped at spaces.
+// Wrapped lines are indented to the first line, and attrs are aligned after messages padded to width.
+// Messages are only padded to 40 by default.
+func WithConsoleMessageWidth(width int) ConsoleOption {
+	return func(ch *consoleHandler) {
+		ch.messageWidth = width
+	}
+}
+
+// NewConsoleHandler creates a console handler with w and opts.
+// This handler writes records in a human-friendly format for development, like:
+//
+//	15:04:05.000 INFO  logit/main.go:12 user login                      user.id=123 ip=127.0.0.1
+//
+// Levels are colored if w is a terminal, and values having multiple lines are indented under logs.
+// Set NO_COLOR environment variable to disable colors, see https://no-color.org.
+// Sources are shortened to the last directory and file, see defaults.ConsoleShortSource.
+// Use NewConsoleHandlerFunc if you want to customize it with options.
+func NewConsoleHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return newConsoleHandler(w, opts, nil)
+}
+
+// NewConsoleHandlerFunc returns a function creating console handlers customized by consoleOpts.
+// It's useful for registering a customized console handler or using it in routes.
+func NewConsoleHandlerFunc(consoleOpts ...ConsoleOption) NewHandlerFunc {
+	return func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return newConsoleHandler(w, opts, consoleOpts)
+	}
+}
+
+func newConsoleHandler(w io.Writer, opts *slog.HandlerOptions, consoleOpts []ConsoleOption) slog.Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+
+	handler := &consoleHandler{
+		w:     w,
+		opts:  *opts,
+		color: isTerminal(w),
+		lock:  &sync.Mutex{},
+	}
+
+	for _, opt := range consoleOpts {
+		opt(handler)
+	}
+
+	return handler
+}
+
+// colorOf returns color if handler is colorful or an empty string.
+func (ch *consoleHandler) colorOf(color string) string {
+	if ch.color {
+		return color
+	}
+
+	return ""
+}
+
+// consoleValue returns the string of value and reports whether it's an error.
+func consoleValue(value slog.Value) (string, bool) {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String(), false
+	case slog.KindTime:
+		return value.Time().Format(consoleValueTimeFormat), false
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return err.Error(), true
+		}
+	}
+
+	return value.String(), false
+}
+
+// consoleNeedQuote returns if s should be quoted, like empty strings and strings having spaces or '='.
+func consoleNeedQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ch *consoleHandler) appendAttr(inline []byte, block []byte, prefix string, groups []string, attr slog.Attr) ([]byte, []byte) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+
+		// A group with empty key should be inlined.
+		if attr.Key != "" {
+			prefix = prefix + attr.Key + "."
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+
+		for _, attr := range attrs {
+			inline, block = ch.appendAttr(inline, block, prefix, groups, attr)
+		}
+
+		return inline, block
+	}
+
+	if ch.opts.ReplaceAttr != nil {
+		attr = ch.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Key == "" {
+		return inline, block
+	}
+
+	value, isError := consoleValue(attr.Value)
+
+	valueColor := ""
+	if isError {
+		valueColor = ch.colorOf(colorRed)
+	}
+
+	// Values having multiple lines are placed under logs, and each line is indented.
+	if strings.IndexByte(value, '\n') >= 0 {
+		block = append(block, '\n')
+		block = append(block, consoleIndent...)
+		block = appendColored(block, ch.colorOf(colorCyan), prefix+attr.Key)
+		block = append(block, ':')
+
+		for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+			block = append(block, '\n')
+			block = append(block, consoleIndent+consoleIndent...)
+			block = appendColored(block, valueColor, line)
+		}
+
+		return inline, block
+	}
+
+	if consoleNeedQuote(value) {
+		value = strconv.Quote(value)
+	}
+
+	inline = append(inline, ' ')
+	inline = appendColored(inline, ch.colorOf(colorCyan), prefix+attr.Key)
+	inline = appendColored(inline, ch.colorOf(colorFaint), "=")
+	inline = appendColored(inline, valueColor, value)
+
+	return inline, block
+}
+
+// WithAttrs returns a new handler with attrs.
+func (ch *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return ch
+	}
+
+	logger := ch.logger
+	inline := slices.Clip(ch.inline)
+	block := slices.Clip(ch.block)
+
+	for _, attr := range attrs {
+		if name, ok := ch.loggerName(attr); ok {
+			logger = name
+			continue
+		}
+
+		inline, block = ch.appendAttr(inline, block, ch.prefix, ch.groups, attr)
+	}
+
+	handler := *ch
+	handler.logger = logger
+	handler.inline = inline
+	handler.block = block
+
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (ch *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return ch
+	}
+
+	handler := *ch
+	handler.prefix = ch.prefix + name + "."
+	handler.groups = append(slices.Clip(ch.groups), name)
+
+	return &handler
+}
+
+// Enabled reports whether the logger should ignore logs whose level is lower than passed level.
+func (ch *consoleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= ch.opts.Level.Level()
+}
+
+func (ch *consoleHandler) appendTime(bs []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return bs
+	}
+
+	value := slog.TimeValue(t)
+	if ch.opts.ReplaceAttr != nil {
+		attr := ch.opts.ReplaceAttr(nil, slog.Time(slog.TimeKey, t))
+		if attr.Key == "" {
+			return bs
+		}
+
+		value = attr.Value.Resolve()
+	}
+
+	formatted := ""
+	if value.Kind() == slog.KindTime {
+		formatted = value.Time().Format(consoleTimeFormat)
+	} else {
+		formatted, _ = consoleValue(value)
+	}
+
+	bs = appendColored(bs, ch.colorOf(colorFaint), formatted)
+	return append(bs, ' ')
+}
+
+// levelColor returns the color of level set by WithConsoleLevelColors or the default color.
+func (ch *consoleHandler) levelColor(level slog.Level) string {
+	if color, ok := ch.levelColors[level]; ok {
+		return string(color)
+	}
+
+	return levelColor(level)
+}
+
+// consoleColumn fits s in a column of width, which pads s with spaces or cuts s to width.
+// The last runes of s are kept if keepLast is true, or the first runes are kept.
+func consoleColumn(s string, width int, keepLast bool) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s + strings.Repeat(" ", width-len(runes))
+	}
+
+	if keepLast {
+		return string(runes[len(runes)-width:])
+	}
+
+	return string(runes[:width])
+}
+
+func (ch *consoleHandler) appendLevel(bs []byte, level slog.Level) []byte {
+	if icon, ok := ch.levelIcons[level]; ok && icon != "" {
+		bs = append(bs, icon...)
+		bs = append(bs, ' ')
+	}
+
+	name := levelName(level)
+	if ch.levelWidth > 0 {
+		name = consoleColumn(name, ch.levelWidth, false)
+	} else if padding := consoleLevelWidth - len(name); padding > 0 {
+		name = name + strings.Repeat(" ", padding)
+	}
+
+	bs = appendColored(bs, ch.colorOf(ch.levelColor(level)), name)
+	return append(bs, ' ')
+}
+
+// loggerName returns the logger name in attr if logger names are written in the logger column.
+func (ch *consoleHandler) loggerName(attr slog.Attr) (string, bool) {
+	if ch.loggerWidth <= 0 || attr.Key != consoleLoggerKey {
+		return "", false
+	}
+
+	if value := attr.Value.Resolve(); value.Kind() == slog.KindString {
+		return value.String(), true
+	}
+
+	return "", false
+}
+
+func (ch *consoleHandler) appendLogger(bs []byte, logger string) []byte {
+	if ch.loggerWidth <= 0 {
+		return bs
+	}
+
+	bs = append(bs, consoleColumn(logger, ch.loggerWidth, true)...)
+	return append(bs, ' ')
+}
+
+// wrapMessage splits msg into lines at spaces, so lines aren't wider than width.
+// Words wider than width are split, and line breaks in msg are kept.
+func wrapMessage(msg string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(msg, "\n") {
+		var line []rune
+		for _, word := range strings.Fields(paragraph) {
+			runes := []rune(word)
+			for len(runes) > width {
+				if len(line) > 0 {
+					lines = append(lines, string(line))
+					line = line[:0]
+				}
+
+				lines = append(lines, string(runes[:width]))
+				runes = runes[width:]
+			}
+
+			if len(runes) == 0 {
+				continue
+			}
+
+			if len(line) > 0 && len(line)+1+len(runes) > width {
+				lines = append(lines, string(line))
+				line = line[:0]
+			}
+
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+
+			line = append(line, runes...)
+		}
+
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
+
+// visibleWidth returns the count of runes in bs displayed by terminals, so escape codes of colors and links are skipped.
+func visibleWidth(bs []byte) int {
+	width := 0
+	for i := 0; i < len(bs); {
+		if bs[i] == '\033' && i+1 < len(bs) && bs[i+1] == '[' {
+			// Colors end with 'm'.
+			end := bytes.IndexByte(bs[i:], 'm')
+			if end < 0 {
+				break
+			}
+
+			i += end + 1
+			continue
+		}
+
+		if bytes.HasPrefix(bs[i:], []byte(osc8Start)) {
+			end := bytes.Index(bs[i:], []byte(osc8End))
+			if end < 0 {
+				break
+			}
+
+			i += end + len(osc8End)
+			continue
+		}
+
+		_, size := utf8.DecodeRune(bs[i:])
+		i += size
+		width++
+	}
+
+	return width
+}
+
+// appendMessage appends msg and returns the width of the last line of msg.
+// Messages wider than messageWidth are wrapped, and wrapped lines are indented to the first line.
+func (ch *consoleHandler) appendMessage(bs []byte, msg string) ([]byte, int) {
+	width := utf8.RuneCountInString(msg)
+	if ch.messageWidth <= 0 || width <= ch.messageWidth {
+		return append(bs, msg...), width
+	}
+
+	indent := strings.Repeat(" ", visibleWidth(bs[bytes.LastIndexByte(bs, '\n')+1:]))
+	lines := wrapMessage(msg, ch.messageWidth)
+
+	for i, line := range lines {
+		if i > 0 {
+			bs = append(bs, '\n')
+			bs = append(bs, indent...)
+		}
+
+		bs = append(bs, line...)
+	}
+
+	return bs, utf8.RuneCountInString(lines[len(lines)-1])
+}
+
+// shortSource returns the last directory and file of file.
+func shortSource(file string) string {
+	index := strings.LastIndexByte(file, '/')
+	if index <= 0 {
+		return file
+	}
+
+	if index = strings.LastIndexByte(file[:index], '/'); index >= 0 {
+		return file[index+1:]
+	}
+
+	return file
+}
+
+// FileSourceLink returns a file:// link of file, which can be set to defaults.ConsoleSourceLink.
+// Notice that the line is ignored because file links don't support lines.
+func FileSourceLink(file string, line int) string {
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file
+	}
+
+	link := url.URL{Scheme: "file", Path: file}
+	return link.String()
+}
+
+// VSCodeSourceLink returns a vscode:// link of file and line, which can be set to defaults.ConsoleSourceLink.
+// Clicking the link will open the file at the line in vscode.
+func VSCodeSourceLink(file string, line int) string {
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file
+	}
+
+	link := url.URL{Scheme: "vscode", Host: "file", Path: file}
+	return link.String() + ":" + strconv.Itoa(line)
+}
+
+// appendLink appends text as an OSC 8 hyperlink to link.
+func appendLink(bs []byte, link string, text []byte) []byte {
+	bs = append(bs, osc8Start...)
+	bs = append(bs, link...)
+	bs = append(bs, osc8End...)
+	bs = append(bs, text...)
+	bs = append(bs, osc8Start...)
+	bs = append(bs, osc8End...)
+
+	return bs
+}
+
+func (ch *consoleHandler) appendSource(bs []byte, pc uintptr) []byte {
+	if !ch.opts.AddSource || pc == 0 {
+		return bs
+	}
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+
+	resolved, attr, ok := resolveSource(pc, ch.opts.ReplaceAttr)
+	if !ok {
+		if attr.Key == "" {
+			return bs
+		}
+
+		value, _ := consoleValue(attr.Value)
+		bs = appendColored(bs, ch.colorOf(colorFaint), value)
+
+		return append(bs, ' ')
+	}
+
+	file := resolved.File
+	if defaults.ConsoleShortSource {
+		file = shortSource(file)
+	}
+
+	source := file + ":" + strconv.Itoa(resolved.Line)
+
+	// Only terminals can render hyperlinks, so sources are linked only if handler is colorful.
+	// Links always use the full path of file, so the file can be found even if the source is trimmed.
+	if sourceLink := defaults.ConsoleSourceLink; ch.color && sourceLink != nil {
+		text := appendColored(nil, colorFaint, source)
+		bs = appendLink(bs, sourceLink(frame.File, frame.Line), text)
+
+		return append(bs, ' ')
+	}
+
+	bs = appendColored(bs, ch.colorOf(colorFaint), source)
+	return append(bs, ' ')
+}
+
+// Handle handles one record and returns an error if failed.
+func (ch *consoleHandler) Handle(ctx context.Context, record slog.Record) error {
+	// Setup a buffer for handling record.
+	buffer := newBuffer()
+	bs := buffer.bs
+
+	defer func() {
+		buffer.bs = bs
+		freeBuffer(buffer)
+	}()
+
+	// Handling record.
+	// Attrs are handled first, so the logger name in attrs can be written before the message.
+	logger := ch.logger
+	inline := slices.Clip(ch.inline)
+	block := slices.Clip(ch.block)
+
+	if record.NumAttrs() > 0 {
+		record.Attrs(func(attr slog.Attr) bool {
+			if name, ok := ch.loggerName(attr); ok {
+				logger = name
+				return true
+			}
+
+			inline, block = ch.appendAttr(inline, block, ch.prefix, ch.groups, attr)
+			return true
+		})
+	}
+
+	bs = ch.appendTime(bs, record.Time)
+	bs = ch.appendLevel(bs, record.Level)
+	bs = ch.appendLogger(bs, logger)
+	bs = ch.appendSource(bs, record.PC)
+
+	bs, lastWidth := ch.appendMessage(bs, record.Message)
+
+	// Pad short messages so attrs are aligned.
+	if len(inline) > 0 {
+		padWidth := consoleMessageWidth
+		if ch.messageWidth > 0 {
+			padWidth = ch.messageWidth
+		}
+
+		if padding := padWidth - lastWidth; padding > 0 {
+			bs = append(bs, strings.Repeat(" ", padding)...)
+		}
+	}
+
+	bs = append(bs, inline...)
+	bs = append(bs, block...)
+	bs = append(bs, '\n')
+
+	// Write handled record.
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	_, err := ch.w.Write(bs)
+	return err
+}
@@ -0,0 +1,335 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestShortSource$
+func TestShortSource(t *testing.T) {
+	sources := map[string]string{
+		"/a/b/c/logger.go": "c/logger.go",
+		"c/logger.go":      "c/logger.go",
+		"/logger.go":       "/logger.go",
+		"logger.go":        "logger.go",
+	}
+
+	for source, want := range sources {
+		if got := shortSource(source); got != want {
+			t.Fatalf("source %s got %s != want %s", source, got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandler$
+func TestConsoleHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, "now")
+			}
+
+			return attr
+		},
+	}
+
+	handler := NewConsoleHandler(buffer, opts)
+	if handler.Enabled(context.Background(), slog.LevelDebug-1) {
+		t.Fatal("level debug-1 is enabled")
+	}
+
+	logger := slog.New(handler).With("service", "logit").WithGroup("user").With("id", 123)
+	logger.Info("login", "name", "fish goddess", "empty", "")
+	logger.Debug("no attrs in record")
+	logger.Error("failed", "err", errors.New("line1\nline2\n"), "at", time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC))
+
+	slog.New(handler).Warn("no attrs")
+
+	want := []string{
+		`now INFO  login                                    service=logit user.id=123 user.name="fish goddess" user.empty=""`,
+		`now DEBUG no attrs in record                       service=logit user.id=123`,
+		`now ERROR failed                                   service=logit user.id=123 user.at="2024-01-02 03:04:05.006"`,
+		`    user.err:`,
+		`        line1`,
+		`        line2`,
+		`now WARN  no attrs`,
+	}
+
+	if got := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandlerColor$
+func TestConsoleHandlerColor(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	handler := NewConsoleHandler(buffer, &slog.HandlerOptions{AddSource: true}).(*consoleHandler)
+	handler.color = true
+
+	slog.New(handler).Error("msg", "err", errors.New("failed"))
+
+	got := buffer.String()
+	if !strings.Contains(got, colorRed+"ERROR"+colorReset) {
+		t.Fatalf("got %q doesn't have colored level", got)
+	}
+
+	if !strings.Contains(got, colorCyan+"err"+colorReset+colorFaint+"="+colorReset+colorRed+"failed"+colorReset) {
+		t.Fatalf("got %q doesn't have colored attr", got)
+	}
+
+	if !strings.Contains(got, colorFaint+"handler/console_test.go:") {
+		t.Fatalf("got %q doesn't have short source", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandlerSource$
+func TestConsoleHandlerSource(t *testing.T) {
+	consoleShortSource := defaults.ConsoleShortSource
+	defer func() {
+		defaults.ConsoleShortSource = consoleShortSource
+	}()
+
+	defaults.ConsoleShortSource = false
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	slog.New(NewConsoleHandler(buffer, &slog.HandlerOptions{AddSource: true})).Info("msg")
+
+	got := buffer.String()
+	if index := strings.Index(got, "/handler/console_test.go:"); index < 0 || strings.Count(got[:index], "/") < 1 {
+		t.Fatalf("got %q doesn't have full source", got)
+	}
+
+	timePart := got[:strings.IndexByte(got, ' ')]
+	if _, err := time.Parse(consoleTimeFormat, timePart); err != nil {
+		t.Fatalf("time %q is wrong: %+v", timePart, err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandlerSourceLink$
+func TestConsoleHandlerSourceLink(t *testing.T) {
+	consoleSourceLink := defaults.ConsoleSourceLink
+	defer func() {
+		defaults.ConsoleSourceLink = consoleSourceLink
+	}()
+
+	defaults.ConsoleSourceLink = VSCodeSourceLink
+
+	// Buffer isn't a terminal so sources shouldn't be linked.
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	slog.New(NewConsoleHandler(buffer, &slog.HandlerOptions{AddSource: true})).Info("msg")
+
+	if got := buffer.String(); strings.Contains(got, osc8Start) {
+		t.Fatalf("got %q has links", got)
+	}
+
+	buffer.Reset()
+
+	handler := NewConsoleHandler(buffer, &slog.HandlerOptions{AddSource: true}).(*consoleHandler)
+	handler.color = true
+
+	slog.New(handler).Info("msg")
+
+	got := buffer.String()
+	if !strings.Contains(got, osc8Start+"vscode://file/") {
+		t.Fatalf("got %q doesn't have link", got)
+	}
+
+	if !strings.Contains(got, osc8End+colorFaint+"handler/console_test.go:") {
+		t.Fatalf("got %q doesn't have linked source", got)
+	}
+
+	if !strings.Contains(got, colorReset+osc8Start+osc8End+" ") {
+		t.Fatalf("got %q doesn't have closed link", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSourceLink$
+func TestSourceLink(t *testing.T) {
+	if link := FileSourceLink("/home/logit/main.go", 12); link != "file:///home/logit/main.go" {
+		t.Fatalf("link %s is wrong", link)
+	}
+
+	if link := FileSourceLink("C:/logit/my main.go", 12); link != "file:///C:/logit/my%20main.go" {
+		t.Fatalf("link %s is wrong", link)
+	}
+
+	if link := VSCodeSourceLink("/home/logit/main.go", 12); link != "vscode://file/home/logit/main.go:12" {
+		t.Fatalf("link %s is wrong", link)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandlerLevelColors$
+func TestConsoleHandlerLevelColors(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	colors := map[slog.Level]Color{
+		slog.LevelInfo: Color256(28),
+		slog.LevelWarn: ColorRGB(175, 95, 0),
+	}
+
+	icons := map[slog.Level]string{
+		slog.LevelWarn:  "⚠️",
+		slog.LevelError: "❌",
+	}
+
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}
+
+	newHandler := NewConsoleHandlerFunc(WithConsoleLevelColors(colors), WithConsoleLevelIcons(icons))
+
+	handler := newHandler(buffer, opts).(*consoleHandler)
+	handler.color = true
+
+	logger := slog.New(handler)
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	want := []string{
+		"\033[38;5;28mINFO " + colorReset + " info",
+		"⚠️ \033[38;2;175;95;0mWARN " + colorReset + " warn",
+		"❌ " + colorRed + "ERROR" + colorReset + " error",
+	}
+
+	if got := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+
+	// Icons are written even if w isn't a terminal, but colors aren't.
+	buffer.Reset()
+	slog.New(newHandler(buffer, opts)).Warn("warn")
+
+	if got := buffer.String(); got != "⚠️ WARN  warn\n" {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleColumn$
+func TestConsoleColumn(t *testing.T) {
+	testCases := []struct {
+		s        string
+		width    int
+		keepLast bool
+		want     string
+	}{
+		{s: "INFO", width: 6, keepLast: false, want: "INFO  "},
+		{s: "WARNING", width: 4, keepLast: false, want: "WARN"},
+		{s: "app.db.pool", width: 7, keepLast: true, want: "db.pool"},
+		{s: "数据库", width: 2, keepLast: true, want: "据库"},
+		{s: "", width: 3, keepLast: true, want: "   "},
+	}
+
+	for _, testCase := range testCases {
+		if got := consoleColumn(testCase.s, testCase.width, testCase.keepLast); got != testCase.want {
+			t.Fatalf("got %q != want %q", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWrapMessage$
+func TestWrapMessage(t *testing.T) {
+	testCases := []struct {
+		msg   string
+		width int
+		want  []string
+	}{
+		{msg: "user login", width: 10, want: []string{"user login"}},
+		{msg: "user login failed", width: 10, want: []string{"user login", "failed"}},
+		{msg: "a  b   c", width: 3, want: []string{"a b", "c"}},
+		{msg: "abcdefgh ij", width: 3, want: []string{"abc", "def", "gh", "ij"}},
+		{msg: "abcdef", width: 3, want: []string{"abc", "def"}},
+		{msg: "line1\nline2 is long", width: 8, want: []string{"line1", "line2 is", "long"}},
+	}
+
+	for _, testCase := range testCases {
+		if got := wrapMessage(testCase.msg, testCase.width); strings.Join(got, "|") != strings.Join(testCase.want, "|") {
+			t.Fatalf("msg %q got %q != want %q", testCase.msg, got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestVisibleWidth$
+func TestVisibleWidth(t *testing.T) {
+	bs := appendColored(nil, colorRed, "ERROR")
+	bs = append(bs, ' ')
+	bs = appendLink(bs, "vscode://file/main.go:1", appendColored(nil, colorFaint, "main.go:1"))
+	bs = append(bs, " 数据库"...)
+
+	if width := visibleWidth(bs); width != 19 {
+		t.Fatalf("width %d != 19", width)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConsoleHandlerWidths$
+func TestConsoleHandlerWidths(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, "now")
+			}
+
+			return attr
+		},
+	}
+
+	newHandler := NewConsoleHandlerFunc(WithConsoleLevelWidth(4), WithConsoleLoggerWidth(7), WithConsoleMessageWidth(12))
+	handler := newHandler(buffer, opts)
+
+	logger := slog.New(handler)
+	logger.Info("user login", "logger", "app.db.pool", "id", 1)
+	logger.Warn("the pool is almost full", "size", 100)
+	logger.With("logger", "http").WithGroup("req").Error("failed", "path", "/")
+
+	want := []string{
+		`now INFO db.pool user login   id=1`,
+		`now WARN         the pool is`,
+		`                 almost full  size=100`,
+		`now ERRO http    failed       req.path=/`,
+	}
+
+	if got := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+
+	// The logger attr is kept as an attr if the logger column isn't enabled.
+	buffer.Reset()
+	slog.New(NewConsoleHandler(buffer, opts)).Info("msg", "logger", "app")
+
+	if got := buffer.String(); !strings.HasSuffix(got, " logger=app\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
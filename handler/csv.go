@@ -0,0 +1,315 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultCSVColumns is the columns used by csv handler if no columns are specified.
+	DefaultCSVColumns = []string{slog.TimeKey, slog.LevelKey, slog.MessageKey}
+)
+
+// csvWriter is shared by csv handlers derived from the same handler, so the header will be written only once.
+type csvWriter struct {
+	w io.Writer
+
+	// headerWritten is true if the header has been written or the writer isn't empty.
+	headerWritten bool
+
+	lock sync.Mutex
+}
+
+// newCSVWriter returns a csv writer of w.
+// The header won't be written if w has a Stat method reporting it isn't empty, like an existing file.
+func newCSVWriter(w io.Writer) *csvWriter {
+	cw := &csvWriter{w: w}
+
+	if stater, ok := w.(interface{ Stat() (os.FileInfo, error) }); ok {
+		if info, err := stater.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			cw.headerWritten = true
+		}
+	}
+
+	return cw
+}
+
+func (cw *csvWriter) write(header []byte, row []byte) (err error) {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+
+	if !cw.headerWritten {
+		if _, err = cw.w.Write(header); err != nil {
+			return err
+		}
+
+		cw.headerWritten = true
+	}
+
+	_, err = cw.w.Write(row)
+	return err
+}
+
+type csvHandler struct {
+	writer *csvWriter
+	opts   slog.HandlerOptions
+
+	columns []string
+	indexes map[string]int
+	header  []byte
+
+	// prefix is the groups joined with '.', like "a.b.".
+	prefix string
+	groups []string
+
+	// values is the values of columns set by WithAttrs.
+	values []string
+}
+
+// NewCSVHandler creates a csv handler with w, opts and columns.
+// This handler writes records as csv rows, and columns can be "time", "level", "msg", "source" or keys of attrs.
+// Keys of attrs in groups are joined with '.', so attr "id" in group "user" is in column "user.id".
+// Attrs not in columns are discarded, and columns not in records are empty.
+// A header of columns is written before the first row, unless w is a file which isn't empty.
+// DefaultCSVColumns will be used if no columns are specified.
+func NewCSVHandler(w io.Writer, opts *slog.HandlerOptions, columns ...string) slog.Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+
+	if len(columns) <= 0 {
+		columns = DefaultCSVColumns
+	}
+
+	columns = slices.Clone(columns)
+	indexes := make(map[string]int, len(columns))
+
+	var header []byte
+	for i, column := range columns {
+		indexes[column] = i
+		header = appendCSVField(header, i, column)
+	}
+
+	handler := &csvHandler{
+		writer:  newCSVWriter(w),
+		opts:    *opts,
+		columns: columns,
+		indexes: indexes,
+		header:  append(header, '\n'),
+		values:  make([]string, len(columns)),
+	}
+
+	return handler
+}
+
+// appendCSVField appends field as the ith field of a row.
+// The field will be quoted if it has commas, quotes, line breaks or leading spaces.
+func appendCSVField(bs []byte, i int, field string) []byte {
+	if i > 0 {
+		bs = append(bs, ',')
+	}
+
+	if field == "" || (field[0] != ' ' && field[0] != '\t' && !strings.ContainsAny(field, ",\"\r\n")) {
+		return append(bs, field...)
+	}
+
+	bs = append(bs, '"')
+
+	for {
+		index := strings.IndexByte(field, '"')
+		if index < 0 {
+			break
+		}
+
+		bs = append(bs, field[:index+1]...)
+		bs = append(bs, '"')
+		field = field[index+1:]
+	}
+
+	bs = append(bs, field...)
+	bs = append(bs, '"')
+
+	return bs
+}
+
+func csvValue(value slog.Value) string {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String()
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if level, ok := value.Any().(slog.Level); ok {
+			return levelName(level)
+		}
+
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+
+		if source, ok := value.Any().(*slog.Source); ok {
+			return source.File + ":" + strconv.Itoa(source.Line)
+		}
+	}
+
+	return value.String()
+}
+
+// setAttr sets the value of attr to values if its key is in columns.
+func (ch *csvHandler) setAttr(values []string, prefix string, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+
+		// A group with empty key should be inlined.
+		if attr.Key != "" {
+			prefix = prefix + attr.Key + "."
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+
+		for _, attr := range attrs {
+			ch.setAttr(values, prefix, groups, attr)
+		}
+
+		return
+	}
+
+	if ch.opts.ReplaceAttr != nil {
+		attr = ch.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	if index, ok := ch.indexes[prefix+attr.Key]; ok {
+		values[index] = csvValue(attr.Value)
+	}
+}
+
+// setBuiltinAttr sets the value of a builtin attr like time to values.
+// The attr will be passed to ReplaceAttr first, and it will be discarded if its key becomes empty.
+func (ch *csvHandler) setBuiltinAttr(values []string, column string, value slog.Value) {
+	index, ok := ch.indexes[column]
+	if !ok {
+		return
+	}
+
+	attr := slog.Attr{Key: column, Value: value}
+	if ch.opts.ReplaceAttr != nil {
+		attr = ch.opts.ReplaceAttr(nil, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	values[index] = csvValue(attr.Value)
+}
+
+// WithAttrs returns a new handler with attrs.
+func (ch *csvHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return ch
+	}
+
+	values := slices.Clone(ch.values)
+	for _, attr := range attrs {
+		ch.setAttr(values, ch.prefix, ch.groups, attr)
+	}
+
+	handler := *ch
+	handler.values = values
+
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (ch *csvHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return ch
+	}
+
+	handler := *ch
+	handler.prefix = ch.prefix + name + "."
+	handler.groups = append(slices.Clip(ch.groups), name)
+
+	return &handler
+}
+
+// Enabled reports whether the logger should ignore logs whose level is lower than passed level.
+func (ch *csvHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= ch.opts.Level.Level()
+}
+
+// Handle handles one record and returns an error if failed.
+func (ch *csvHandler) Handle(ctx context.Context, record slog.Record) error {
+	values := slices.Clone(ch.values)
+
+	if !record.Time.IsZero() {
+		ch.setBuiltinAttr(values, slog.TimeKey, slog.TimeValue(record.Time))
+	}
+
+	ch.setBuiltinAttr(values, slog.LevelKey, slog.AnyValue(record.Level))
+	ch.setBuiltinAttr(values, slog.MessageKey, slog.StringValue(record.Message))
+
+	if ch.opts.AddSource && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+
+		source := &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+		ch.setBuiltinAttr(values, slog.SourceKey, slog.AnyValue(source))
+	}
+
+	if record.NumAttrs() > 0 {
+		record.Attrs(func(attr slog.Attr) bool {
+			ch.setAttr(values, ch.prefix, ch.groups, attr)
+			return true
+		})
+	}
+
+	// Setup a buffer for handling record.
+	buffer := newBuffer()
+	bs := buffer.bs
+
+	defer func() {
+		buffer.bs = bs
+		freeBuffer(buffer)
+	}()
+
+	for i, value := range values {
+		bs = appendCSVField(bs, i, value)
+	}
+
+	bs = append(bs, '\n')
+	return ch.writer.write(ch.header, bs)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAppendCSVField$
+func TestAppendCSVField(t *testing.T) {
+	fields := []string{"", "abc", "a,b", `say "hi"`, "line1\nline2", "\r", " leading", "\ttab", "中文"}
+
+	var bs []byte
+	for i, field := range fields {
+		bs = appendCSVField(bs, i, field)
+	}
+
+	bs = append(bs, '\n')
+
+	records, err := csv.NewReader(bytes.NewReader(bs)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || !reflect.DeepEqual(records[0], fields) {
+		t.Fatalf("records %q != fields %q", records, fields)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCSVHandler$
+func TestCSVHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				attr.Value = slog.Int64Value(1)
+			}
+
+			if attr.Key == "password" {
+				attr.Value = slog.StringValue("******")
+			}
+
+			return attr
+		},
+	}
+
+	columns := []string{"time", "level", "msg", "user.id", "user.password", "err", "missing"}
+	handler := NewCSVHandler(buffer, opts, columns...)
+
+	if handler.Enabled(context.Background(), slog.LevelDebug-1) {
+		t.Fatal("level debug-1 is enabled")
+	}
+
+	logger := slog.New(handler).With("ignored", 1).WithGroup("user").With("id", 123)
+	logger.Info("login", "password", "123456", slog.Group("", slog.Any("err", errors.New("wrong, password"))))
+	logger.Debug("say \"hi\"", "id", 456)
+
+	slog.New(handler).Info("group", slog.Group("user", "id", 789))
+
+	want := [][]string{
+		columns,
+		{"1", "INFO", "login", "123", "******", "", ""},
+		{"1", "DEBUG", "say \"hi\"", "456", "", "", ""},
+		{"1", "INFO", "group", "789", "", "", ""},
+	}
+
+	records, err := csv.NewReader(buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != len(want) {
+		t.Fatalf("len(records) %d != len(want) %d", len(records), len(want))
+	}
+
+	for i, record := range records {
+		if !reflect.DeepEqual(record, want[i]) {
+			t.Fatalf("record %q != want %q", record, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCSVHandlerDefaultColumns$
+func TestCSVHandlerDefaultColumns(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	handler := NewCSVHandler(buffer, &slog.HandlerOptions{AddSource: true}, "level", "source")
+	slog.New(handler).Info("msg")
+
+	records, err := csv.NewReader(buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 || records[1][0] != "INFO" || filepath.Base(records[1][1])[:len("csv_test.go:")] != "csv_test.go:" {
+		t.Fatalf("records %q is wrong", records)
+	}
+
+	buffer.Reset()
+	slog.New(NewCSVHandler(buffer, nil)).Info("msg")
+
+	records, err = csv.NewReader(buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 || !reflect.DeepEqual(records[0], DefaultCSVColumns) || records[1][2] != "msg" {
+		t.Fatalf("records %q is wrong", records)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCSVHandlerHeader$
+func TestCSVHandlerHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logit.csv")
+
+	for i := 0; i < 2; i++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Handlers derived from the same handler share the header.
+		handler := NewCSVHandler(file, nil, "level", "msg")
+		slog.New(handler).Info("msg")
+		slog.New(handler.WithGroup("group")).Info("msg")
+
+		if err = file.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "level,msg\nINFO,msg\nINFO,msg\nINFO,msg\nINFO,msg\n"
+	if string(data) != want {
+		t.Fatalf("data %q != want %q", data, want)
+	}
+}
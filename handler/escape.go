@@ -15,10 +15,67 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
 	"unicode/utf8"
 )
 
+// EscapeMode is the mode of escaping strings in tape handler.
+type EscapeMode string
+
+const (
+	// EscapeFull quotes strings having spaces, quotes, '=' or control characters like slog's text handler.
+	EscapeFull EscapeMode = "full"
+
+	// EscapeMinimal only escapes control characters like newlines, which is the default mode.
+	EscapeMinimal EscapeMode = "minimal"
+
+	// EscapeNone doesn't escape anything, so logs are raw and human-readable.
+	// Notice that a value having newlines will break the log into lines.
+	EscapeNone EscapeMode = "none"
+)
+
+// ParseEscapeMode parses an escape mode from name and returns an error if failed.
+func ParseEscapeMode(name string) (EscapeMode, error) {
+	switch mode := EscapeMode(name); mode {
+	case EscapeFull, EscapeMinimal, EscapeNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("logit: escape mode %s unknown", name)
+	}
+}
+
+// needQuotedString returns if value need to be quoted in full escape mode.
+func needQuotedString(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return true
+		}
+	}
+
+	return !utf8.ValidString(value)
+}
+
+// appendEscapedStringWith appends value escaped in mode to dst.
+func appendEscapedStringWith(dst []byte, value string, mode EscapeMode) []byte {
+	switch mode {
+	case EscapeFull:
+		if needQuotedString(value) {
+			return strconv.AppendQuote(dst, value)
+		}
+
+		return append(dst, value...)
+	case EscapeNone:
+		return append(dst, value...)
+	default:
+		return appendEscapedString(dst, value)
+	}
+}
+
 // needEscapedByte returns if value need to escape.
 // The main character should be escaped is ascii less than \u0020.
 func needEscapedByte(value byte) bool {
@@ -14,7 +14,11 @@
 
 package handler
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
 
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestAppendEscapedByte$
 func TestAppendEscapedByte(t *testing.T) {
@@ -45,3 +49,76 @@ func TestAppendEscapedString(t *testing.T) {
 		t.Errorf("result %s is wrong", string(buffer))
 	}
 }
+
+// go test -v -run=^$ -fuzz=^FuzzAppendEscapedString$ -fuzztime=10s
+func FuzzAppendEscapedString(f *testing.F) {
+	f.Add("")
+	f.Add("abc")
+	f.Add("\b\f\n\r\t\x00\x1f")
+	f.Add("你好\n世界")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		escaped := appendEscapedString(nil, value)
+
+		for _, b := range escaped {
+			if needEscapedByte(b) {
+				t.Fatalf("escaped %q contains byte %d needs escaping", escaped, b)
+			}
+		}
+
+		needEscaped := strings.IndexFunc(value, func(r rune) bool { return r < 32 }) >= 0
+		if !needEscaped && string(escaped) != value {
+			t.Fatalf("escaped %q != value %q", escaped, value)
+		}
+
+		prefix := []byte("prefix")
+		if got := appendEscapedString(prefix, value); !bytes.Equal(got[len(prefix):], escaped) {
+			t.Fatalf("got %q != escaped %q", got[len(prefix):], escaped)
+		}
+	})
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseEscapeMode$
+func TestParseEscapeMode(t *testing.T) {
+	for _, mode := range []EscapeMode{EscapeFull, EscapeMinimal, EscapeNone} {
+		parsed, err := ParseEscapeMode(string(mode))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parsed != mode {
+			t.Fatalf("parsed %s != mode %s", parsed, mode)
+		}
+	}
+
+	if _, err := ParseEscapeMode("unknown"); err == nil {
+		t.Fatal("parsing unknown mode should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAppendEscapedStringWith$
+func TestAppendEscapedStringWith(t *testing.T) {
+	testcases := []struct {
+		mode  EscapeMode
+		value string
+		want  string
+	}{
+		{mode: EscapeFull, value: "abc", want: `abc`},
+		{mode: EscapeFull, value: "", want: `""`},
+		{mode: EscapeFull, value: "a b", want: `"a b"`},
+		{mode: EscapeFull, value: `say "hi"`, want: `"say \"hi\""`},
+		{mode: EscapeFull, value: "k=v", want: `"k=v"`},
+		{mode: EscapeFull, value: "a\nb", want: `"a\nb"`},
+		{mode: EscapeMinimal, value: `say "hi"`, want: `say "hi"`},
+		{mode: EscapeMinimal, value: "a\nb", want: `a\nb`},
+		{mode: EscapeNone, value: `say "hi"`, want: `say "hi"`},
+		{mode: EscapeNone, value: "a\nb", want: "a\nb"},
+	}
+
+	for _, testcase := range testcases {
+		got := appendEscapedStringWith(nil, testcase.value, testcase.mode)
+		if string(got) != testcase.want {
+			t.Fatalf("mode %s: got %s != want %s", testcase.mode, got, testcase.want)
+		}
+	}
+}
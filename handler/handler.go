@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"sync"
 )
 
@@ -25,6 +26,17 @@ const (
 	Tape = "tape"
 	Text = "text"
 	Json = "json"
+	CSV  = "csv"
+
+	Console = "console"
+
+	Protobuf = "protobuf"
+
+	Journal       = "journal"
+	JournalExport = "journal_export"
+
+	// Auto picks a handler by the writer, see AutoName.
+	Auto = "auto"
 )
 
 var (
@@ -33,10 +45,28 @@ var (
 			return NewTapeHandler(w, opts)
 		},
 		Text: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewTextHandler(w, opts)
+			return newTextHandler(w, opts)
 		},
 		Json: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewJSONHandler(w, opts)
+			return newJSONHandler(w, opts)
+		},
+		Console: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewConsoleHandler(w, opts)
+		},
+		CSV: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewCSVHandler(w, opts)
+		},
+		Protobuf: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewProtobufHandler(w, opts)
+		},
+		Journal: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewJournalHandler(w, opts)
+		},
+		JournalExport: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewJournalExportHandler(w, opts)
+		},
+		Auto: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return NewAutoHandler(w, opts)
 		},
 	}
 )
@@ -60,6 +90,20 @@ func Get(name string) (NewHandlerFunc, error) {
 	return nil, fmt.Errorf("logit: handler %s not found", name)
 }
 
+// Names returns the sorted names of all handlers registered.
+func Names() []string {
+	newHandlersLock.RLock()
+	defer newHandlersLock.RUnlock()
+
+	names := make([]string, 0, len(newHandlers))
+	for name := range newHandlers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
 // Register registers newHandler with name.
 func Register(name string, newHandler NewHandlerFunc) error {
 	newHandlersLock.Lock()
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"testing"
 )
 
@@ -64,3 +65,22 @@ func TestRegister(t *testing.T) {
 		t.Fatal("newHandler registered is wrong")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNames$
+func TestNames(t *testing.T) {
+	handler := t.Name()
+	if err := Register(handler, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	names := Names()
+	if !slices.IsSorted(names) {
+		t.Fatalf("names %+v aren't sorted", names)
+	}
+
+	for _, name := range []string{Tape, Json, Auto, handler} {
+		if !slices.Contains(names, name) {
+			t.Fatalf("names %+v don't contain %s", names, name)
+		}
+	}
+}
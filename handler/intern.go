@@ -0,0 +1,65 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// internMaxLen is the max length of strings interned.
+	// Long strings are unlikely to repeat, and escaping them costs less than caching them relatively.
+	internMaxLen = 64
+)
+
+// internTable caches escaped bytes of strings, so repeated strings are copied instead of being escaped again.
+// It stops caching new strings if it's full, so it should be used for strings in small sets like status names and route templates.
+type internTable struct {
+	escape     EscapeMode
+	maxEntries int64
+	entries    atomic.Int64
+	escaped    sync.Map
+}
+
+func newInternTable(escape EscapeMode, maxEntries int) *internTable {
+	return &internTable{
+		escape:     escape,
+		maxEntries: int64(maxEntries),
+	}
+}
+
+// appendString appends value escaped to dst.
+func (it *internTable) appendString(dst []byte, value string) []byte {
+	if len(value) > internMaxLen {
+		return appendEscapedStringWith(dst, value, it.escape)
+	}
+
+	if escaped, ok := it.escaped.Load(value); ok {
+		return append(dst, escaped.([]byte)...)
+	}
+
+	escaped := appendEscapedStringWith(nil, value, it.escape)
+
+	// The value may refer to a larger buffer like a request body, so clone it before caching.
+	if it.entries.Load() < it.maxEntries {
+		if _, loaded := it.escaped.LoadOrStore(strings.Clone(value), escaped); !loaded {
+			it.entries.Add(1)
+		}
+	}
+
+	return append(dst, escaped...)
+}
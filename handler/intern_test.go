@@ -0,0 +1,77 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestInternTable$
+func TestInternTable(t *testing.T) {
+	table := newInternTable(EscapeFull, 2)
+
+	if got := string(table.appendString(nil, "not found")); got != `"not found"` {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if got := string(table.appendString([]byte("status="), "not found")); got != `status="not found"` {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	table.appendString(nil, "ok")
+	table.appendString(nil, "failed")
+
+	if entries := table.entries.Load(); entries != 2 {
+		t.Fatalf("entries %d != 2", entries)
+	}
+
+	if _, ok := table.escaped.Load("failed"); ok {
+		t.Fatal("failed shouldn't be cached after the table is full")
+	}
+
+	long := strings.Repeat("x", internMaxLen+1)
+	if got := string(newInternTable(EscapeNone, 2).appendString(nil, long)); got != long {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTapeInterning$
+func TestWithTapeInterning(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	handler := NewTapeHandler(buffer, nil, WithTapeInterning(16), WithTapeEscape(EscapeFull))
+	if table := handler.(*tapeHandler).intern; table == nil || table.escape != EscapeFull {
+		t.Fatalf("table %+v is wrong", table)
+	}
+
+	logger := slog.New(handler).WithGroup("req")
+	for i := 0; i < 3; i++ {
+		logger.Info("request done", "route", "/users/{id}", "status", "not found")
+	}
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), string(lineBreak))
+	for _, log := range logs {
+		if !strings.HasSuffix(log, `"request done" ¦ req.route=/users/{id} ¦ req.status="not found"`) {
+			t.Fatalf("log %s is wrong", log)
+		}
+	}
+
+	if _, ok := handler.(*tapeHandler).intern.escaped.Load("route"); !ok {
+		t.Fatal("key route should be cached")
+	}
+}
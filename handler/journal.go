@@ -0,0 +1,289 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apashe License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apashe.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// journalFieldMaxLength is the max length of field names accepted by journald.
+	journalFieldMaxLength = 64
+)
+
+type journalHandler struct {
+	w    io.Writer
+	opts slog.HandlerOptions
+
+	// prefix is the groups converted to field names and joined with '_', like "A_B_".
+	prefix string
+	groups []string
+
+	// preformatted is the fields of attrs added by WithAttrs.
+	preformatted []byte
+
+	// export is true if records are written in journal export format.
+	export bool
+
+	lock *sync.Mutex
+}
+
+// NewJournalHandler creates a journal handler with w and opts.
+// This handler writes records in journald's native protocol, one record in one write.
+// The level is mapped to PRIORITY and attrs are mapped to fields whose names are upper case like "USER_ID".
+// Groups are joined to field names with '_', so attr "id" in group "user" is mapped to "USER_ID".
+// Writing to journald socket directly is recommended, since the datagrams of records shouldn't be buffered or merged.
+func NewJournalHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+
+	handler := &journalHandler{
+		w:    w,
+		opts: *opts,
+		lock: &sync.Mutex{},
+	}
+
+	return handler
+}
+
+// NewJournalExportHandler creates a journal handler in export format with w and opts.
+// Records are written like journal handler, but each record carries a SYSLOG_IDENTIFIER field and ends with an empty line.
+// It's useful for hosts capturing outputs of units and ingesting them as journal export format.
+// See defaults.JournalIdentifier.
+func NewJournalExportHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	handler := NewJournalHandler(w, opts).(*journalHandler)
+	handler.export = true
+
+	return handler
+}
+
+// journalFieldName converts name to a field name accepted by journald.
+// A field name only contains upper case letters, digits and '_', and it can't start with '_' or a digit.
+func journalFieldName(name string) string {
+	bs := make([]byte, 0, len(name))
+	for i := 0; i < len(name) && len(bs) < journalFieldMaxLength; i++ {
+		c := name[i]
+
+		switch {
+		case c >= 'a' && c <= 'z':
+			bs = append(bs, c-'a'+'A')
+		case c >= 'A' && c <= 'Z':
+			bs = append(bs, c)
+		case c >= '0' && c <= '9':
+			if len(bs) > 0 {
+				bs = append(bs, c)
+			}
+		default:
+			if len(bs) > 0 {
+				bs = append(bs, '_')
+			}
+		}
+	}
+
+	return string(bs)
+}
+
+// journalPriority maps level to the priority of syslog used by journald.
+func journalPriority(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError+4:
+		return 2 // crit
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// appendJournalField appends a field in native protocol.
+// A value having line breaks will be appended in binary form.
+func appendJournalField(bs []byte, name string, value string) []byte {
+	if strings.IndexByte(value, '\n') < 0 {
+		bs = append(bs, name...)
+		bs = append(bs, '=')
+		bs = append(bs, value...)
+		bs = append(bs, '\n')
+
+		return bs
+	}
+
+	bs = append(bs, name...)
+	bs = append(bs, '\n')
+	bs = binary.LittleEndian.AppendUint64(bs, uint64(len(value)))
+	bs = append(bs, value...)
+	bs = append(bs, '\n')
+
+	return bs
+}
+
+func journalValue(value slog.Value) string {
+	switch value.Kind() {
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+	}
+
+	return value.String()
+}
+
+func (jh *journalHandler) appendAttr(bs []byte, prefix string, groups []string, attr slog.Attr) []byte {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+
+		// A group with empty key should be inlined.
+		if attr.Key != "" {
+			prefix = prefix + journalFieldName(attr.Key) + "_"
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+
+		for _, attr := range attrs {
+			bs = jh.appendAttr(bs, prefix, groups, attr)
+		}
+
+		return bs
+	}
+
+	if jh.opts.ReplaceAttr != nil {
+		attr = jh.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	name := journalFieldName(attr.Key)
+	if name == "" {
+		return bs
+	}
+
+	return appendJournalField(bs, prefix+name, journalValue(attr.Value))
+}
+
+// WithAttrs returns a new handler with attrs.
+func (jh *journalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return jh
+	}
+
+	preformatted := slices.Clip(jh.preformatted)
+	for _, attr := range attrs {
+		preformatted = jh.appendAttr(preformatted, jh.prefix, jh.groups, attr)
+	}
+
+	handler := *jh
+	handler.preformatted = preformatted
+
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (jh *journalHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return jh
+	}
+
+	handler := *jh
+	handler.prefix = jh.prefix + journalFieldName(name) + "_"
+	handler.groups = append(slices.Clip(jh.groups), name)
+
+	return &handler
+}
+
+// Enabled reports whether the logger should ignore logs whose level is lower than passed level.
+func (jh *journalHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= jh.opts.Level.Level()
+}
+
+func (jh *journalHandler) appendSource(bs []byte, pc uintptr) []byte {
+	if !jh.opts.AddSource || pc == 0 {
+		return bs
+	}
+
+	// The source replaced with other values can't be mapped to code fields, so it's removed.
+	source, _, ok := resolveSource(pc, jh.opts.ReplaceAttr)
+	if !ok {
+		return bs
+	}
+
+	bs = appendJournalField(bs, "CODE_FILE", source.File)
+	bs = appendJournalField(bs, "CODE_LINE", strconv.Itoa(source.Line))
+	bs = appendJournalField(bs, "CODE_FUNC", source.Function)
+
+	return bs
+}
+
+// Handle handles one record and returns an error if failed.
+func (jh *journalHandler) Handle(ctx context.Context, record slog.Record) error {
+	// Setup a buffer for handling record.
+	buffer := newBuffer()
+	bs := buffer.bs
+
+	defer func() {
+		buffer.bs = bs
+		freeBuffer(buffer)
+	}()
+
+	// Handling record.
+	bs = appendJournalField(bs, "MESSAGE", record.Message)
+	bs = appendJournalField(bs, "PRIORITY", strconv.Itoa(journalPriority(record.Level)))
+	bs = appendJournalField(bs, "LEVEL", levelName(record.Level))
+
+	if jh.export {
+		bs = appendJournalField(bs, "SYSLOG_IDENTIFIER", defaults.JournalIdentifier)
+	}
+
+	bs = jh.appendSource(bs, record.PC)
+	bs = append(bs, jh.preformatted...)
+
+	if record.NumAttrs() > 0 {
+		record.Attrs(func(attr slog.Attr) bool {
+			bs = jh.appendAttr(bs, jh.prefix, jh.groups, attr)
+			return true
+		})
+	}
+
+	// Records in export format are separated by empty lines.
+	if jh.export {
+		bs = append(bs, '\n')
+	}
+
+	// Write handled record.
+	jh.lock.Lock()
+	defer jh.lock.Unlock()
+
+	_, err := jh.w.Write(bs)
+	return err
+}
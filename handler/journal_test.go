@@ -0,0 +1,139 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apashe License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apashe.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestJournalFieldName$
+func TestJournalFieldName(t *testing.T) {
+	names := map[string]string{
+		"user_id":               "USER_ID",
+		"userID":                "USERID",
+		"user.id":               "USER_ID",
+		"_private":              "PRIVATE",
+		"2fa":                   "FA",
+		"中文":                    "",
+		strings.Repeat("a", 80): strings.Repeat("A", journalFieldMaxLength),
+	}
+
+	for name, want := range names {
+		if got := journalFieldName(name); got != want {
+			t.Fatalf("name %s got %s != want %s", name, got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestJournalPriority$
+func TestJournalPriority(t *testing.T) {
+	levels := map[slog.Level]int{
+		slog.LevelDebug - 4: 7,
+		slog.LevelDebug:     7,
+		slog.LevelInfo:      6,
+		slog.LevelWarn:      4,
+		slog.LevelError:     3,
+		slog.LevelError + 4: 2,
+		slog.LevelError + 8: 2,
+	}
+
+	for level, want := range levels {
+		if got := journalPriority(level); got != want {
+			t.Fatalf("level %s got %d != want %d", level, got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAppendJournalField$
+func TestAppendJournalField(t *testing.T) {
+	got := appendJournalField(nil, "K", "v")
+	if string(got) != "K=v\n" {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	want := []byte("K\n")
+	want = binary.LittleEndian.AppendUint64(want, 3)
+	want = append(want, "a\nb\n"...)
+
+	got = appendJournalField(nil, "K", "a\nb")
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestJournalHandler$
+func TestJournalHandler(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == "secret" {
+			return slog.Attr{}
+		}
+
+		if len(groups) == 2 && attr.Key == "id" {
+			attr.Value = slog.StringValue("replaced")
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewJournalHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: replaceAttr})
+
+	logger := slog.New(handler).With("service", "test").WithGroup("user").With("id", 1)
+	logger.Warn("msg", "secret", "xxx", slog.Group("order", "id", 2), "err", errors.New("failed"))
+
+	want := "MESSAGE=msg\nPRIORITY=4\nLEVEL=WARN\nSERVICE=test\nUSER_ID=1\nUSER_ORDER_ID=replaced\nUSER_ERR=failed\n"
+	if got := buffer.String(); got != want {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+
+	buffer.Reset()
+	handler = NewJournalHandler(buffer, &slog.HandlerOptions{AddSource: true})
+	slog.New(handler).Info("msg")
+
+	got := buffer.String()
+	if !strings.Contains(got, "CODE_FILE=") || !strings.Contains(got, "journal_test.go") || !strings.Contains(got, "CODE_FUNC=") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestJournalExportHandler$
+func TestJournalExportHandler(t *testing.T) {
+	journalIdentifier := defaults.JournalIdentifier
+	defer func() {
+		defaults.JournalIdentifier = journalIdentifier
+	}()
+
+	defaults.JournalIdentifier = "logit"
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(NewJournalExportHandler(buffer, nil))
+
+	logger.Error("msg1", "k", "v")
+	logger.Info("msg2")
+
+	want := "MESSAGE=msg1\nPRIORITY=3\nLEVEL=ERROR\nSYSLOG_IDENTIFIER=logit\nK=v\n\n" +
+		"MESSAGE=msg2\nPRIORITY=6\nLEVEL=INFO\nSYSLOG_IDENTIFIER=logit\n\n"
+
+	if got := buffer.String(); got != want {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+}
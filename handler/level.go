@@ -0,0 +1,224 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// levelNames stores all names of levels registered.
+	// It's copied on writing so reading it doesn't need a lock.
+	levelNames atomic.Pointer[map[slog.Level]string]
+
+	levelNamesLock sync.Mutex
+)
+
+// RegisterLevelName registers name of level so handlers will output the name instead of level.String().
+// The name registered later will replace the former one.
+func RegisterLevelName(level slog.Level, name string) {
+	levelNamesLock.Lock()
+	defer levelNamesLock.Unlock()
+
+	names := make(map[slog.Level]string, 8)
+	if oldNames := levelNames.Load(); oldNames != nil {
+		for oldLevel, oldName := range *oldNames {
+			names[oldLevel] = oldName
+		}
+	}
+
+	names[level] = name
+	levelNames.Store(&names)
+}
+
+// LevelNames returns a copy of all names of levels registered.
+// It's useful for saving names before registering some temporarily, like in tests, see SetLevelNames.
+func LevelNames() map[slog.Level]string {
+	names := make(map[slog.Level]string, 8)
+	if registered := levelNames.Load(); registered != nil {
+		for level, name := range *registered {
+			names[level] = name
+		}
+	}
+
+	return names
+}
+
+// SetLevelNames replaces all names of levels registered with names, so levels not in names are unregistered.
+// It's useful for restoring names returned by LevelNames.
+func SetLevelNames(names map[slog.Level]string) {
+	levelNamesLock.Lock()
+	defer levelNamesLock.Unlock()
+
+	newNames := make(map[slog.Level]string, len(names))
+	for level, name := range names {
+		newNames[level] = name
+	}
+
+	levelNames.Store(&newNames)
+}
+
+// levelName returns the registered name of level or level.String() if not registered.
+func levelName(level slog.Level) string {
+	if names := levelNames.Load(); names != nil {
+		if name, ok := (*names)[level]; ok {
+			return name
+		}
+	}
+
+	return level.String()
+}
+
+// LevelName returns the registered name of level or level.String() if not registered.
+// It's useful for outputting levels outside handlers, like labels of metrics.
+func LevelName(level slog.Level) string {
+	return levelName(level)
+}
+
+// LookupLevel returns the level registered with name and reports whether it's found.
+// The name is case-insensitive.
+func LookupLevel(name string) (slog.Level, bool) {
+	if names := levelNames.Load(); names != nil {
+		for level, levelName := range *names {
+			if strings.EqualFold(levelName, name) {
+				return level, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// replaceLevelName wraps replaceAttr so the level will be replaced with its registered name.
+// The level will be passed to replaceAttr first so users can still replace it as a slog.Level.
+func replaceLevelName(replaceAttr func(groups []string, attr slog.Attr) slog.Attr) func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if replaceAttr != nil {
+			attr = replaceAttr(groups, attr)
+		}
+
+		if len(groups) > 0 || attr.Key != slog.LevelKey {
+			return attr
+		}
+
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			attr.Value = slog.StringValue(levelName(level))
+		}
+
+		return attr
+	}
+}
+
+// withLevelNames returns a copy of opts which will replace levels with their registered names.
+func withLevelNames(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	newOpts := new(slog.HandlerOptions)
+	if opts != nil {
+		*newOpts = *opts
+	}
+
+	newOpts.ReplaceAttr = replaceLevelName(newOpts.ReplaceAttr)
+	return newOpts
+}
+
+// hasLevelName reports whether level has a registered name.
+func hasLevelName(level slog.Level) bool {
+	if names := levelNames.Load(); names != nil {
+		_, ok := (*names)[level]
+		return ok
+	}
+
+	return false
+}
+
+// lockedWriter serializes writes of handlers sharing the same writer.
+type lockedWriter struct {
+	writer io.Writer
+	lock   sync.Mutex
+}
+
+func (lw *lockedWriter) Write(p []byte) (n int, err error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	return lw.writer.Write(p)
+}
+
+// levelNameHandler handles records in levels having registered names with a handler replacing levels with their names.
+// Slog calls ReplaceAttr for every attr if it's set, so records in other levels are handled by a handler without it.
+type levelNameHandler struct {
+	handler      slog.Handler
+	namedHandler slog.Handler
+}
+
+// newLevelNameHandler creates a level name handler with two handlers created by newHandler, which write to the same writer.
+func newLevelNameHandler(w io.Writer, opts *slog.HandlerOptions, newHandler func(w io.Writer, opts *slog.HandlerOptions) slog.Handler) slog.Handler {
+	writer := &lockedWriter{writer: w}
+
+	handler := levelNameHandler{
+		handler:      newHandler(writer, opts),
+		namedHandler: newHandler(writer, withLevelNames(opts)),
+	}
+
+	return handler
+}
+
+func (lnh levelNameHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return lnh.handler.Enabled(ctx, level)
+}
+
+func (lnh levelNameHandler) Handle(ctx context.Context, record slog.Record) error {
+	if hasLevelName(record.Level) {
+		return lnh.namedHandler.Handle(ctx, record)
+	}
+
+	return lnh.handler.Handle(ctx, record)
+}
+
+func (lnh levelNameHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := levelNameHandler{
+		handler:      lnh.handler.WithAttrs(attrs),
+		namedHandler: lnh.namedHandler.WithAttrs(attrs),
+	}
+
+	return handler
+}
+
+func (lnh levelNameHandler) WithGroup(name string) slog.Handler {
+	handler := levelNameHandler{
+		handler:      lnh.handler.WithGroup(name),
+		namedHandler: lnh.namedHandler.WithGroup(name),
+	}
+
+	return handler
+}
+
+// newTextHandler creates a slog.TextHandler outputting registered names of levels.
+func newTextHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return newLevelNameHandler(w, opts, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewTextHandler(w, opts)
+	})
+}
+
+// newJSONHandler creates a slog.JSONHandler outputting registered names of levels.
+func newJSONHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return newLevelNameHandler(w, opts, func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		return slog.NewJSONHandler(w, opts)
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLevelName$
+func TestRegisterLevelName(t *testing.T) {
+	level := slog.Level(99)
+	if name := levelName(level); name != level.String() {
+		t.Fatalf("name %s != level.String() %s", name, level.String())
+	}
+
+	RegisterLevelName(level, "TEST")
+	if name := levelName(level); name != "TEST" {
+		t.Fatalf("name %s != 'TEST'", name)
+	}
+
+	RegisterLevelName(level, "TEST2")
+	if name := levelName(level); name != "TEST2" {
+		t.Fatalf("name %s != 'TEST2'", name)
+	}
+
+	if name := levelName(slog.LevelInfo); name != slog.LevelInfo.String() {
+		t.Fatalf("name %s != slog.LevelInfo.String() %s", name, slog.LevelInfo.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelName$
+func TestLevelName(t *testing.T) {
+	if name := LevelName(slog.Level(95)); name != slog.Level(95).String() {
+		t.Fatalf("name %s != slog.Level(95).String() %s", name, slog.Level(95).String())
+	}
+
+	RegisterLevelName(slog.Level(95), "NAMED")
+
+	if name := LevelName(slog.Level(95)); name != "NAMED" {
+		t.Fatalf("name %s != 'NAMED'", name)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSetLevelNames$
+func TestSetLevelNames(t *testing.T) {
+	names := LevelNames()
+	defer SetLevelNames(names)
+
+	RegisterLevelName(slog.Level(97), "TEMP")
+
+	got := LevelNames()
+	if got[slog.Level(97)] != "TEMP" {
+		t.Fatalf("got %+v is wrong", got)
+	}
+
+	// The map returned is a copy, so modifying it won't affect names registered.
+	got[slog.Level(97)] = "MODIFIED"
+	if name := LevelName(slog.Level(97)); name != "TEMP" {
+		t.Fatalf("name %s != 'TEMP'", name)
+	}
+
+	SetLevelNames(names)
+
+	if name := LevelName(slog.Level(97)); name != slog.Level(97).String() {
+		t.Fatalf("name %s != slog.Level(97).String() %s", name, slog.Level(97).String())
+	}
+
+	if _, ok := LookupLevel("TEMP"); ok {
+		t.Fatal("level TEMP should be unregistered")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLookupLevel$
+func TestLookupLevel(t *testing.T) {
+	if _, ok := LookupLevel("LOOKUP"); ok {
+		t.Fatal("level LOOKUP shouldn't be found")
+	}
+
+	RegisterLevelName(slog.Level(97), "LOOKUP")
+
+	level, ok := LookupLevel("lookup")
+	if !ok {
+		t.Fatal("level lookup not found")
+	}
+
+	if level != slog.Level(97) {
+		t.Fatalf("level %d != 97", level)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevelNames$
+func TestWithLevelNames(t *testing.T) {
+	level := slog.Level(98)
+	RegisterLevelName(level, "NAMED")
+
+	replaced := false
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey {
+				_, replaced = attr.Value.Any().(slog.Level)
+			}
+
+			return attr
+		},
+	}
+
+	for _, name := range []string{Tape, Text, Json} {
+		newHandler, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		logger := slog.New(newHandler(buffer, opts))
+		logger.Log(context.Background(), level, "msg")
+
+		if !strings.Contains(buffer.String(), "NAMED") {
+			t.Fatalf("handler %s output %s without level name", name, buffer.String())
+		}
+	}
+
+	if !replaced {
+		t.Fatal("replaceAttr should receive level before replacing its name")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelNameHandler$
+func TestLevelNameHandler(t *testing.T) {
+	level := slog.Level(96)
+	RegisterLevelName(level, "NAMED")
+
+	var replaceAttrs []bool
+	newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		replaceAttrs = append(replaceAttrs, opts != nil && opts.ReplaceAttr != nil)
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := newLevelNameHandler(buffer, nil, newHandler)
+
+	// Only the handler for levels having names replaces attrs, so others keep the fast path of slog.
+	if len(replaceAttrs) != 2 || replaceAttrs[0] || !replaceAttrs[1] {
+		t.Fatalf("replaceAttrs %+v is wrong", replaceAttrs)
+	}
+
+	logger := slog.New(handler).With("key", "value").WithGroup("group")
+	logger.Info("info", "number", 1)
+	logger.Log(context.Background(), level, "named", "number", 2)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("len(lines) %d != 2", len(lines))
+	}
+
+	if !strings.Contains(lines[0], `"level":"INFO","msg":"info","key":"value","group":{"number":1}}`) {
+		t.Fatalf("lines[0] %s is wrong", lines[0])
+	}
+
+	if !strings.Contains(lines[1], `"level":"NAMED","msg":"named","key":"value","group":{"number":2}}`) {
+		t.Fatalf("lines[1] %s is wrong", lines[1])
+	}
+}
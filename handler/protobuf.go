@@ -0,0 +1,337 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Field numbers and wire types defined in protobuf.proto.
+const (
+	protobufVarint = 0
+	protobufI64    = 1
+	protobufLen    = 2
+
+	protobufRecordTime      = 1
+	protobufRecordLevel     = 2
+	protobufRecordLevelName = 3
+	protobufRecordMessage   = 4
+	protobufRecordSource    = 5
+	protobufRecordAttrs     = 6
+
+	protobufSourceFile     = 1
+	protobufSourceLine     = 2
+	protobufSourceFunction = 3
+
+	protobufAttrKey   = 1
+	protobufAttrValue = 2
+
+	protobufValueString   = 1
+	protobufValueInt      = 2
+	protobufValueUint     = 3
+	protobufValueFloat    = 4
+	protobufValueBool     = 5
+	protobufValueDuration = 6
+	protobufValueTime     = 7
+	protobufValueGroup    = 8
+
+	protobufGroupAttrs = 1
+)
+
+func appendProtobufTag(bs []byte, field int, wireType int) []byte {
+	return binary.AppendUvarint(bs, uint64(field)<<3|uint64(wireType))
+}
+
+func appendProtobufVarint(bs []byte, field int, value uint64) []byte {
+	bs = appendProtobufTag(bs, field, protobufVarint)
+	return binary.AppendUvarint(bs, value)
+}
+
+func appendProtobufBytes(bs []byte, field int, value []byte) []byte {
+	bs = appendProtobufTag(bs, field, protobufLen)
+	bs = binary.AppendUvarint(bs, uint64(len(value)))
+	return append(bs, value...)
+}
+
+func appendProtobufString(bs []byte, field int, value string) []byte {
+	bs = appendProtobufTag(bs, field, protobufLen)
+	bs = binary.AppendUvarint(bs, uint64(len(value)))
+	return append(bs, value...)
+}
+
+// appendProtobufValue appends value as a Value message without tag and length.
+// The field in oneof is always appended even if it's zero, so the kind can be known.
+func appendProtobufValue(bs []byte, value slog.Value) []byte {
+	switch value.Kind() {
+	case slog.KindString:
+		return appendProtobufString(bs, protobufValueString, value.String())
+	case slog.KindInt64:
+		return appendProtobufVarint(bs, protobufValueInt, uint64(value.Int64()))
+	case slog.KindUint64:
+		return appendProtobufVarint(bs, protobufValueUint, value.Uint64())
+	case slog.KindFloat64:
+		bs = appendProtobufTag(bs, protobufValueFloat, protobufI64)
+		return binary.LittleEndian.AppendUint64(bs, math.Float64bits(value.Float64()))
+	case slog.KindBool:
+		if value.Bool() {
+			return appendProtobufVarint(bs, protobufValueBool, 1)
+		}
+
+		return appendProtobufVarint(bs, protobufValueBool, 0)
+	case slog.KindDuration:
+		return appendProtobufVarint(bs, protobufValueDuration, uint64(value.Duration()))
+	case slog.KindTime:
+		return appendProtobufVarint(bs, protobufValueTime, uint64(value.Time().UnixNano()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return appendProtobufString(bs, protobufValueString, err.Error())
+		}
+
+		return appendProtobufString(bs, protobufValueString, fmt.Sprintf("%+v", value.Any()))
+	default:
+		return appendProtobufString(bs, protobufValueString, value.String())
+	}
+}
+
+// appendProtobufAttr appends an Attr message with key and the encoded Value message as a field.
+func appendProtobufAttr(bs []byte, field int, key string, value []byte) []byte {
+	attr := make([]byte, 0, len(key)+len(value)+8)
+	attr = appendProtobufString(attr, protobufAttrKey, key)
+	attr = appendProtobufBytes(attr, protobufAttrValue, value)
+
+	return appendProtobufBytes(bs, field, attr)
+}
+
+// appendProtobufGroup appends a group attr whose attrs are encoded, and empty groups are omitted like slog.
+func appendProtobufGroup(bs []byte, field int, key string, attrs []byte) []byte {
+	if len(attrs) <= 0 {
+		return bs
+	}
+
+	value := appendProtobufBytes(nil, protobufValueGroup, attrs)
+	return appendProtobufAttr(bs, field, key, value)
+}
+
+type protobufHandler struct {
+	w    io.Writer
+	opts slog.HandlerOptions
+
+	groups []string
+
+	// preformatted is the attrs added by WithAttrs in each level of groups, so its length is len(groups)+1.
+	// Attrs in the first level are fields of Record, and attrs in other levels are fields of Group.
+	preformatted [][]byte
+
+	lock *sync.Mutex
+}
+
+// NewProtobufHandler creates a protobuf handler with w and opts.
+// This handler writes records as Record messages defined in protobuf.proto, prefixed with their length in varint.
+// It encodes messages by itself, so you don't need to import any protobuf library.
+func NewProtobufHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+
+	handler := &protobufHandler{
+		w:            w,
+		opts:         *opts,
+		preformatted: make([][]byte, 1),
+		lock:         &sync.Mutex{},
+	}
+
+	return handler
+}
+
+// attrsField returns the field number of attrs in the level of groups.
+func attrsField(level int) int {
+	if level == 0 {
+		return protobufRecordAttrs
+	}
+
+	return protobufGroupAttrs
+}
+
+func (ph *protobufHandler) appendAttr(bs []byte, field int, groups []string, attr slog.Attr) []byte {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+
+		// A group with empty key should be inlined.
+		if attr.Key == "" {
+			for _, attr := range attrs {
+				bs = ph.appendAttr(bs, field, groups, attr)
+			}
+
+			return bs
+		}
+
+		groups = append(slices.Clip(groups), attr.Key)
+
+		var group []byte
+		for _, attr := range attrs {
+			group = ph.appendAttr(group, protobufGroupAttrs, groups, attr)
+		}
+
+		return appendProtobufGroup(bs, field, attr.Key, group)
+	}
+
+	if ph.opts.ReplaceAttr != nil {
+		attr = ph.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Key == "" {
+		return bs
+	}
+
+	value := appendProtobufValue(nil, attr.Value)
+	return appendProtobufAttr(bs, field, attr.Key, value)
+}
+
+// WithAttrs returns a new handler with attrs.
+func (ph *protobufHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return ph
+	}
+
+	level := len(ph.groups)
+	preformatted := slices.Clip(ph.preformatted[level])
+
+	for _, attr := range attrs {
+		preformatted = ph.appendAttr(preformatted, attrsField(level), ph.groups, attr)
+	}
+
+	handler := *ph
+	handler.preformatted = slices.Clone(ph.preformatted)
+	handler.preformatted[level] = preformatted
+
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (ph *protobufHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return ph
+	}
+
+	handler := *ph
+	handler.groups = append(slices.Clip(ph.groups), name)
+	handler.preformatted = append(slices.Clip(ph.preformatted), nil)
+
+	return &handler
+}
+
+// Enabled reports whether the logger should ignore logs whose level is lower than passed level.
+func (ph *protobufHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= ph.opts.Level.Level()
+}
+
+func (ph *protobufHandler) appendSource(bs []byte, pc uintptr) []byte {
+	if !ph.opts.AddSource || pc == 0 {
+		return bs
+	}
+
+	// The source replaced with other values can't be encoded as a source, so it's removed.
+	resolved, _, ok := resolveSource(pc, ph.opts.ReplaceAttr)
+	if !ok {
+		return bs
+	}
+
+	source := make([]byte, 0, len(resolved.File)+len(resolved.Function)+16)
+	source = appendProtobufString(source, protobufSourceFile, resolved.File)
+	source = appendProtobufVarint(source, protobufSourceLine, uint64(resolved.Line))
+	source = appendProtobufString(source, protobufSourceFunction, resolved.Function)
+
+	return appendProtobufBytes(bs, protobufRecordSource, source)
+}
+
+// appendAttrs appends preformatted attrs and attrs of record, wrapped by groups from the innermost one.
+func (ph *protobufHandler) appendAttrs(bs []byte, record slog.Record) []byte {
+	level := len(ph.groups)
+	attrs := slices.Clip(ph.preformatted[level])
+
+	if record.NumAttrs() > 0 {
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs = ph.appendAttr(attrs, attrsField(level), ph.groups, attr)
+			return true
+		})
+	}
+
+	for level > 0 {
+		group := attrs
+		level--
+
+		attrs = slices.Clip(ph.preformatted[level])
+		attrs = appendProtobufGroup(attrs, attrsField(level), ph.groups[level], group)
+	}
+
+	return append(bs, attrs...)
+}
+
+// Handle handles one record and returns an error if failed.
+func (ph *protobufHandler) Handle(ctx context.Context, record slog.Record) error {
+	// Setup a buffer for handling record.
+	buffer := newBuffer()
+	bs := buffer.bs
+
+	defer func() {
+		buffer.bs = bs
+		freeBuffer(buffer)
+	}()
+
+	// Handling record.
+	if !record.Time.IsZero() {
+		bs = appendProtobufVarint(bs, protobufRecordTime, uint64(record.Time.UnixNano()))
+	}
+
+	if record.Level != 0 {
+		bs = appendProtobufVarint(bs, protobufRecordLevel, uint64(int64(record.Level)))
+	}
+
+	bs = appendProtobufString(bs, protobufRecordLevelName, levelName(record.Level))
+
+	if record.Message != "" {
+		bs = appendProtobufString(bs, protobufRecordMessage, record.Message)
+	}
+
+	bs = ph.appendSource(bs, record.PC)
+	bs = ph.appendAttrs(bs, record)
+
+	// Prefix the record with its length, and write them in one write.
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(bs)))
+	bs = append(bs, prefix[:n]...)
+	copy(bs[n:], bs[:len(bs)-n])
+	copy(bs, prefix[:n])
+
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	_, err := ph.w.Write(bs)
+	return err
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type protobufTestField struct {
+	number int
+	varint uint64
+	bytes  []byte
+}
+
+// decodeProtobufTestFields decodes fields of a message for testing.
+func decodeProtobufTestFields(t *testing.T, bs []byte) []protobufTestField {
+	var fields []protobufTestField
+
+	for len(bs) > 0 {
+		tag, n := binary.Uvarint(bs)
+		if n <= 0 {
+			t.Fatalf("tag of %v is wrong", bs)
+		}
+
+		bs = bs[n:]
+		field := protobufTestField{number: int(tag >> 3)}
+
+		switch tag & 7 {
+		case protobufVarint:
+			field.varint, n = binary.Uvarint(bs)
+			if n <= 0 {
+				t.Fatalf("varint of %v is wrong", bs)
+			}
+
+			bs = bs[n:]
+		case protobufI64:
+			field.varint = binary.LittleEndian.Uint64(bs)
+			bs = bs[8:]
+		case protobufLen:
+			length, n := binary.Uvarint(bs)
+			if n <= 0 || uint64(len(bs)-n) < length {
+				t.Fatalf("length of %v is wrong", bs)
+			}
+
+			field.bytes = bs[n : n+int(length)]
+			bs = bs[n+int(length):]
+		default:
+			t.Fatalf("wire type of tag %d is wrong", tag)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// decodeProtobufTestAttrs decodes attrs to a map whose keys are joined with '.'.
+func decodeProtobufTestAttrs(t *testing.T, attrs map[string]any, prefix string, bs []byte) {
+	var key string
+	var value []byte
+
+	for _, field := range decodeProtobufTestFields(t, bs) {
+		switch field.number {
+		case protobufAttrKey:
+			key = string(field.bytes)
+		case protobufAttrValue:
+			value = field.bytes
+		}
+	}
+
+	for _, field := range decodeProtobufTestFields(t, value) {
+		switch field.number {
+		case protobufValueString:
+			attrs[prefix+key] = string(field.bytes)
+		case protobufValueInt:
+			attrs[prefix+key] = int64(field.varint)
+		case protobufValueUint:
+			attrs[prefix+key] = field.varint
+		case protobufValueFloat:
+			attrs[prefix+key] = math.Float64frombits(field.varint)
+		case protobufValueBool:
+			attrs[prefix+key] = field.varint == 1
+		case protobufValueDuration:
+			attrs[prefix+key] = time.Duration(field.varint)
+		case protobufValueTime:
+			attrs[prefix+key] = time.Unix(0, int64(field.varint))
+		case protobufValueGroup:
+			for _, attr := range decodeProtobufTestFields(t, field.bytes) {
+				decodeProtobufTestAttrs(t, attrs, prefix+key+".", attr.bytes)
+			}
+		}
+	}
+}
+
+type protobufTestRecord struct {
+	time      int64
+	level     int32
+	levelName string
+	message   string
+	source    string
+	attrs     map[string]any
+}
+
+func readProtobufTestRecords(t *testing.T, r io.Reader) []protobufTestRecord {
+	var records []protobufTestRecord
+
+	reader := bufio.NewReader(r)
+	for {
+		length, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			return records
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bs := make([]byte, length)
+		if _, err = io.ReadFull(reader, bs); err != nil {
+			t.Fatal(err)
+		}
+
+		record := protobufTestRecord{attrs: make(map[string]any)}
+		for _, field := range decodeProtobufTestFields(t, bs) {
+			switch field.number {
+			case protobufRecordTime:
+				record.time = int64(field.varint)
+			case protobufRecordLevel:
+				record.level = int32(field.varint)
+			case protobufRecordLevelName:
+				record.levelName = string(field.bytes)
+			case protobufRecordMessage:
+				record.message = string(field.bytes)
+			case protobufRecordSource:
+				for _, sourceField := range decodeProtobufTestFields(t, field.bytes) {
+					if sourceField.number == protobufSourceFile {
+						record.source = filepath.Base(string(sourceField.bytes))
+					}
+				}
+			case protobufRecordAttrs:
+				decodeProtobufTestAttrs(t, record.attrs, "", field.bytes)
+			}
+		}
+
+		records = append(records, record)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestProtobufHandler$
+func TestProtobufHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	opts := &slog.HandlerOptions{
+		Level:     slog.LevelDebug,
+		AddSource: true,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == "password" {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}
+
+	now := time.Unix(1700000000, 123)
+	handler := NewProtobufHandler(buffer, opts)
+
+	logger := slog.New(handler).With("service", "logit").WithGroup("request").With("id", 123)
+	logger.Debug("debug", "cost", time.Second, "ok", true, "password", "123456")
+	logger.WithGroup("user").Info("info", "uid", uint64(7), "score", 0.5, "at", now, "err", errors.New("failed"))
+	logger.WithGroup("empty").Warn("")
+
+	slog.New(handler).Error("error", slog.Group("group", "key", "value"), slog.Group("", "inline", -1), slog.Group("empty"))
+
+	records := readProtobufTestRecords(t, buffer)
+
+	want := []protobufTestRecord{
+		{level: -4, levelName: "DEBUG", message: "debug", source: "protobuf_test.go", attrs: map[string]any{
+			"service": "logit", "request.id": int64(123), "request.cost": time.Second, "request.ok": true,
+		}},
+		{level: 0, levelName: "INFO", message: "info", source: "protobuf_test.go", attrs: map[string]any{
+			"service": "logit", "request.id": int64(123), "request.user.uid": uint64(7), "request.user.score": 0.5,
+			"request.user.at": time.Unix(0, now.UnixNano()), "request.user.err": "failed",
+		}},
+		{level: 4, levelName: "WARN", message: "", source: "protobuf_test.go", attrs: map[string]any{
+			"service": "logit", "request.id": int64(123),
+		}},
+		{level: 8, levelName: "ERROR", message: "error", source: "protobuf_test.go", attrs: map[string]any{
+			"group.key": "value", "inline": int64(-1),
+		}},
+	}
+
+	if len(records) != len(want) {
+		t.Fatalf("len(records) %d != len(want) %d", len(records), len(want))
+	}
+
+	for i, record := range records {
+		if record.time <= 0 {
+			t.Fatalf("record.time %d is wrong", record.time)
+		}
+
+		record.time = 0
+		if !reflect.DeepEqual(record, want[i]) {
+			t.Fatalf("record %+v != want %+v", record, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestProtobufHandlerWithoutAttrs$
+func TestProtobufHandlerWithoutAttrs(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	handler := NewProtobufHandler(buffer, nil)
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("level debug is enabled")
+	}
+
+	slog.New(handler.WithGroup("group")).Info("msg", "any", struct{ A int }{A: 1})
+
+	records := readProtobufTestRecords(t, buffer)
+	if len(records) != 1 {
+		t.Fatalf("len(records) %d != 1", len(records))
+	}
+
+	want := map[string]any{"group.any": fmt.Sprintf("%+v", struct{ A int }{A: 1})}
+	if records[0].source != "" || !reflect.DeepEqual(records[0].attrs, want) {
+		t.Fatalf("records[0] %+v is wrong", records[0])
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"log/slog"
+	"runtime"
+)
+
+// resolveSource resolves the source of pc and passes it to replaceAttr as a *slog.Source like slog's handlers,
+// so the source can be trimmed or removed by replaceAttr.
+// It returns the source and true if the value is still a source, or the attr replaced and false if not.
+// The attr replaced having an empty key means the source should be removed.
+func resolveSource(pc uintptr, replaceAttr func(groups []string, attr slog.Attr) slog.Attr) (slog.Source, slog.Attr, bool) {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+
+	source := slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+	if replaceAttr == nil {
+		return source, slog.Attr{}, true
+	}
+
+	attr := replaceAttr(nil, slog.Any(slog.SourceKey, &source))
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Key == "" {
+		return source, attr, false
+	}
+
+	if replaced, ok := attr.Value.Any().(*slog.Source); ok {
+		return *replaced, attr, true
+	}
+
+	return source, attr, false
+}
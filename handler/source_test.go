@@ -0,0 +1,60 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestResolveSource$
+func TestResolveSource(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+
+	source, _, ok := resolveSource(pc, nil)
+	if !ok || !strings.HasSuffix(source.File, "handler/source_test.go") || source.Line <= 0 {
+		t.Fatalf("source %+v or ok %+v is wrong", source, ok)
+	}
+
+	trim := func(groups []string, attr slog.Attr) slog.Attr {
+		if source, ok := attr.Value.Any().(*slog.Source); ok {
+			source.File = "source_test.go"
+		}
+
+		return attr
+	}
+
+	if source, _, ok = resolveSource(pc, trim); !ok || source.File != "source_test.go" {
+		t.Fatalf("source %+v or ok %+v is wrong", source, ok)
+	}
+
+	remove := func(groups []string, attr slog.Attr) slog.Attr {
+		return slog.Attr{}
+	}
+
+	if _, attr, ok := resolveSource(pc, remove); ok || attr.Key != "" {
+		t.Fatalf("attr %+v or ok %+v is wrong", attr, ok)
+	}
+
+	replace := func(groups []string, attr slog.Attr) slog.Attr {
+		return slog.String("caller", "main")
+	}
+
+	if _, attr, ok := resolveSource(pc, replace); ok || attr.Key != "caller" || attr.Value.String() != "main" {
+		t.Fatalf("attr %+v or ok %+v is wrong", attr, ok)
+	}
+}
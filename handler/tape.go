@@ -21,7 +21,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"runtime"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -55,16 +55,58 @@ type tapeHandler struct {
 	w    io.Writer
 	opts slog.HandlerOptions
 
-	group  string
-	groups []string
-	attrs  []slog.Attr
+	// groupPrefix is the escaped groups joined with group connector, like "a.b.".
+	// It's computed once in WithGroup so handling records won't escape groups again.
+	groupPrefix []byte
+	groups      []string
+	attrs       []slog.Attr
+
+	// color is true if levels and keys should be colored.
+	// It's only set when colors are enabled and w is a terminal.
+	color bool
+
+	// escape is the mode of escaping strings, see EscapeMode.
+	escape EscapeMode
+
+	// internSize is the max count of strings interned, see WithTapeInterning.
+	// The table is created after applying all options, so it uses the final escape mode.
+	internSize int
+	intern     *internTable
 
 	lock *sync.Mutex
 }
 
-// NewTapeHandler creates a tape handler with w and opts.
+// TapeOption is an option for creating tape handlers.
+type TapeOption func(th *tapeHandler)
+
+// WithTapeColor colors levels and keys if w is a terminal.
+// Logs written to files won't be colored, so they won't contain escape codes.
+func WithTapeColor() TapeOption {
+	return func(th *tapeHandler) {
+		th.color = isTerminal(th.w)
+	}
+}
+
+// WithTapeEscape sets the mode of escaping strings, and EscapeMinimal is used by default.
+func WithTapeEscape(mode EscapeMode) TapeOption {
+	return func(th *tapeHandler) {
+		th.escape = mode
+	}
+}
+
+// WithTapeInterning caches escaped keys and string values up to maxEntries, so repeated strings won't be escaped again.
+// It's useful for long-lived loggers logging the same strings like status names and route templates repeatedly.
+// Only short strings are cached and new strings won't be cached after the table is full, so the memory is bounded.
+// Notice that it pays off with EscapeFull, while looking up the table costs more than escaping with EscapeMinimal and EscapeNone.
+func WithTapeInterning(maxEntries int) TapeOption {
+	return func(th *tapeHandler) {
+		th.internSize = maxEntries
+	}
+}
+
+// NewTapeHandler creates a tape handler with w, opts and tapeOpts.
 // This handler is more readable and faster than slog's handlers.
-func NewTapeHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+func NewTapeHandler(w io.Writer, opts *slog.HandlerOptions, tapeOpts ...TapeOption) slog.Handler {
 	if opts == nil {
 		opts = new(slog.HandlerOptions)
 	}
@@ -74,14 +116,29 @@ func NewTapeHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
 	}
 
 	handler := &tapeHandler{
-		w:    w,
-		opts: *opts,
-		lock: &sync.Mutex{},
+		w:      w,
+		opts:   *opts,
+		escape: EscapeMinimal,
+		lock:   &sync.Mutex{},
+	}
+
+	for _, opt := range tapeOpts {
+		opt(handler)
+	}
+
+	if handler.internSize > 0 {
+		handler.intern = newInternTable(handler.escape, handler.internSize)
 	}
 
 	return handler
 }
 
+// NewColorTapeHandler creates a tape handler with w and opts, which colors levels and keys.
+// Colors are only used if w is a terminal, so logs written to files won't contain escape codes.
+func NewColorTapeHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return NewTapeHandler(w, opts, WithTapeColor())
+}
+
 // WithAttrs returns a new handler with attrs.
 func (th *tapeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	if len(attrs) <= 0 {
@@ -100,13 +157,14 @@ func (th *tapeHandler) WithGroup(name string) slog.Handler {
 		return th
 	}
 
-	handler := *th
-	if handler.group != "" {
-		handler.group = handler.group + groupConnector
-	}
+	groupPrefix := make([]byte, 0, len(th.groupPrefix)+len(name)+len(groupConnector))
+	groupPrefix = append(groupPrefix, th.groupPrefix...)
+	groupPrefix = appendEscapedStringWith(groupPrefix, name, th.escape)
+	groupPrefix = append(groupPrefix, groupConnector...)
 
-	handler.group = handler.group + name
-	handler.groups = append(handler.groups, name)
+	handler := *th
+	handler.groupPrefix = groupPrefix
+	handler.groups = append(slices.Clip(th.groups), name)
 
 	return &handler
 }
@@ -132,22 +190,36 @@ func (th *tapeHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return level >= th.opts.Level.Level()
 }
 
+func (th *tapeHandler) appendEscapedString(bs []byte, value string) []byte {
+	if th.intern != nil {
+		return th.intern.appendString(bs, value)
+	}
+
+	return appendEscapedStringWith(bs, value, th.escape)
+}
+
 func (th *tapeHandler) appendKey(bs []byte, group string, key string) []byte {
 	if key == "" {
 		return bs
 	}
 
-	if th.group != "" {
-		bs = appendEscapedString(bs, th.group)
-		bs = append(bs, groupConnector...)
+	if th.color {
+		bs = append(bs, colorCyan...)
 	}
 
+	bs = append(bs, th.groupPrefix...)
+
 	if group != "" {
-		bs = appendEscapedString(bs, group)
+		bs = th.appendEscapedString(bs, group)
 		bs = append(bs, groupConnector...)
 	}
 
-	bs = appendEscapedString(bs, key)
+	bs = th.appendEscapedString(bs, key)
+
+	if th.color {
+		bs = append(bs, colorReset...)
+	}
+
 	bs = append(bs, keyValueConnector)
 
 	return bs
@@ -182,7 +254,7 @@ func (th *tapeHandler) appendFloat64(bs []byte, value float64) []byte {
 }
 
 func (th *tapeHandler) appendString(bs []byte, value string) []byte {
-	bs = appendEscapedString(bs, value)
+	bs = th.appendEscapedString(bs, value)
 	bs = append(bs, attrConnector...)
 
 	return bs
@@ -309,7 +381,9 @@ func (th *tapeHandler) appendAttr(bs []byte, group string, attr slog.Attr) []byt
 	}
 
 	// Resolve the Attr's value before doing anything else.
+	// The kind may be changed by replaceAttr so we should get it again.
 	attr.Value = attr.Value.Resolve()
+	kind = attr.Value.Kind()
 
 	if attr.Equal(emptyAttr) {
 		return bs
@@ -322,27 +396,48 @@ func (th *tapeHandler) appendAttr(bs []byte, group string, attr slog.Attr) []byt
 	}
 
 	bs = th.appendKey(bs, group, attr.Key)
+	bs = th.appendValue(bs, attr.Value)
 
-	switch kind {
+	return bs
+}
+
+func (th *tapeHandler) appendValue(bs []byte, value slog.Value) []byte {
+	switch value.Kind() {
 	case slog.KindBool:
-		bs = th.appendBool(bs, attr.Value.Bool())
+		return th.appendBool(bs, value.Bool())
 	case slog.KindInt64:
-		bs = th.appendInt64(bs, attr.Value.Int64())
+		return th.appendInt64(bs, value.Int64())
 	case slog.KindUint64:
-		bs = th.appendUint64(bs, attr.Value.Uint64())
+		return th.appendUint64(bs, value.Uint64())
 	case slog.KindFloat64:
-		bs = th.appendFloat64(bs, attr.Value.Float64())
+		return th.appendFloat64(bs, value.Float64())
 	case slog.KindDuration:
-		bs = th.appendDuration(bs, attr.Value.Duration())
+		return th.appendDuration(bs, value.Duration())
 	case slog.KindTime:
-		bs = th.appendTime(bs, attr.Value.Time())
+		return th.appendTime(bs, value.Time())
 	case slog.KindAny:
-		bs = th.appendAny(bs, attr.Value.Any())
+		return th.appendAny(bs, value.Any())
 	default:
-		bs = th.appendString(bs, attr.Value.String())
+		return th.appendString(bs, value.String())
+	}
+}
+
+// appendRecordTime appends the time of record.
+// The time will be passed to ReplaceAttr like slog's handlers, so it can be formatted in other ways.
+func (th *tapeHandler) appendRecordTime(bs []byte, t time.Time) []byte {
+	replaceAttr := th.opts.ReplaceAttr
+	if replaceAttr == nil || t.IsZero() {
+		return th.appendTime(bs, t)
 	}
 
-	return bs
+	attr := replaceAttr(nil, slog.Time(slog.TimeKey, t))
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Key == "" {
+		return bs
+	}
+
+	return th.appendValue(bs, attr.Value)
 }
 
 func (th *tapeHandler) appendAttrs(bs []byte, group string, attrs []slog.Attr) []byte {
@@ -353,19 +448,43 @@ func (th *tapeHandler) appendAttrs(bs []byte, group string, attrs []slog.Attr) [
 	return bs
 }
 
+func (th *tapeHandler) appendLevel(bs []byte, level slog.Level) []byte {
+	if !th.color {
+		return th.appendString(bs, levelName(level))
+	}
+
+	bs = append(bs, levelColor(level)...)
+	bs = appendEscapedString(bs, levelName(level))
+	bs = append(bs, colorReset...)
+	bs = append(bs, attrConnector...)
+
+	return bs
+}
+
 func (th *tapeHandler) appendSource(bs []byte, pc uintptr) []byte {
 	if !th.opts.AddSource || pc == 0 {
 		return bs
 	}
 
-	frames := runtime.CallersFrames([]uintptr{pc})
-	frame, _ := frames.Next()
+	source, attr, ok := resolveSource(pc, th.opts.ReplaceAttr)
+	if !ok {
+		if attr.Key == "" {
+			return bs
+		}
+
+		// The source is a built-in key like time and level, so it isn't in groups added by WithGroup.
+		bs = appendEscapedStringWith(bs, attr.Key, th.escape)
+		bs = append(bs, keyValueConnector)
+		bs = th.appendValue(bs, attr.Value)
+
+		return bs
+	}
 
 	bs = append(bs, slog.SourceKey...)
 	bs = append(bs, keyValueConnector)
-	bs = appendEscapedString(bs, frame.File)
+	bs = appendEscapedStringWith(bs, source.File, th.escape)
 	bs = append(bs, sourceConnector)
-	bs = strconv.AppendInt(bs, int64(frame.Line), 10)
+	bs = strconv.AppendInt(bs, int64(source.Line), 10)
 	bs = append(bs, attrConnector...)
 
 	return bs
@@ -383,8 +502,8 @@ func (th *tapeHandler) Handle(ctx context.Context, record slog.Record) error {
 	}()
 
 	// Handling record.
-	bs = th.appendTime(bs, record.Time)
-	bs = th.appendString(bs, record.Level.String())
+	bs = th.appendRecordTime(bs, record.Time)
+	bs = th.appendLevel(bs, record.Level)
 	bs = th.appendString(bs, record.Message)
 	bs = th.appendSource(bs, record.PC)
 	bs = th.appendAttrs(bs, "", th.attrs)
@@ -21,6 +21,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"testing/slogtest"
@@ -197,3 +199,187 @@ func TestTapeHandler(t *testing.T) {
 		t.Log(err)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerWithGroup$
+func TestTapeHandlerWithGroup(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, nil).WithGroup("a").WithGroup("b\n")
+
+	handlerC := handler.WithGroup("c")
+	handlerD := handler.WithGroup("d")
+
+	groupPrefix := handler.(*tapeHandler).groupPrefix
+	if string(groupPrefix) != `a.b\n.` {
+		t.Fatalf("groupPrefix %s is wrong", groupPrefix)
+	}
+
+	slog.New(handlerC).Info("msg", "k", "v")
+	slog.New(handlerD).Info("msg", "k", "v")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), string(lineBreak))
+	if len(logs) != 2 {
+		t.Fatalf("len(logs) %d != 2", len(logs))
+	}
+
+	if !strings.HasSuffix(logs[0], `a.b\n.c.k=v`) {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	if !strings.HasSuffix(logs[1], `a.b\n.d.k=v`) {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+
+	if groups := handlerC.(*tapeHandler).groups; !slices.Equal(groups, []string{"a", "b\n", "c"}) {
+		t.Fatalf("groups %+v is wrong", groups)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerReplaceAttrKind$
+func TestTapeHandlerReplaceAttrKind(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == "id" {
+			attr.Value = slog.StringValue("replaced")
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, &slog.HandlerOptions{ReplaceAttr: replaceAttr})
+
+	slog.New(handler).Info("msg", "id", 123)
+
+	if log := strings.TrimSpace(buffer.String()); !strings.HasSuffix(log, "id=replaced") {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerReplaceTime$
+func TestTapeHandlerReplaceTime(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, &slog.HandlerOptions{ReplaceAttr: replaceAttr})
+
+	slog.New(handler).Info("msg", "k", "v")
+
+	if log := strings.TrimSpace(buffer.String()); log != "INFO ¦ msg ¦ k=v" {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestColorTapeHandler$
+func TestColorTapeHandler(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts := &slog.HandlerOptions{ReplaceAttr: replaceAttr}
+
+	// Buffer isn't a terminal so logs shouldn't be colored.
+	slog.New(NewColorTapeHandler(buffer, opts)).WithGroup("g").Info("msg", "k", "v")
+
+	if log := strings.TrimSpace(buffer.String()); log != "INFO ¦ msg ¦ g.k=v" {
+		t.Fatalf("log %s is wrong", log)
+	}
+
+	buffer.Reset()
+
+	handler := NewColorTapeHandler(buffer, opts).(*tapeHandler)
+	handler.color = true
+
+	slog.New(handler).WithGroup("g").Warn("msg", "k", "v")
+
+	want := colorYellow + "WARN" + colorReset + " ¦ msg ¦ " + colorCyan + "g.k" + colorReset + "=v"
+	if log := strings.TrimSpace(buffer.String()); log != want {
+		t.Fatalf("log %q != want %q", log, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerEscape$
+func TestTapeHandlerEscape(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}
+
+	opts := &slog.HandlerOptions{ReplaceAttr: replaceAttr}
+
+	wants := map[EscapeMode]string{
+		EscapeFull:    `INFO ¦ "login msg" ¦ user=abc ¦ sql="select \"a\"\n"`,
+		EscapeMinimal: `INFO ¦ login msg ¦ user=abc ¦ sql=select "a"\n`,
+		EscapeNone:    "INFO ¦ login msg ¦ user=abc ¦ sql=select \"a\"\n",
+	}
+
+	for mode, want := range wants {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		handler := NewTapeHandler(buffer, opts, WithTapeEscape(mode))
+		slog.New(handler).Info("login msg", "user", "abc", "sql", "select \"a\"\n")
+
+		if log := strings.TrimSuffix(buffer.String(), "\n"); log != want {
+			t.Fatalf("mode %s: log %q != want %q", mode, log, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerReplaceSource$
+func TestTapeHandlerReplaceSource(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		if source, ok := attr.Value.Any().(*slog.Source); ok {
+			source.File = "tape_test.go"
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, &slog.HandlerOptions{AddSource: true, ReplaceAttr: replaceAttr})
+
+	slog.New(handler).Info("msg")
+
+	if log := strings.TrimSpace(buffer.String()); !strings.HasPrefix(log, "INFO ¦ msg ¦ source=tape_test.go:") {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerReplaceSourceWithGroup$
+func TestTapeHandlerReplaceSourceWithGroup(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		if source, ok := attr.Value.Any().(*slog.Source); ok {
+			return slog.String(slog.SourceKey, filepath.Base(source.File))
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, &slog.HandlerOptions{AddSource: true, ReplaceAttr: replaceAttr})
+
+	slog.New(handler).WithGroup("group").Info("msg", "key", "value")
+
+	if log := strings.TrimSpace(buffer.String()); log != "INFO ¦ msg ¦ source=tape_test.go ¦ group.key=value" {
+		t.Fatalf("log %s is wrong", log)
+	}
+}
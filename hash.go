@@ -0,0 +1,67 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"log/slog"
+	"sync"
+)
+
+type attrHasher struct {
+	keys map[string]struct{}
+	pool sync.Pool
+}
+
+func newAttrHasher(secret []byte, keys []string) *attrHasher {
+	secret = append([]byte(nil), secret...)
+
+	hasher := &attrHasher{
+		keys: make(map[string]struct{}, len(keys)),
+	}
+
+	hasher.pool.New = func() any {
+		return hmac.New(sha256.New, secret)
+	}
+
+	for _, key := range keys {
+		hasher.keys[key] = struct{}{}
+	}
+
+	return hasher
+}
+
+func (ah *attrHasher) hash(value string) string {
+	mac := ah.pool.Get().(hash.Hash)
+	defer ah.pool.Put(mac)
+
+	mac.Reset()
+	mac.Write([]byte(value))
+
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(mac.Sum(sum[:0]))
+}
+
+func (ah *attrHasher) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if _, ok := ah.keys[attr.Key]; !ok {
+		return attr
+	}
+
+	attr.Value = slog.StringValue(ah.hash(attr.Value.Resolve().String()))
+	return attr
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHashedAttrs$
+func TestWithHashedAttrs(t *testing.T) {
+	secret := []byte("secret")
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("123"))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithHashedAttrs(secret, "user_id", "email"))
+
+	logger.Info("msg", "user_id", 123, "other", "plain")
+	logger.Info("msg", "user_id", "123")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if !strings.HasSuffix(logs[0], "user_id="+want+" ¦ other=plain") {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	if !strings.HasSuffix(logs[1], "user_id="+want) {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAttrHasherReplaceAttr$
+func TestAttrHasherReplaceAttr(t *testing.T) {
+	hasher := newAttrHasher([]byte("secret"), []string{"k"})
+	otherHasher := newAttrHasher([]byte("other"), []string{"k"})
+
+	attr := hasher.replaceAttr(nil, slog.String("other", "v"))
+	if attr.Value.String() != "v" {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	attr = hasher.replaceAttr(nil, slog.String("k", "v"))
+	if attr.Value.String() != hasher.hash("v") {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	if hasher.hash("v") == otherHasher.hash("v") {
+		t.Fatal("hashes with different secrets should be different")
+	}
+
+	if hasher.hash("v") == hasher.hash("w") {
+		t.Fatal("hashes of different values should be different")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// ErrSkipRecord is returned by Hook.Before to skip a record without reporting an error.
+var ErrSkipRecord = errors.New("logit: skip record")
+
+// Hook is called around handling records, so you can mutate records, count them or trigger side effects without writing a handler.
+// Before is called before handling a record and can mutate the record.
+// The record won't be handled if Before returns an error, and the error will be returned unless it's ErrSkipRecord.
+// After is called after handling a record with the record handled and the error returned, including ErrSkipRecord.
+// Only hooks whose Before have been called will be called After.
+// Hooks' Before are called in order and their After are called in reverse order, like middlewares.
+// See WithHooks.
+type Hook interface {
+	Before(ctx context.Context, record *slog.Record) error
+	After(ctx context.Context, record slog.Record, err error)
+}
+
+// hookHandler calls hooks around handling records.
+type hookHandler struct {
+	slog.Handler
+
+	hooks []Hook
+}
+
+func newHookHandler(handler slog.Handler, hooks []Hook) slog.Handler {
+	return hookHandler{Handler: handler, hooks: hooks}
+}
+
+func (hh hookHandler) Handle(ctx context.Context, record slog.Record) error {
+	// The record may be shared with the caller, so clone it before hooks mutating it.
+	record = record.Clone()
+
+	var err error
+	called := 0
+
+	for _, hook := range hh.hooks {
+		called++
+
+		if err = hook.Before(ctx, &record); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = hh.Handler.Handle(ctx, record)
+	}
+
+	for i := called - 1; i >= 0; i-- {
+		hh.hooks[i].After(ctx, record, err)
+	}
+
+	if errors.Is(err, ErrSkipRecord) {
+		return nil
+	}
+
+	return err
+}
+
+func (hh hookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newHookHandler(hh.Handler.WithAttrs(attrs), hh.hooks)
+}
+
+func (hh hookHandler) WithGroup(name string) slog.Handler {
+	return newHookHandler(hh.Handler.WithGroup(name), hh.hooks)
+}
+
+// AttrHook rewrites attr in groups and returns the rewritten one, or returns false to drop it.
+// Groups are the names of groups which attr belongs to, and the attr will be passed with its resolved value.
+// Hooks are called in the order they are registered, and a dropped attr won't be passed to hooks after.
+// See WithAttrHooks.
+type AttrHook func(groups []string, attr slog.Attr) (slog.Attr, bool)
+
+// replaceAttr adapts the hook to a replaceAttr, which drops an attr by returning an attr with empty key.
+func (ah AttrHook) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	attr, keep := ah(groups, attr)
+	if !keep {
+		return slog.Attr{}
+	}
+
+	return attr
+}
+
+// DropAttrs returns a hook dropping attrs having one of keys regardless of groups.
+func DropAttrs(keys ...string) AttrHook {
+	dropped := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		dropped[key] = struct{}{}
+	}
+
+	return func(groups []string, attr slog.Attr) (slog.Attr, bool) {
+		_, ok := dropped[attr.Key]
+		return attr, !ok
+	}
+}
+
+// RenameAttrs returns a hook renaming keys of attrs to names, whose key is the old key.
+// Keys are matched regardless of groups.
+func RenameAttrs(names map[string]string) AttrHook {
+	renamed := make(map[string]string, len(names))
+	for key, name := range names {
+		renamed[key] = name
+	}
+
+	return func(groups []string, attr slog.Attr) (slog.Attr, bool) {
+		if name, ok := renamed[attr.Key]; ok {
+			attr.Key = name
+		}
+
+		return attr, true
+	}
+}
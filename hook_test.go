@@ -0,0 +1,193 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testHook struct {
+	name    string
+	calls   *[]string
+	skipMsg string
+}
+
+func (th *testHook) Before(ctx context.Context, record *slog.Record) error {
+	*th.calls = append(*th.calls, th.name+".before")
+
+	if record.Message == th.skipMsg {
+		return ErrSkipRecord
+	}
+
+	record.AddAttrs(slog.String(th.name, "ok"))
+	return nil
+}
+
+func (th *testHook) After(ctx context.Context, record slog.Record, err error) {
+	*th.calls = append(*th.calls, th.name+".after:"+record.Message+":"+fmt.Sprint(err))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAttrHook$
+func TestAttrHook(t *testing.T) {
+	hook := AttrHook(func(groups []string, attr slog.Attr) (slog.Attr, bool) {
+		return attr, attr.Key != "drop"
+	})
+
+	if attr := hook.replaceAttr(nil, slog.String("drop", "value")); attr.Key != "" {
+		t.Fatalf("attr %+v should be dropped", attr)
+	}
+
+	if attr := hook.replaceAttr(nil, slog.String("keep", "value")); attr.String() != "keep=value" {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDropAttrs$
+func TestDropAttrs(t *testing.T) {
+	hook := DropAttrs("debug_info", "trace")
+
+	if _, keep := hook([]string{"req"}, slog.String("trace", "value")); keep {
+		t.Fatal("attr trace should be dropped")
+	}
+
+	if _, keep := hook(nil, slog.String("user", "fish")); !keep {
+		t.Fatal("attr user should be kept")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRenameAttrs$
+func TestRenameAttrs(t *testing.T) {
+	hook := RenameAttrs(map[string]string{"uid": "user_id"})
+
+	attr, keep := hook(nil, slog.Int("uid", 123))
+	if !keep || attr.String() != "user_id=123" {
+		t.Fatalf("attr %+v or keep %+v is wrong", attr, keep)
+	}
+
+	attr, keep = hook(nil, slog.Int("id", 123))
+	if !keep || attr.String() != "id=123" {
+		t.Fatalf("attr %+v or keep %+v is wrong", attr, keep)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAttrHooksChain$
+func TestAttrHooksChain(t *testing.T) {
+	var calls []string
+	recordCall := AttrHook(func(groups []string, attr slog.Attr) (slog.Attr, bool) {
+		calls = append(calls, attr.Key)
+		return attr, true
+	})
+
+	truncate := AttrHook(func(groups []string, attr slog.Attr) (slog.Attr, bool) {
+		if attr.Value.Kind() == slog.KindString && len(attr.Value.String()) > 4 {
+			attr.Value = slog.StringValue(attr.Value.String()[:4] + "...")
+		}
+
+		return attr, true
+	})
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(
+		WithWriter(buffer),
+		WithAttrHooks(DropAttrs("password"), recordCall),
+		WithAttrHooks(RenameAttrs(map[string]string{"uid": "user_id"}), truncate, nil),
+	)
+
+	logger.Info("msg", "password", "123456", "uid", 123, "comment", "a very long comment")
+
+	got := buffer.String()
+	if !strings.HasSuffix(got, "¦ user_id=123 ¦ comment=a ve...\n") || strings.Contains(got, "password") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if strings.Contains(strings.Join(calls, ","), "password") {
+		t.Fatalf("calls %+v should not contain dropped attr", calls)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHookHandler$
+func TestHookHandler(t *testing.T) {
+	var calls []string
+	first := &testHook{name: "first", calls: &calls}
+	second := &testHook{name: "second", calls: &calls, skipMsg: "skip"}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithHooks(first, nil), WithHooks(second), WithMasking("password"))
+	logger.Info("msg", "password", "123456")
+
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ msg ¦ password=*** ¦ first=ok ¦ second=ok\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	want := "first.before,second.before,second.after:msg:<nil>,first.after:msg:<nil>"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	buffer.Reset()
+	calls = calls[:0]
+	logger.Info("skip")
+
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer %s should be empty", buffer.String())
+	}
+
+	want = "first.before,second.before,second.after:skip:" + ErrSkipRecord.Error() + ",first.after:skip:" + ErrSkipRecord.Error()
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHookHandlerError$
+func TestHookHandlerError(t *testing.T) {
+	errHook := errors.New("hook failed")
+
+	var calls []string
+	hook := &testHook{name: "hook", calls: &calls}
+	handler := newHookHandler(slog.NewTextHandler(new(bytes.Buffer), nil), []Hook{hook, failedHook{err: errHook}})
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	if err := handler.Handle(context.Background(), record); err != errHook {
+		t.Fatalf("err %+v != errHook %+v", err, errHook)
+	}
+
+	want := "hook.before,hook.after:msg:" + errHook.Error()
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	if record.NumAttrs() != 0 {
+		t.Fatalf("record.NumAttrs() %d should be 0", record.NumAttrs())
+	}
+}
+
+type failedHook struct {
+	err error
+}
+
+func (fh failedHook) Before(ctx context.Context, record *slog.Record) error {
+	return fh.err
+}
+
+func (fh failedHook) After(ctx context.Context, record slog.Record, err error) {}
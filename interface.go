@@ -0,0 +1,115 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// Interface is a minimal interface of logging for libraries.
+// Libraries can accept it instead of *Logger, so users can pass a logger of logit, slog or anything else.
+// Use Logger.Interface and FromSlog to adapt loggers to it.
+// *Logger can't implement it directly, because Logger.With returns *Logger instead of Interface.
+type Interface interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	With(args ...any) Interface
+}
+
+// loggerInterface adapts *Logger to Interface.
+type loggerInterface struct {
+	logger *Logger
+}
+
+// Interface returns an Interface adapted from the logger.
+func (l *Logger) Interface() Interface {
+	return loggerInterface{logger: l}
+}
+
+func (li loggerInterface) Debug(msg string, args ...any) {
+	li.logger.log(context.Background(), slog.LevelDebug, 0, msg, args...)
+}
+
+func (li loggerInterface) Info(msg string, args ...any) {
+	li.logger.log(context.Background(), slog.LevelInfo, 0, msg, args...)
+}
+
+func (li loggerInterface) Warn(msg string, args ...any) {
+	li.logger.log(context.Background(), slog.LevelWarn, 0, msg, args...)
+}
+
+func (li loggerInterface) Error(msg string, args ...any) {
+	li.logger.log(context.Background(), slog.LevelError, 0, msg, args...)
+}
+
+func (li loggerInterface) With(args ...any) Interface {
+	return loggerInterface{logger: li.logger.With(args...)}
+}
+
+// slogInterface adapts *slog.Logger to Interface.
+type slogInterface struct {
+	logger *slog.Logger
+}
+
+// FromSlog returns an Interface adapted from a slog logger.
+func FromSlog(logger *slog.Logger) Interface {
+	return slogInterface{logger: logger}
+}
+
+func (si slogInterface) log(level slog.Level, msg string, args []any) {
+	ctx := context.Background()
+
+	handler := si.logger.Handler()
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the method of Interface.
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(defaults.CurrentTime(), level, msg, pcs[0])
+	record.Add(args...)
+
+	if err := handler.Handle(ctx, record); err != nil {
+		defaults.HandleError("slogInterface.handler.Handle", err)
+	}
+}
+
+func (si slogInterface) Debug(msg string, args ...any) {
+	si.log(slog.LevelDebug, msg, args)
+}
+
+func (si slogInterface) Info(msg string, args ...any) {
+	si.log(slog.LevelInfo, msg, args)
+}
+
+func (si slogInterface) Warn(msg string, args ...any) {
+	si.log(slog.LevelWarn, msg, args)
+}
+
+func (si slogInterface) Error(msg string, args ...any) {
+	si.log(slog.LevelError, msg, args)
+}
+
+func (si slogInterface) With(args ...any) Interface {
+	return slogInterface{logger: si.logger.With(args...)}
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testInterface(t *testing.T, logger Interface, buffer *bytes.Buffer) {
+	logger.Debug("debug", "key", 1)
+	logger.Info("info", "key", 2)
+	logger.With("with", true).Warn("warn", "key", 3)
+	logger.Error("error", "key", 4)
+
+	want := []string{"DEBUG debug 1 false", "INFO info 2 false", "WARN warn 3 true", "ERROR error 4 false"}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("len(lines) %d != len(want) %d", len(lines), len(want))
+	}
+
+	for i, line := range lines {
+		var entry struct {
+			Level  string `json:"level"`
+			Msg    string `json:"msg"`
+			Key    int    `json:"key"`
+			With   bool   `json:"with"`
+			Source struct {
+				File string `json:"file"`
+			} `json:"source"`
+		}
+
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if filepath.Base(entry.Source.File) != "interface_test.go" {
+			t.Fatalf("entry.Source.File %s is wrong", entry.Source.File)
+		}
+
+		got := entry.Level + " " + entry.Msg + " " + strconv.Itoa(entry.Key) + " " + strconv.FormatBool(entry.With)
+		if got != want[i] {
+			t.Fatalf("got %s != want %s", got, want[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerInterface$
+func TestLoggerInterface(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	testInterface(t, logger.Interface(), buffer)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFromSlog$
+func TestFromSlog(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true}))
+
+	testInterface(t, FromSlog(logger), buffer)
+
+	buffer.Reset()
+	FromSlog(slog.New(slog.NewJSONHandler(buffer, nil))).Debug("debug")
+
+	if buffer.Len() > 0 {
+		t.Fatalf("buffer %s isn't empty", buffer.String())
+	}
+}
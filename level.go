@@ -0,0 +1,144 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+const (
+	// LevelTrace is the level lower than debug.
+	// It's useful for logging very high-volume details which you don't want to mix with debug.
+	LevelTrace = slog.LevelDebug - 4
+
+	// LevelPanic is the level higher than error and lower than fatal.
+	// Logs in panic level will be synced before panicking.
+	LevelPanic = slog.LevelError + 4
+
+	// LevelFatal is the level higher than panic.
+	// Logs in fatal level will be synced before the process exits.
+	LevelFatal = slog.LevelError + 8
+)
+
+func init() {
+	handler.RegisterLevelName(LevelTrace, "TRACE")
+	handler.RegisterLevelName(LevelPanic, "PANIC")
+	handler.RegisterLevelName(LevelFatal, "FATAL")
+}
+
+// RegisterLevel registers a level with name, so handlers will output the name instead of something like "INFO+2".
+// The name can also be parsed by ParseLevel, which means you can use it in configs.
+// Registering a name which has been used by another level will return an error.
+func RegisterLevel(level slog.Level, name string) error {
+	if name == "" {
+		return fmt.Errorf("logit: level %d registered with an empty name", level)
+	}
+
+	parsed, err := ParseLevel(name)
+	if err == nil && parsed != level {
+		return fmt.Errorf("logit: level name %s has been registered", name)
+	}
+
+	handler.RegisterLevelName(level, name)
+	return nil
+}
+
+// RegisterLevelNames registers names of levels, so levels will be output as custom names like "WARNING" or lowercase names.
+// Names are registered in order of levels and all names can be parsed by ParseLevel, see RegisterLevel.
+// Notice that names are registered globally, so all loggers will output the same names.
+func RegisterLevelNames(names map[slog.Level]string) error {
+	levels := make([]slog.Level, 0, len(names))
+	for level := range names {
+		levels = append(levels, level)
+	}
+
+	slices.Sort(levels)
+
+	for _, level := range levels {
+		if err := RegisterLevel(level, names[level]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewLevelVar returns a new level variable whose level is initial.
+// Loggers created with the same level variable share the level, so changing it once adjusts all of them.
+// See WithLevelVar and RegisterLeveler.
+func NewLevelVar(initial slog.Level) *slog.LevelVar {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(initial)
+
+	return levelVar
+}
+
+var (
+	// levelers stores all levelers registered by names.
+	levelers     = make(map[string]slog.Leveler, 4)
+	levelersLock sync.RWMutex
+)
+
+// RegisterLeveler registers a leveler like slog.LevelVar with name, so loggers created from configs can share it by name.
+// The name is case-insensitive and it can't be a level name parsed by ParseLevel, so levels and levelers won't be mixed up.
+// The leveler registered later will replace the former one having the same name.
+// See LookupLeveler and WithLeveler.
+func RegisterLeveler(name string, leveler slog.Leveler) error {
+	if name == "" || leveler == nil {
+		return fmt.Errorf("logit: leveler %v registered with an empty name or nil", leveler)
+	}
+
+	if _, err := ParseLevel(name); err == nil {
+		return fmt.Errorf("logit: leveler name %s is a level name", name)
+	}
+
+	levelersLock.Lock()
+	defer levelersLock.Unlock()
+
+	levelers[strings.ToLower(name)] = leveler
+	return nil
+}
+
+// LookupLeveler returns the leveler registered with name and reports whether it's found.
+// The name is case-insensitive, see RegisterLeveler.
+func LookupLeveler(name string) (slog.Leveler, bool) {
+	levelersLock.RLock()
+	defer levelersLock.RUnlock()
+
+	leveler, ok := levelers[strings.ToLower(name)]
+	return leveler, ok
+}
+
+// ParseLevel parses a level from name and returns an error if failed.
+// The name is case-insensitive and can be a registered name or a name like "info" and "INFO+2".
+// See RegisterLevel and slog.Level.UnmarshalText.
+func ParseLevel(name string) (slog.Level, error) {
+	if level, ok := handler.LookupLevel(name); ok {
+		return level, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("logit: level %s unknown", name)
+	}
+
+	return level, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelNames$
+func TestLevelNames(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	logger.log(context.Background(), LevelFatal, 0, "msg")
+
+	if !strings.Contains(buffer.String(), "FATAL") {
+		t.Fatalf("buffer %s doesn't contain FATAL", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLevel$
+func TestRegisterLevel(t *testing.T) {
+	levelNotice := slog.LevelInfo + 2
+
+	if err := RegisterLevel(levelNotice, ""); err == nil {
+		t.Fatal("registering an empty name should be failed")
+	}
+
+	if err := RegisterLevel(levelNotice, "info"); err == nil {
+		t.Fatal("registering a name used by another level should be failed")
+	}
+
+	if err := RegisterLevel(levelNotice, "NOTICE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterLevel(levelNotice, "notice"); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+	logger.log(context.Background(), levelNotice, 0, "msg")
+
+	if !strings.Contains(buffer.String(), `"level":"notice"`) {
+		t.Fatalf("buffer %s doesn't contain notice", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLevelNames$
+func TestRegisterLevelNames(t *testing.T) {
+	defer RegisterLevelNames(map[slog.Level]string{slog.LevelInfo: "INFO", slog.LevelWarn: "WARN"})
+
+	names := map[slog.Level]string{slog.LevelInfo: "信息", slog.LevelWarn: "WARNING"}
+	if err := RegisterLevelNames(names); err != nil {
+		t.Fatal(err)
+	}
+
+	handlers := []Option{WithTapeHandler(), WithTextHandler(), WithJsonHandler()}
+	wants := []string{"¦ WARNING ¦ msg", "level=WARNING msg=msg", `"level":"WARNING","msg":"msg"`}
+
+	for i, withHandler := range handlers {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		NewLogger(withHandler, WithWriter(buffer)).Warn("msg")
+
+		if got := buffer.String(); !strings.Contains(got, wants[i]) {
+			t.Fatalf("got %s doesn't contain %s", got, wants[i])
+		}
+	}
+
+	for name, want := range map[string]slog.Level{"warning": slog.LevelWarn, "warn": slog.LevelWarn, "信息": slog.LevelInfo} {
+		level, err := ParseLevel(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if level != want {
+			t.Fatalf("name %s: level %s != want %s", name, level, want)
+		}
+	}
+
+	if err := RegisterLevelNames(map[slog.Level]string{slog.LevelError: "warning"}); err == nil {
+		t.Fatal("registering a name used by another level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseLevel$
+func TestParseLevel(t *testing.T) {
+	testCases := map[string]slog.Level{
+		"trace":  LevelTrace,
+		"DEBUG":  slog.LevelDebug,
+		"info":   slog.LevelInfo,
+		"Warn":   slog.LevelWarn,
+		"error":  slog.LevelError,
+		"INFO+2": slog.LevelInfo + 2,
+		"panic":  LevelPanic,
+		"fatal":  LevelFatal,
+	}
+
+	for name, want := range testCases {
+		level, err := ParseLevel(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if level != want {
+			t.Fatalf("name %s: level %s != want %s", name, level, want)
+		}
+	}
+
+	if _, err := ParseLevel("unknown"); err == nil {
+		t.Fatal("parsing unknown level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLeveler$
+func TestRegisterLeveler(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slog.LevelWarn)
+
+	if err := RegisterLeveler("TestRegisterLeveler", levelVar); err != nil {
+		t.Fatal(err)
+	}
+
+	leveler, ok := LookupLeveler("testregisterleveler")
+	if !ok || leveler != levelVar {
+		t.Fatalf("leveler %v != levelVar %v", leveler, levelVar)
+	}
+
+	if _, ok = LookupLeveler("unknown"); ok {
+		t.Fatal("looking up unknown leveler should be failed")
+	}
+
+	if err := RegisterLeveler("info", levelVar); err == nil {
+		t.Fatal("registering a level name should be failed")
+	}
+
+	if err := RegisterLeveler("", levelVar); err == nil {
+		t.Fatal("registering an empty name should be failed")
+	}
+
+	if err := RegisterLeveler("TestRegisterLeveler", nil); err == nil {
+		t.Fatal("registering a nil leveler should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewLevelVar$
+func TestNewLevelVar(t *testing.T) {
+	levelVar := NewLevelVar(slog.LevelWarn)
+	if level := levelVar.Level(); level != slog.LevelWarn {
+		t.Fatalf("level %s != slog.LevelWarn", level)
+	}
+
+	levelVar.Set(LevelTrace)
+	if level := levelVar.Level(); level != LevelTrace {
+		t.Fatalf("level %s != LevelTrace", level)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// limitSummaryMsg is the message of the summary record logged after records dropped by rate limit.
+	limitSummaryMsg = "logit: records dropped by rate limit"
+
+	// limitSummaryInterval is the min interval of logging summary records.
+	limitSummaryInterval = 10 * time.Second
+)
+
+// rateLimiter limits the rate of records with a token bucket, and logs a summary record of the dropped count periodically.
+type rateLimiter struct {
+	// handler is the handler logging summary records, which has no attrs and groups added by loggers.
+	handler slog.Handler
+
+	limit        float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	dropped      uint64
+	summarizedAt time.Time
+	lock         sync.Mutex
+}
+
+func newRateLimiter(handler slog.Handler, limit int, burst int) *rateLimiter {
+	now := defaults.CurrentTime()
+	burst = max(burst, 1)
+
+	limiter := &rateLimiter{
+		handler:      handler,
+		limit:        float64(limit),
+		burst:        float64(burst),
+		tokens:       float64(burst),
+		last:         now,
+		summarizedAt: now,
+	}
+
+	return limiter
+}
+
+// allow reports whether a record is allowed and the dropped count should be summarized.
+func (rl *rateLimiter) allow() (bool, uint64) {
+	now := defaults.CurrentTime()
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	if elapsed := now.Sub(rl.last); elapsed > 0 {
+		rl.tokens = min(rl.burst, rl.tokens+elapsed.Seconds()*rl.limit)
+		rl.last = now
+	}
+
+	allowed := rl.tokens >= 1
+	if allowed {
+		rl.tokens--
+	} else {
+		rl.dropped++
+	}
+
+	var summary uint64
+	if rl.dropped > 0 && now.Sub(rl.summarizedAt) >= limitSummaryInterval {
+		summary = rl.dropped
+		rl.dropped = 0
+		rl.summarizedAt = now
+	}
+
+	return allowed, summary
+}
+
+func (rl *rateLimiter) summarize(ctx context.Context, dropped uint64) error {
+	record := slog.NewRecord(defaults.CurrentTime(), slog.LevelWarn, limitSummaryMsg, 0)
+	record.AddAttrs(slog.Uint64(keyDropped, dropped))
+
+	return rl.handler.Handle(ctx, record)
+}
+
+// Close logs a summary record if some records were dropped after the last summary.
+func (rl *rateLimiter) Close() error {
+	rl.lock.Lock()
+	dropped := rl.dropped
+	rl.dropped = 0
+	rl.lock.Unlock()
+
+	if dropped == 0 {
+		return nil
+	}
+
+	return rl.summarize(context.Background(), dropped)
+}
+
+// limitHandler drops records exceeding the rate limit.
+type limitHandler struct {
+	slog.Handler
+
+	limiter *rateLimiter
+}
+
+func newLimitHandler(handler slog.Handler, limiter *rateLimiter) slog.Handler {
+	return limitHandler{Handler: handler, limiter: limiter}
+}
+
+func (lh limitHandler) Handle(ctx context.Context, record slog.Record) error {
+	allowed, summary := lh.limiter.allow()
+	if summary > 0 {
+		if err := lh.limiter.summarize(ctx, summary); err != nil {
+			defaults.HandleError("rateLimiter.summarize", err)
+		}
+	}
+
+	if !allowed {
+		return nil
+	}
+
+	return lh.Handler.Handle(ctx, record)
+}
+
+func (lh limitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newLimitHandler(lh.Handler.WithAttrs(attrs), lh.limiter)
+}
+
+func (lh limitHandler) WithGroup(name string) slog.Handler {
+	return newLimitHandler(lh.Handler.WithGroup(name), lh.limiter)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRateLimiter$
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	limiter := newRateLimiter(slog.NewTextHandler(new(bytes.Buffer), nil), 10, 3)
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := limiter.allow(); !allowed {
+			t.Fatalf("record %d should be allowed", i)
+		}
+	}
+
+	if allowed, _ := limiter.allow(); allowed {
+		t.Fatal("record exceeding burst should be dropped")
+	}
+
+	now = now.Add(200 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.allow(); !allowed {
+			t.Fatalf("record %d should be allowed", i)
+		}
+	}
+
+	if allowed, _ := limiter.allow(); allowed {
+		t.Fatal("record exceeding limit should be dropped")
+	}
+
+	now = now.Add(limitSummaryInterval)
+
+	allowed, summary := limiter.allow()
+	if !allowed || summary != 2 {
+		t.Fatalf("allowed %+v or summary %d is wrong", allowed, summary)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLimitHandler$
+func TestLimitHandler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithRateLimit(1, 2))
+	for i := 0; i < 10; i++ {
+		logger.With("i", i).Info("msg")
+	}
+
+	got := buffer.String()
+	if strings.Count(got, "¦ msg ¦") != 2 || strings.Contains(got, limitSummaryMsg) {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	now = now.Add(limitSummaryInterval)
+	logger.Info("msg")
+
+	got = buffer.String()
+	if !strings.Contains(got, "¦ WARN ¦ "+limitSummaryMsg+" ¦ dropped=8\n") || strings.Count(got, "¦ msg") != 3 {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	logger.Info("msg")
+	logger.Info("msg")
+	logger.Close()
+
+	if got = buffer.String(); !strings.HasSuffix(got, limitSummaryMsg+" ¦ dropped=1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// lineWriter is a writer which splits bytes written into lines and logs each line as a log.
+type lineWriter struct {
+	logger *Logger
+	level  slog.Level
+
+	// line is the incomplete line waiting for a line break.
+	line []byte
+
+	lock sync.Mutex
+}
+
+// Writer returns a writer which splits bytes written into lines and logs each line in level.
+// It's useful for capturing outputs of subprocesses or libraries which only take an io.Writer.
+// An incomplete line will be kept until a line break comes or its size reaches defaults.MaxBufferSize.
+// Closing the writer logs the incomplete line, like the last line of outputs without a line break, so close it after writing.
+// Empty lines will be ignored, and closing the writer won't close the logger.
+func (l *Logger) Writer(level slog.Level) io.WriteCloser {
+	lw := &lineWriter{
+		logger: l,
+		level:  level,
+	}
+
+	return lw
+}
+
+func (lw *lineWriter) logLine() {
+	line := bytes.TrimSuffix(lw.line, []byte{'\r'})
+	if len(line) > 0 {
+		lw.logger.log(context.Background(), lw.level, 0, string(line))
+	}
+
+	lw.line = lw.line[:0]
+}
+
+// Write splits p into lines and logs each line.
+// It always returns len(p) and a nil error.
+func (lw *lineWriter) Write(p []byte) (n int, err error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	n = len(p)
+	for len(p) > 0 {
+		index := bytes.IndexByte(p, '\n')
+		if index < 0 {
+			lw.line = append(lw.line, p...)
+
+			if len(lw.line) >= defaults.MaxBufferSize {
+				lw.logLine()
+			}
+
+			break
+		}
+
+		lw.line = append(lw.line, p[:index]...)
+		lw.logLine()
+		p = p[index+1:]
+	}
+
+	return n, nil
+}
+
+// Close logs the incomplete line kept if it has one.
+// It always returns a nil error.
+func (lw *lineWriter) Close() error {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	lw.logLine()
+	return nil
+}
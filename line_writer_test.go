@@ -0,0 +1,89 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWriter$
+func TestLoggerWriter(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+
+	writer := logger.Writer(slog.LevelWarn)
+	writer.Write([]byte("line1\nli"))
+	writer.Write([]byte("ne2\r\n\n"))
+	writer.Write([]byte("line3"))
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(logs) != 2 {
+		t.Fatalf("len(logs) %d != 2", len(logs))
+	}
+
+	if !strings.HasSuffix(logs[0], "WARN ¦ line1") {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	if !strings.HasSuffix(logs[1], "WARN ¦ line2") {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+
+	buffer.Reset()
+	writer.Write([]byte(strings.Repeat("x", defaults.MaxBufferSize)))
+
+	if !strings.Contains(buffer.String(), "WARN ¦ line3xxx") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWriterClose$
+func TestLoggerWriterClose(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+
+	// Outputs of subprocesses may end without a line break.
+	writer := logger.Writer(slog.LevelError)
+	writer.Write([]byte("line1\nlast line"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(logs) != 2 {
+		t.Fatalf("len(logs) %d != 2", len(logs))
+	}
+
+	if !strings.HasSuffix(logs[1], "ERROR ¦ last line") {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+
+	// Nothing is kept after closing, so closing again logs nothing.
+	buffer.Reset()
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); got != "" {
+		t.Fatalf("got %s should be empty", got)
+	}
+}
@@ -16,23 +16,28 @@ package logit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"runtime"
 	"time"
 
 	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/writer"
 )
 
 const (
-	keyBad = "!BADKEY"
-	keyPID = "pid"
+	keyBad    = "!BADKEY"
+	keyPID    = "pid"
+	keyLogger = "logger"
 )
 
 var (
 	pid = os.Getpid()
+
+	// exit is the function exiting the process, and it's a variable for testing.
+	exit = os.Exit
 )
 
 // Syncer is an interface that syncs data to somewhere.
@@ -41,7 +46,7 @@ type Syncer interface {
 }
 
 // Logger is the entry of logging in logit.
-// It has several levels including debug, info, warn and error.
+// It has several levels including trace, debug, info, warn, error, panic and fatal.
 // It's also a syncer or closer if handler is a syncer or closer.
 type Logger struct {
 	handler slog.Handler
@@ -49,8 +54,32 @@ type Logger struct {
 	syncer Syncer
 	closer io.Closer
 
+	// name is the name of logger set by Named, like "app.db".
+	name string
+
 	withSource bool
 	withPID    bool
+
+	// callerSkip is the count of frames skipped additionally when getting the caller of logs.
+	callerSkip int
+
+	// depth is the count of With and WithGroup calls deriving this logger.
+	depth        int
+	depthWarning *depthWarning
+
+	// origin is the handler created by NewLogger and derivations are With and WithGroup calls applied to it.
+	// They're recorded only if WithContextMerge is set, so loggers in contexts can be merged.
+	origin      *origin
+	derivations []*derivation
+
+	// closeState is shared by derived loggers, so logs after closing will be discarded.
+	closeState *closeState
+
+	// levelState is shared by derived loggers, so they can be elevated together.
+	levelState *levelState
+
+	// stats is shared by derived loggers, see Logger.Stats.
+	stats *recordStats
 }
 
 // NewLogger creates a logger with given options or panics if failed.
@@ -73,6 +102,13 @@ func NewLoggerGracefully(opts ...Option) (*Logger, error) {
 		opt.applyTo(conf)
 	}
 
+	var leveler slog.Leveler = conf.level
+	if conf.leveler != nil {
+		leveler = conf.leveler
+	}
+
+	conf.levelState = newLevelState(leveler)
+
 	handler, syncer, closer, err := conf.newHandler()
 	if err != nil {
 		return nil, err
@@ -84,6 +120,27 @@ func NewLoggerGracefully(opts ...Option) (*Logger, error) {
 		closer:     closer,
 		withSource: conf.withSource,
 		withPID:    conf.withPID,
+		callerSkip: conf.callerSkip,
+		closeState: newCloseState(),
+		levelState: conf.levelState,
+		stats:      conf.stats,
+	}
+
+	// Nothing should be kept in dry run, so the logger is closed before starting goroutines like the sync timer.
+	if conf.dryRun {
+		if err = logger.Close(); err != nil {
+			return nil, err
+		}
+
+		return logger, nil
+	}
+
+	if conf.maxDepth > 0 {
+		logger.depthWarning = newDepthWarning(conf.maxDepth, handler)
+	}
+
+	if conf.mergeContext {
+		logger.origin = &origin{handler: handler}
 	}
 
 	if conf.syncTimer > 0 {
@@ -100,9 +157,13 @@ func (l *Logger) runSyncTimer(d time.Duration) {
 	for {
 		select {
 		case <-timer.C:
-			if err := l.Sync(); err != nil {
+			if err := l.syncInterval(); err != nil {
 				defaults.HandleError("Logger.Sync", err)
 			}
+
+			timer.Reset(d)
+		case <-l.closeState.done:
+			return
 		}
 	}
 }
@@ -112,6 +173,18 @@ func (l *Logger) clone() *Logger {
 	return &newLogger
 }
 
+// derive clones a logger with depth increased and checks its depth.
+func (l *Logger) derive() *Logger {
+	newLogger := l.clone()
+	newLogger.depth++
+
+	if newLogger.depthWarning != nil {
+		newLogger.depthWarning.check(newLogger.depth)
+	}
+
+	return newLogger
+}
+
 func (l *Logger) squeezeAttr(args []any) (slog.Attr, []any) {
 	// len of args must be > 0
 	switch arg := args[0].(type) {
@@ -142,7 +215,7 @@ func (l *Logger) newAttrs(args []any) (attrs []slog.Attr) {
 // All logs from the new logger will carry the given args.
 // See slog.Handler.WithAttrs.
 func (l *Logger) With(args ...any) *Logger {
-	if len(args) <= 0 {
+	if len(args) <= 0 || l.isNop() {
 		return l
 	}
 
@@ -151,8 +224,9 @@ func (l *Logger) With(args ...any) *Logger {
 		return l
 	}
 
-	newLogger := l.clone()
+	newLogger := l.derive()
 	newLogger.handler = l.handler.WithAttrs(attrs)
+	newLogger.recordDerivation(&derivation{attrs: attrs})
 
 	return newLogger
 }
@@ -161,15 +235,67 @@ func (l *Logger) With(args ...any) *Logger {
 // All logs from the new logger will be grouped by the name.
 // See slog.Handler.WithGroup.
 func (l *Logger) WithGroup(name string) *Logger {
-	if name == "" {
+	if name == "" || l.isNop() {
 		return l
 	}
 
-	newLogger := l.clone()
+	newLogger := l.derive()
 	newLogger.handler = l.handler.WithGroup(name)
+	newLogger.recordDerivation(&derivation{group: name})
+
+	return newLogger
+
+}
+
+// Named returns a new logger with name appended to the name of logger, joined with '.'.
+// All logs from the new logger will carry the name in a "logger" attr, like "app.db".
+// Unlike WithGroup, the name won't change the shape of logs, so it's useful for naming components.
+// The attr is added to logs, so it will be grouped if the logger has groups.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" || l.isNop() {
+		return l
+	}
+
+	newLogger := l.derive()
+	newLogger.name = name
+
+	if l.name != "" {
+		newLogger.name = l.name + "." + name
+	}
+
+	return newLogger
+}
+
+// WithCallerSkip returns a logger skipping more skip frames when getting the caller of logs.
+// It's useful if you wrap logger in a helper function, so the source of logs is the caller of helper instead of helper itself.
+// The skip is added to the one set by WithCallerDepth and it won't affect the logger returned by Slog.
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	if skip == 0 || l.isNop() {
+		return l
+	}
+
+	newLogger := l.clone()
+	newLogger.callerSkip += skip
 
 	return newLogger
+}
 
+// Slog returns a slog.Logger using the handler of logger.
+// All logs from the slog.Logger will be handled like logs from logger, so they will be written to the same writer.
+// It's useful for libraries logging through slog.
+// Logs after closing the logger will be discarded and counted as dropped like logs from logger.
+func (l *Logger) Slog() *slog.Logger {
+	handler := l.handler
+	if l.withPID {
+		handler = handler.WithAttrs([]slog.Attr{slog.Int(keyPID, pid)})
+	}
+
+	if l.name != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String(keyLogger, l.name)})
+	}
+
+	handler = newCloseHandler(handler, l.closeState, l.stats)
+	return slog.New(handler)
 }
 
 // enabled reports whether the logger should ignore logs whose level is lower.
@@ -177,6 +303,17 @@ func (l *Logger) enabled(level slog.Level) bool {
 	return l.handler.Enabled(context.Background(), level)
 }
 
+// Enabled reports whether the logger handles logs with ctx in level.
+// It's useful for facades wrapping logger, which should check the level before doing something like firing hooks.
+func (l *Logger) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.handler.Enabled(ctx, level)
+}
+
+// TraceEnabled reports whether the logger should ignore logs whose level is lower than trace.
+func (l *Logger) TraceEnabled() bool {
+	return l.enabled(LevelTrace)
+}
+
 // DebugEnabled reports whether the logger should ignore logs whose level is lower than debug.
 func (l *Logger) DebugEnabled() bool {
 	return l.enabled(slog.LevelDebug)
@@ -202,12 +339,10 @@ func (l *Logger) PrintEnabled() bool {
 	return l.enabled(defaults.LevelPrint)
 }
 
-func (l *Logger) newRecord(level slog.Level, msg string, args []any) slog.Record {
+func (l *Logger) newRecord(level slog.Level, skip int, msg string, args []any) slog.Record {
 	var pc uintptr
 	if l.withSource {
-		var pcs [1]uintptr
-		runtime.Callers(defaults.CallerDepth, pcs[:])
-		pc = pcs[0]
+		pc = callerPC(defaults.CallerDepth + l.callerSkip + skip)
 	}
 
 	now := defaults.CurrentTime()
@@ -217,6 +352,10 @@ func (l *Logger) newRecord(level slog.Level, msg string, args []any) slog.Record
 		record.AddAttrs(slog.Int(keyPID, pid))
 	}
 
+	if l.name != "" {
+		record.AddAttrs(slog.String(keyLogger, l.name))
+	}
+
 	var attr slog.Attr
 	for len(args) > 0 {
 		attr, args = l.squeezeAttr(args)
@@ -226,69 +365,163 @@ func (l *Logger) newRecord(level slog.Level, msg string, args []any) slog.Record
 	return record
 }
 
-func (l *Logger) log(level slog.Level, msg string, args ...any) {
-	if !l.enabled(level) {
+// log logs a log in level and skips skip more frames when getting the caller of the log.
+func (l *Logger) log(ctx context.Context, level slog.Level, skip int, msg string, args ...any) {
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	if l.closeState.isClosed() {
+		l.stats.recordDropped()
+		l.closeState.report()
 		return
 	}
 
-	record := l.newRecord(level, msg, args)
+	record := l.newRecord(level, skip, msg, args)
 
-	if err := l.handler.Handle(context.Background(), record); err != nil {
+	if err := l.handler.Handle(ctx, record); err != nil {
 		defaults.HandleError("Logger.handler.Handle", err)
 	}
 }
 
+// Log logs a log with ctx, msg and args in level, and skips skip more frames above its caller when getting the caller of the log.
+// It's useful for facades wrapping logger, so their logs are handled like logs from Info or Error, including closing and stats.
+// The skip is added to the one set by WithCallerSkip, and a skip of 0 means the caller of Log.
+func (l *Logger) Log(ctx context.Context, level slog.Level, skip int, msg string, args ...any) {
+	l.log(ctx, level, skip, msg, args...)
+}
+
+// Trace logs a log with msg and args in trace level.
+func (l *Logger) Trace(msg string, args ...any) {
+	l.log(context.Background(), LevelTrace, 0, msg, args...)
+}
+
+// TraceContext logs a log with ctx, msg and args in trace level.
+func (l *Logger) TraceContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LevelTrace, 0, msg, args...)
+}
+
 // Debug logs a log with msg and args in debug level.
 func (l *Logger) Debug(msg string, args ...any) {
-	l.log(slog.LevelDebug, msg, args...)
+	l.log(context.Background(), slog.LevelDebug, 0, msg, args...)
 }
 
 // Info logs a log with msg and args in info level.
 func (l *Logger) Info(msg string, args ...any) {
-	l.log(slog.LevelInfo, msg, args...)
+	l.log(context.Background(), slog.LevelInfo, 0, msg, args...)
 }
 
 // Warn logs a log with msg and args in warn level.
 func (l *Logger) Warn(msg string, args ...any) {
-	l.log(slog.LevelWarn, msg, args...)
+	l.log(context.Background(), slog.LevelWarn, 0, msg, args...)
 }
 
 // Error logs a log with msg and args in error level.
 func (l *Logger) Error(msg string, args ...any) {
-	l.log(slog.LevelError, msg, args...)
+	l.log(context.Background(), slog.LevelError, 0, msg, args...)
+}
+
+// Panic logs a log with msg and args in panic level.
+// It syncs the logger and panics with msg after logging.
+func (l *Logger) Panic(msg string, args ...any) {
+	l.log(context.Background(), LevelPanic, 0, msg, args...)
+	l.panic(msg)
+}
+
+// Panicf logs a log with format and args in panic level.
+// It syncs the logger and panics with the formatted message after logging.
+func (l *Logger) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(context.Background(), LevelPanic, 0, msg)
+	l.panic(msg)
+}
+
+func (l *Logger) panic(msg string) {
+	if err := l.Sync(); err != nil {
+		defaults.HandleError("Logger.Sync", err)
+	}
+
+	panic(msg)
+}
+
+// Fatal logs a log with msg and args in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(context.Background(), LevelFatal, 0, msg, args...)
+	l.exit()
+}
+
+// FatalContext logs a log with ctx, msg and args in fatal level.
+// It syncs the logger and exits the process with code 1 after logging.
+func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LevelFatal, 0, msg, args...)
+	l.exit()
+}
+
+func (l *Logger) exit() {
+	if err := l.Sync(); err != nil {
+		defaults.HandleError("Logger.Sync", err)
+	}
+
+	exit(1)
 }
 
 // Printf logs a log with format and args in print level.
 // It a old-school way to log.
 func (l *Logger) Printf(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	l.log(defaults.LevelPrint, msg)
+	l.log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Print logs a log with args in print level.
 // It a old-school way to log.
 func (l *Logger) Print(args ...interface{}) {
 	msg := fmt.Sprint(args...)
-	l.log(defaults.LevelPrint, msg)
+	l.log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Println logs a log with args in print level.
 // It a old-school way to log.
 func (l *Logger) Println(args ...interface{}) {
 	msg := fmt.Sprintln(args...)
-	l.log(defaults.LevelPrint, msg)
+	l.log(context.Background(), defaults.LevelPrint, 0, msg)
 }
 
 // Sync syncs the logger and returns an error if failed.
+// Syncing a closed logger does nothing.
 func (l *Logger) Sync() error {
-	return l.syncer.Sync()
+	if l.closeState.isClosed() {
+		return nil
+	}
+
+	err := l.syncer.Sync()
+	l.stats.recordSynced(err)
+
+	return err
 }
 
-// Close closes the logger and returns an error if failed.
+// syncInterval syncs the logger by the sync timer, so flushes will be recorded as writer.FlushInterval.
+func (l *Logger) syncInterval() error {
+	if l.closeState.isClosed() {
+		return nil
+	}
+
+	err := syncWithReason(l.syncer, writer.FlushInterval)
+	l.stats.recordSynced(err)
+
+	return err
+}
+
+// Close syncs and closes the logger and returns an error if failed.
+// It will still close the logger even if syncing failed, and the errors of both will be joined.
+// It's safe to call Close multiple times and concurrently, and only the first call closes the logger.
+// Logs after closing will be discarded and ErrLoggerClosed will be passed to defaults.HandleError once.
 func (l *Logger) Close() error {
-	if err := l.Sync(); err != nil {
-		return err
+	syncErr := l.Sync()
+	if !l.closeState.close() {
+		return nil
 	}
 
-	return l.closer.Close()
+	closeErr := l.closer.Close()
+	return errors.Join(syncErr, closeErr)
 }
@@ -16,8 +16,13 @@ package logit
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -32,20 +37,22 @@ type testLoggerHandler struct {
 
 type testSyncer struct {
 	synced bool
+	err    error
 }
 
 func (ts *testSyncer) Sync() error {
 	ts.synced = true
-	return nil
+	return ts.err
 }
 
 type testCloser struct {
 	closed bool
+	err    error
 }
 
 func (tc *testCloser) Close() error {
 	tc.closed = true
-	return nil
+	return tc.err
 }
 
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestNewLogger$
@@ -159,6 +166,110 @@ func TestLoggerWithGroup(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerNamed$
+func TestLoggerNamed(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+
+	if newLogger := logger.Named(""); newLogger != logger {
+		t.Fatalf("newLogger %+v != logger %+v", newLogger, logger)
+	}
+
+	appLogger := logger.Named("app")
+	dbLogger := appLogger.Named("db")
+
+	if appLogger.name != "app" || dbLogger.name != "app.db" || logger.name != "" {
+		t.Fatalf("names %s, %s, %s are wrong", logger.name, appLogger.name, dbLogger.name)
+	}
+
+	dbLogger.Info("msg", "k", "v")
+	dbLogger.Slog().Info("slog msg")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if !strings.HasSuffix(logs[0], `"msg":"msg","logger":"app.db","k":"v"}`) {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	if !strings.HasSuffix(logs[1], `"msg":"slog msg","logger":"app.db"}`) {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+}
+
+func logByHelper(logger *Logger, msg string) {
+	logger.Info(msg)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWithCallerSkip$
+func TestLoggerWithCallerSkip(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	if newLogger := logger.WithCallerSkip(0); newLogger != logger {
+		t.Fatalf("newLogger %+v != logger %+v", newLogger, logger)
+	}
+
+	logByHelper(logger, "helper")
+	logByHelper(logger.WithCallerSkip(1), "caller")
+
+	logger = NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource(), WithCallerDepth(1))
+	logByHelper(logger, "depth")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(logs) != 3 {
+		t.Fatalf("len(logs) %d != 3", len(logs))
+	}
+
+	if !strings.Contains(logs[0], `"function":"github.com/FishGoddess/logit.logByHelper"`) {
+		t.Fatalf("logs[0] %s is wrong", logs[0])
+	}
+
+	for _, log := range logs[1:] {
+		if !strings.Contains(log, `"function":"github.com/FishGoddess/logit.TestLoggerWithCallerSkip"`) {
+			t.Fatalf("log %s is wrong", log)
+		}
+	}
+}
+
+func logSkipByHelper(logger *Logger, msg string) {
+	logger.Log(context.Background(), slog.LevelWarn, 1, msg, "key", "value")
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerLog$
+func TestLoggerLog(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	logger.Log(context.Background(), slog.LevelInfo, 0, "direct")
+	logSkipByHelper(logger, "helper")
+	logger.Log(context.Background(), LevelTrace, 0, "disabled")
+
+	logs := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(logs) != 2 {
+		t.Fatalf("len(logs) %d != 2", len(logs))
+	}
+
+	if !strings.Contains(logs[1], `"level":"WARN"`) || !strings.HasSuffix(logs[1], `"msg":"helper","key":"value"}`) {
+		t.Fatalf("logs[1] %s is wrong", logs[1])
+	}
+
+	for _, log := range logs {
+		if !strings.Contains(log, `"function":"github.com/FishGoddess/logit.TestLoggerLog"`) {
+			t.Fatalf("log %s is wrong", log)
+		}
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer.Reset()
+	logger.Log(context.Background(), slog.LevelError, 0, "closed")
+
+	if got := buffer.String(); got != "" {
+		t.Fatalf("got %s should be empty after closing", got)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerEnabled$
 func TestLoggerEnabled(t *testing.T) {
 	logger := NewLogger(WithErrorLevel())
@@ -178,6 +289,14 @@ func TestLoggerEnabled(t *testing.T) {
 	if !logger.enabled(slog.LevelError) {
 		t.Fatal("logger enabled error")
 	}
+
+	if logger.Enabled(context.Background(), slog.LevelWarn) {
+		t.Fatal("logger Enabled warn")
+	}
+
+	if !logger.Enabled(context.Background(), slog.LevelError) {
+		t.Fatal("logger Enabled error")
+	}
 }
 
 func removeTimeAndSource(str string) string {
@@ -280,3 +399,172 @@ func TestLoggerClose(t *testing.T) {
 		t.Fatal("closer.closed is wrong")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseJoinErrors$
+func TestLoggerCloseJoinErrors(t *testing.T) {
+	errSync := errors.New("sync failed")
+	errClose := errors.New("close failed")
+
+	syncer := &testSyncer{err: errSync}
+	closer := &testCloser{err: errClose}
+
+	logger := &Logger{
+		syncer: syncer,
+		closer: closer,
+	}
+
+	err := logger.Close()
+	if !errors.Is(err, errSync) || !errors.Is(err, errClose) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if !closer.closed {
+		t.Fatal("closer should be closed even if syncing failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerDryRun$
+func TestLoggerDryRun(t *testing.T) {
+	logger, err := NewLoggerGracefully(WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !logger.closeState.isClosed() {
+		t.Fatal("logger should be closed")
+	}
+
+	if _, err = os.Stdout.Write(nil); err != nil {
+		t.Fatalf("stdout should not be closed: %+v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dir", "logit.log")
+
+	if _, err = NewLoggerGracefully(WithFile(path), WithBuffer(4096), WithDryRun()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The parent of path is a file, so the file can't be opened.
+	if _, err = NewLoggerGracefully(WithFile(filepath.Join(path, "logit.log")), WithDryRun()); err == nil {
+		t.Fatal("opening file failed should return an error")
+	}
+
+	if _, err = NewLoggerGracefully(WithHandler("unknown"), WithDryRun()); err == nil {
+		t.Fatal("unknown handler should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerFatal$
+func TestLoggerFatal(t *testing.T) {
+	exitCode := 0
+	exit = func(code int) {
+		exitCode = code
+	}
+
+	defer func() {
+		exit = os.Exit
+	}()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBatch(16))
+
+	logger.Fatal("fatal msg", "key", 1)
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	if !strings.Contains(buffer.String(), "FATAL ¦ fatal msg ¦ key=1") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+
+	exitCode = 0
+	buffer.Reset()
+
+	logger.FatalContext(context.Background(), "fatal context msg", "key", 2)
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	if !strings.Contains(buffer.String(), "FATAL ¦ fatal context msg ¦ key=2") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerPanic$
+func TestLoggerPanic(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBatch(16))
+
+	testPanic := func(fn func(), want string) {
+		defer func() {
+			if r := recover(); r != want {
+				t.Fatalf("r %+v != want %s", r, want)
+			}
+		}()
+
+		fn()
+	}
+
+	testPanic(func() { logger.Panic("panic msg", "key", 1) }, "panic msg")
+	if !strings.Contains(buffer.String(), "PANIC ¦ panic msg ¦ key=1") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+
+	buffer.Reset()
+
+	testPanic(func() { logger.Panicf("panicf %s", "msg") }, "panicf msg")
+	if !strings.Contains(buffer.String(), "PANIC ¦ panicf msg") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerTrace$
+func TestLoggerTrace(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+
+	if logger.TraceEnabled() {
+		t.Fatal("logger enabled trace")
+	}
+
+	logger.Trace("trace msg")
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer %s should be empty", buffer.String())
+	}
+
+	logger = NewLogger(WithWriter(buffer), WithTraceLevel())
+	if !logger.TraceEnabled() {
+		t.Fatal("logger disabled trace")
+	}
+
+	logger.Trace("trace msg", "key", 1)
+	logger.TraceContext(context.Background(), "trace context msg", "key", 2)
+
+	logs := buffer.String()
+	if !strings.Contains(logs, "TRACE ¦ trace msg ¦ key=1") {
+		t.Fatalf("logs %s is wrong", logs)
+	}
+
+	if !strings.Contains(logs, "TRACE ¦ trace context msg ¦ key=2") {
+		t.Fatalf("logs %s is wrong", logs)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSlog$
+func TestLoggerSlog(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithPID())
+
+	slogLogger := logger.Slog()
+	slogLogger.Info("slog msg", "key", 1)
+
+	want := fmt.Sprintf("INFO ¦ slog msg ¦ %s=%d ¦ key=1", keyPID, pid)
+	if !strings.Contains(buffer.String(), want) {
+		t.Fatalf("buffer %s doesn't contain %s", buffer.String(), want)
+	}
+}
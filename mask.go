@@ -0,0 +1,154 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maskedValue replaces the sensitive values.
+	maskedValue = "***"
+)
+
+var (
+	// maskKeyRegexp matches rules which are keys instead of patterns.
+	maskKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+)
+
+// masker masks values of sensitive keys and substrings matching sensitive patterns.
+type masker struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// newMasker creates a masker with rules, and a rule only having letters, digits, '_', '.' and '-' is a key.
+// Other rules are patterns in regexp syntax, and an error will be returned if one of them is invalid.
+func newMasker(rules []string) (*masker, error) {
+	m := &masker{
+		keys: make(map[string]struct{}, len(rules)),
+	}
+
+	for _, rule := range rules {
+		if maskKeyRegexp.MatchString(rule) {
+			m.keys[strings.ToLower(rule)] = struct{}{}
+			continue
+		}
+
+		pattern, err := regexp.Compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("logit: mask pattern %s is invalid: %w", rule, err)
+		}
+
+		m.patterns = append(m.patterns, pattern)
+	}
+
+	return m, nil
+}
+
+func (m *masker) maskedKey(key string) bool {
+	_, ok := m.keys[strings.ToLower(key)]
+	return ok
+}
+
+// maskString replaces all substrings matching patterns with maskedValue and reports whether s is masked.
+func (m *masker) maskString(s string) (string, bool) {
+	masked := false
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(s) {
+			s = pattern.ReplaceAllLiteralString(s, maskedValue)
+			masked = true
+		}
+	}
+
+	return s, masked
+}
+
+func (m *masker) maskAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if m.maskedKey(attr.Key) {
+		attr.Value = slog.StringValue(maskedValue)
+		return attr
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		groupAttrs := attr.Value.Group()
+		maskedAttrs := make([]slog.Attr, 0, len(groupAttrs))
+
+		for _, groupAttr := range groupAttrs {
+			maskedAttrs = append(maskedAttrs, m.maskAttr(groupAttr))
+		}
+
+		attr.Value = slog.GroupValue(maskedAttrs...)
+	case slog.KindString:
+		if masked, ok := m.maskString(attr.Value.String()); ok {
+			attr.Value = slog.StringValue(masked)
+		}
+	case slog.KindAny:
+		// Values like errors and structs may carry sensitive data, so they're checked in string form.
+		if masked, ok := m.maskString(fmt.Sprint(attr.Value.Any())); ok {
+			attr.Value = slog.StringValue(masked)
+		}
+	}
+
+	return attr
+}
+
+func (m *masker) maskAttrs(attrs []slog.Attr) []slog.Attr {
+	maskedAttrs := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		maskedAttrs = append(maskedAttrs, m.maskAttr(attr))
+	}
+
+	return maskedAttrs
+}
+
+// maskHandler masks messages and attrs of records before handling them.
+// It wraps the handler instead of using ReplaceAttr, so messages and attrs added by WithAttrs are masked too.
+type maskHandler struct {
+	slog.Handler
+
+	masker *masker
+}
+
+func newMaskHandler(handler slog.Handler, masker *masker) slog.Handler {
+	return maskHandler{Handler: handler, masker: masker}
+}
+
+func (mh maskHandler) Handle(ctx context.Context, record slog.Record) error {
+	msg, _ := mh.masker.maskString(record.Message)
+	masked := slog.NewRecord(record.Time, record.Level, msg, record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(mh.masker.maskAttr(attr))
+		return true
+	})
+
+	return mh.Handler.Handle(ctx, masked)
+}
+
+func (mh maskHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newMaskHandler(mh.Handler.WithAttrs(mh.masker.maskAttrs(attrs)), mh.masker)
+}
+
+func (mh maskHandler) WithGroup(name string) slog.Handler {
+	return newMaskHandler(mh.Handler.WithGroup(name), mh.masker)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewMasker$
+func TestNewMasker(t *testing.T) {
+	m, err := newMasker([]string{"password", "X-Token", `\d{16}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.keys) != 2 || len(m.patterns) != 1 {
+		t.Fatalf("keys %+v or patterns %+v is wrong", m.keys, m.patterns)
+	}
+
+	if !m.maskedKey("PASSWORD") || !m.maskedKey("x-token") || m.maskedKey("user") {
+		t.Fatalf("keys %+v is wrong", m.keys)
+	}
+
+	if _, err = newMasker([]string{`(\d+`}); err == nil {
+		t.Fatal("invalid pattern should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMaskerMaskAttr$
+func TestMaskerMaskAttr(t *testing.T) {
+	m, err := newMasker([]string{"password", `\d{16}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		attr slog.Attr
+		want string
+	}{
+		{attr: slog.String("password", "123456"), want: "password=***"},
+		{attr: slog.Int("Password", 123456), want: "Password=***"},
+		{attr: slog.String("card", "card 1234567812345678 paid"), want: "card=card *** paid"},
+		{attr: slog.String("user", "fish"), want: "user=fish"},
+		{attr: slog.Int64("id", 1234567812345678), want: "id=1234567812345678"},
+		{attr: slog.Any("err", errors.New("card 1234567812345678 declined")), want: "err=card *** declined"},
+		{attr: slog.Group("login", slog.String("user", "fish"), slog.String("password", "123")), want: "login=[user=fish password=***]"},
+	}
+
+	for _, testCase := range testCases {
+		if got := m.maskAttr(testCase.attr).String(); got != testCase.want {
+			t.Fatalf("got %s != want %s", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMaskHandler$
+func TestMaskHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithMasking("password", "token", `\d{16}`))
+	logger = logger.With("token", "abc")
+	logger.Info("pay with 1234567812345678", slog.Group("req", "password", "123456", "user", "fish"))
+
+	got := buffer.String()
+	if strings.Contains(got, "abc") || strings.Contains(got, "123456") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if !strings.Contains(got, "pay with *** ¦ token=*** ¦ req.password=*** ¦ req.user=fish") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if _, err := NewLoggerGracefully(WithMasking(`(\d+`)); err == nil {
+		t.Fatal("invalid pattern should return an error")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMaskHandlerContext$
+func TestMaskHandlerContext(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithMasking("token"), WithContextExtractor(ExtractContextKeys("token", "user")))
+	ctx := context.WithValue(context.Background(), ContextKey("token"), "abc")
+	ctx = context.WithValue(ctx, ContextKey("user"), "fish")
+
+	logger.Log(ctx, slog.LevelInfo, 0, "msg")
+
+	got := buffer.String()
+	if strings.Contains(got, "abc") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if !strings.HasSuffix(got, "¦ msg ¦ token=*** ¦ user=fish\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
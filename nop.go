@@ -0,0 +1,58 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// nopHandler is a handler which is always disabled.
+type nopHandler struct{}
+
+func (nopHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return false
+}
+
+func (nopHandler) Handle(ctx context.Context, record slog.Record) error {
+	return nil
+}
+
+func (nh nopHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return nh
+}
+
+func (nh nopHandler) WithGroup(name string) slog.Handler {
+	return nh
+}
+
+// NewNopLogger returns a logger discarding all logs without allocations.
+// It's a safe default for libraries which don't want to log unless users pass a logger.
+// Notice that Panic and Fatal still panic and exit even if logs are discarded.
+func NewNopLogger() *Logger {
+	logger := &Logger{
+		handler: nopHandler{},
+		syncer:  nilSyncer{},
+		closer:  nilCloser{},
+	}
+
+	return logger
+}
+
+// isNop reports whether the logger is created by NewNopLogger.
+func (l *Logger) isNop() bool {
+	_, ok := l.handler.(nopHandler)
+	return ok
+}
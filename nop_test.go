@@ -0,0 +1,75 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewNopLogger$
+func TestNewNopLogger(t *testing.T) {
+	logger := NewNopLogger()
+
+	if logger.DebugEnabled() || logger.ErrorEnabled() {
+		t.Fatal("nop logger is enabled")
+	}
+
+	if logger.With("key", "value") != logger || logger.WithGroup("group") != logger {
+		t.Fatal("nop logger derives a new logger")
+	}
+
+	if logger.Named("name") != logger || logger.WithCached("key", "value") != logger {
+		t.Fatal("nop logger derives a new logger")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info("msg", "key", "value", "number", 123)
+		logger.TraceContext(context.Background(), "msg")
+		logger.With("key", "value").Error("msg")
+	})
+
+	if allocs > 0 {
+		t.Fatalf("allocs %.2f > 0", allocs)
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if r := recover(); r != "msg" {
+			t.Fatalf("r %+v != msg", r)
+		}
+	}()
+
+	logger.Panic("msg")
+}
+
+// go test -v -run=^$ -bench=^BenchmarkNopLogger$ -benchtime=1s
+func BenchmarkNopLogger(b *testing.B) {
+	logger := NewNopLogger()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("msg", "key", "value", "number", 123)
+	}
+}
@@ -17,6 +17,7 @@ package logit
 import (
 	"io"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
@@ -34,6 +35,42 @@ func (o Option) applyTo(conf *config) {
 	o(conf)
 }
 
+// WithLevel sets level to config.
+// It's useful for levels registered by RegisterLevel.
+func WithLevel(level slog.Level) Option {
+	return func(conf *config) {
+		conf.level = level
+	}
+}
+
+// WithLeveler sets a dynamic level like slog.LevelVar to config, which takes precedence over the level set by WithLevel.
+// Loggers having the same leveler share the level, so changing it adjusts all of them at once.
+// Elevating loggers still works, and the level of leveler will be used after elevations expired.
+// See RegisterLeveler if you want to share a leveler with loggers created from configs.
+func WithLeveler(leveler slog.Leveler) Option {
+	return func(conf *config) {
+		conf.leveler = leveler
+	}
+}
+
+// WithLevelVar sets a level variable shared by loggers to config, and a nil level variable will be ignored.
+// It's useful for governing a fleet of loggers like loggers of tenants or components with one level variable.
+// See NewLevelVar and WithLeveler.
+func WithLevelVar(levelVar *slog.LevelVar) Option {
+	return func(conf *config) {
+		if levelVar != nil {
+			conf.leveler = levelVar
+		}
+	}
+}
+
+// WithTraceLevel sets trace level to config.
+func WithTraceLevel() Option {
+	return func(conf *config) {
+		conf.level = LevelTrace
+	}
+}
+
 // WithDebugLevel sets debug level to config.
 func WithDebugLevel() Option {
 	return func(conf *config) {
@@ -74,6 +111,29 @@ func WithWriter(w io.Writer) Option {
 	}
 }
 
+// WithWriters sets writers to config, so logs will be written to all of them, like stdout and a rotate file.
+// Syncing and closing the logger syncs and closes all of them, except stdout and stderr which won't be closed.
+// A writer failed to write won't stop writing the rest writers, see writer.Fanout.
+func WithWriters(writers ...io.Writer) Option {
+	newWriter := func() (io.Writer, error) {
+		return writer.Fanout(writers...), nil
+	}
+
+	return func(conf *config) {
+		conf.newWriter = newWriter
+	}
+}
+
+// WithErrorWriter sets the writer of records not lower than warn level, and records lower than warn are still written to the writer.
+// It's useful for writing warn and error logs to stderr or an error file while info and debug logs go to stdout.
+// The error writer is wrapped in the same modes as the writer like WithBuffer, and it will be synced and closed with the logger.
+// Stdout and stderr won't be synced or closed like writers in WithWriters, so WithErrorWriter(os.Stderr) is safe.
+func WithErrorWriter(writer io.Writer) Option {
+	return func(conf *config) {
+		conf.errorWriter = writer
+	}
+}
+
 // WithStdout sets os.Stdout to config.
 // All logs will be written to stdout.
 func WithStdout() Option {
@@ -135,6 +195,44 @@ func WithRotateFile(path string, opts ...rotate.Option) Option {
 	}
 }
 
+// WithLevelFiles sets rotate files of levels to config, so records will be written to the file of their levels.
+// The name of level is inserted before the extension of path, like "app.debug.log", "app.info.log", "app.warn.log" and "app.error.log" for "app.log".
+// Trace logs are written to the debug file, and panic and fatal logs are written to the error file.
+// All files share the same opts of rotation and they replace the writer and the error writer, see WithRotateFile.
+func WithLevelFiles(path string, opts ...rotate.Option) Option {
+	levels := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+	levelWriters := make([]levelWriter, 0, len(levels))
+	for _, level := range levels {
+		levelPath := levelFilePath(path, level)
+
+		newWriter := func() (io.Writer, error) {
+			return rotate.New(levelPath, opts...)
+		}
+
+		levelWriters = append(levelWriters, levelWriter{level: level, newWriter: newWriter})
+	}
+
+	return func(conf *config) {
+		conf.levelWriters = levelWriters
+	}
+}
+
+// WithJournal sets journald socket and journal handler to config.
+// All logs will be sent to journald in native protocol, so attrs will be kept as journal fields.
+// Don't use it with WithBuffer or WithBatch because each datagram should carry only one log.
+// The path of socket can be specified by defaults package, see defaults.JournalSocket.
+func WithJournal() Option {
+	newWriter := func() (io.Writer, error) {
+		return net.Dial("unixgram", defaults.JournalSocket)
+	}
+
+	return func(conf *config) {
+		conf.handler = handler.Journal
+		conf.newWriter = newWriter
+	}
+}
+
 // WithBuffer sets a buffer writer to config.
 // You should specify a buffer size in bytes.
 // The remained data in buffer may discard if you kill the process without syncing or closing the logger.
@@ -148,8 +246,37 @@ func WithBuffer(bufferSize uint64) Option {
 	}
 }
 
+// WithAtomicWrites sets an atomic writer with maxSize to config, and maxSize is usually writer.PipeBuf.
+// Each log will be written to writer in one write call with a lock, so logs won't be interleaved or torn under high concurrency.
+// Logs not larger than maxSize won't be interleaved by other processes if writer is a pipe or a file opened with O_APPEND.
+// Notice that a buffer or batch writer writes many logs at once, so use a buffer size not larger than maxSize if you need both.
+// See writer.Atomic.
+func WithAtomicWrites(maxSize int) Option {
+	atomicWriter := func(w io.Writer) io.Writer {
+		return writer.Atomic(w, maxSize)
+	}
+
+	return func(conf *config) {
+		conf.atomicWriter = atomicWriter
+	}
+}
+
+// WithFraming sets a frame writer with framer to config.
+// Each log will be framed before being buffered or batched, so logs won't be corrupted when sending through transports like tcp.
+// See writer.LengthPrefixFramer, writer.CRLFFramer and writer.DelimiterFramer.
+func WithFraming(framer writer.Framer) Option {
+	frameWriter := func(w io.Writer) io.Writer {
+		return writer.Frame(w, framer)
+	}
+
+	return func(conf *config) {
+		conf.frameWriter = frameWriter
+	}
+}
+
 // WithBatch sets a batch writer to config.
 // You should specify a batch size in count.
+// Batches are flushed in order, so logs are written in call order, which is required by streams like audit logs.
 // The remained logs in batch may discard if you kill the process without syncing or closing the logger.
 func WithBatch(batchSize uint64) Option {
 	wrapWriter := func(w io.Writer) io.Writer {
@@ -161,6 +288,20 @@ func WithBatch(batchSize uint64) Option {
 	}
 }
 
+// WithParallelBatch sets a batch writer flushing at most parallelism full batches in parallel to config.
+// It's useful for network sinks of telemetry streams, but batches may be written out of order.
+// The writer must be safe in concurrency, and parallelism less than 2 is the same as WithBatch.
+// See writer.BatchParallel.
+func WithParallelBatch(batchSize uint64, parallelism int) Option {
+	wrapWriter := func(w io.Writer) io.Writer {
+		return writer.BatchParallel(w, batchSize, parallelism)
+	}
+
+	return func(conf *config) {
+		conf.wrapWriter = wrapWriter
+	}
+}
+
 // WithHandler sets handler to config.
 // See RegisterHandler.
 func WithHandler(handler string) Option {
@@ -190,6 +331,110 @@ func WithJsonHandler() Option {
 	}
 }
 
+// WithConsoleHandler sets console handler to config.
+// It's a human-friendly handler for development, and levels are colored if logs are written to a terminal.
+// See handler.NewConsoleHandler.
+func WithConsoleHandler() Option {
+	return func(conf *config) {
+		conf.handler = handler.Console
+	}
+}
+
+// WithConsoleLevelColors sets the colors of levels if the handler is console, and levels not in colors use the default colors.
+// It's useful if the default colors are hard to read on your terminal theme, and colors can be 256 colors or true colors.
+// See handler.Color and handler.ParseColor.
+//
+//	WithConsoleLevelColors(map[slog.Level]handler.Color{slog.LevelInfo: handler.Color256(28)})
+func WithConsoleLevelColors(colors map[slog.Level]handler.Color) Option {
+	return func(conf *config) {
+		conf.consoleColors = colors
+	}
+}
+
+// WithConsoleLevelIcons sets the icons of levels like emojis if the handler is console, which are written before levels.
+// See handler.WithConsoleLevelIcons.
+func WithConsoleLevelIcons(icons map[slog.Level]string) Option {
+	return func(conf *config) {
+		conf.consoleIcons = icons
+	}
+}
+
+// WithConsoleColumns sets the widths of columns if the handler is console, and 0 means the default layout.
+// The levelWidth is the fixed width of levels, and the loggerWidth is the width of the column of logger names set by Named.
+// The messageWidth is the max width of messages, and longer messages will be wrapped so tailing busy logs still lines up.
+// See handler.WithConsoleLevelWidth, handler.WithConsoleLoggerWidth and handler.WithConsoleMessageWidth.
+func WithConsoleColumns(levelWidth int, loggerWidth int, messageWidth int) Option {
+	return func(conf *config) {
+		conf.consoleLevelWidth = levelWidth
+		conf.consoleLoggerWidth = loggerWidth
+		conf.consoleMessageWidth = messageWidth
+	}
+}
+
+// WithAutoHandler sets auto handler to config, which picks a handler by the writer.
+// It's console for terminals, json for stdout and stderr which aren't terminals like pipes in CI, and tape for files.
+// It's the right default for tools running both interactively and in CI, and you can still override it with other handler options.
+// See handler.AutoName.
+func WithAutoHandler() Option {
+	return func(conf *config) {
+		conf.handler = handler.Auto
+	}
+}
+
+// WithColor colors levels and keys of logs if the handler is tape and logs are written to a terminal.
+// Logs written to files or other writers won't be colored, so they won't contain escape codes.
+// See handler.NewColorTapeHandler.
+func WithColor() Option {
+	return func(conf *config) {
+		conf.color = true
+	}
+}
+
+// WithEscapeMode sets the mode of escaping strings if the handler is tape.
+// Use handler.EscapeNone if you want raw and human-readable logs in files.
+// See handler.EscapeMode.
+func WithEscapeMode(mode handler.EscapeMode) Option {
+	return func(conf *config) {
+		conf.escape = mode
+	}
+}
+
+// WithInterning caches escaped keys, messages and string values up to maxEntries if the handler is tape.
+// It's useful for long-lived loggers logging the same strings like status names and route templates repeatedly,
+// and it pays off with handler.EscapeFull only, see handler.WithTapeInterning.
+func WithInterning(maxEntries int) Option {
+	return func(conf *config) {
+		conf.internSize = maxEntries
+	}
+}
+
+// WithCSVHandler sets csv handler with columns to config.
+// Columns can be "time", "level", "msg", "source" or keys of attrs, and handler.DefaultCSVColumns will be used if no columns are specified.
+// See handler.NewCSVHandler.
+func WithCSVHandler(columns ...string) Option {
+	return func(conf *config) {
+		conf.handler = handler.CSV
+		conf.csvColumns = columns
+	}
+}
+
+// WithProtobufHandler sets protobuf handler to config.
+// Logs will be written as protobuf messages prefixed with their length, see handler/protobuf.proto.
+func WithProtobufHandler() Option {
+	return func(conf *config) {
+		conf.handler = handler.Protobuf
+	}
+}
+
+// WithJournalExportHandler sets journal export handler to config.
+// It's useful for hosts capturing outputs of units and ingesting them as journal export format.
+// If you want to send logs to journald directly, use WithJournal instead.
+func WithJournalExportHandler() Option {
+	return func(conf *config) {
+		conf.handler = handler.JournalExport
+	}
+}
+
 // WithReplaceAttr sets replaceAttr to config.
 func WithReplaceAttr(replaceAttr func(groups []string, attr slog.Attr) slog.Attr) Option {
 	return func(conf *config) {
@@ -197,6 +442,197 @@ func WithReplaceAttr(replaceAttr func(groups []string, attr slog.Attr) slog.Attr
 	}
 }
 
+// WithTimeFormat sets the format of the time of records to config.
+// The format can be one of the TimeFormatXxx constants or a layout like "2006-01-02 15:04:05".
+// The time is formatted before calling the replaceAttr set by WithReplaceAttr.
+func WithTimeFormat(format string) Option {
+	return func(conf *config) {
+		conf.timeFormat = format
+	}
+}
+
+// WithUTC converts the time of records to UTC before formatting it.
+// It works with WithTimeFormat, and the time will be formatted in its handler's way if no format is set.
+func WithUTC() Option {
+	return func(conf *config) {
+		conf.utc = true
+	}
+}
+
+// WithAttrHooks registers hooks which can drop or rewrite attrs of records, see AttrHook.
+// Hooks are chained in order and can be registered many times, so different concerns can be composed.
+// They run in the same chain as options like WithHashedAttrs in order of options, and before the replaceAttr set by WithReplaceAttr.
+// Notice that attrs having empty keys are always dropped, so renaming an attr to an empty key drops it too.
+func WithAttrHooks(hooks ...AttrHook) Option {
+	return func(conf *config) {
+		for _, hook := range hooks {
+			if hook != nil {
+				conf.attrReplacers = append(conf.attrReplacers, hook.replaceAttr)
+			}
+		}
+	}
+}
+
+// WithStaticAttrs attaches attrs to all records, like the metadata of deployment.
+// Attrs are added to the handler once when creating the logger, so they're encoded once instead of in every record.
+// It can be used many times and attrs will be appended in order.
+func WithStaticAttrs(attrs ...slog.Attr) Option {
+	return func(conf *config) {
+		conf.staticAttrs = append(conf.staticAttrs, attrs...)
+	}
+}
+
+// WithSampling samples records in each level to reduce the volume of logs like zap's sampler.
+// The first initial records in each level are logged in every interval, and then every thereafter record is logged.
+// All records after the first initial ones are dropped if thereafter is 0.
+// A record logged after some records dropped carries the dropped count in an attr named "dropped".
+// Records logged after the first initial ones carry sampled=true and sample_rate=1/thereafter, so downstream analytics can re-weight counts.
+// Notice that records are sampled by level only, and all loggers derived by With and WithGroup share the same counters.
+func WithSampling(initial int, thereafter int, interval time.Duration) Option {
+	return func(conf *config) {
+		conf.sampleInitial = initial
+		conf.sampleThereafter = thereafter
+		conf.sampleInterval = interval
+	}
+}
+
+// WithRateLimit limits the rate of records to limit per second with a token bucket of burst size, and drops excess records.
+// A summary record in warn level with the dropped count in an attr named "dropped" will be logged at most every ten seconds,
+// and it will be logged when closing the logger if some records were dropped after the last summary.
+// Notice that all loggers derived by With and WithGroup share the same limit.
+func WithRateLimit(limit int, burst int) Option {
+	return func(conf *config) {
+		conf.rateLimit = limit
+		conf.rateBurst = burst
+	}
+}
+
+// WithAsync moves handling records including encoding them to a background worker with a queue of queueSize.
+// The policy decides what to do when the queue is full, see DropPolicy, and DropPolicyBlock is used if it's empty.
+// Syncing the logger waits until records in the queue are handled, and closing the logger drains the queue.
+// The count of dropped records will be passed to defaults.HandleError when closing the logger.
+// Notice that the logger should be closed before the process exits, or records in the queue will be lost.
+func WithAsync(queueSize int, policy DropPolicy) Option {
+	return func(conf *config) {
+		conf.asyncSize = queueSize
+		conf.asyncPolicy = policy
+	}
+}
+
+// WithAsyncTTL sets the max time records below warn level can wait in the queue of async handler.
+// Records waiting longer are dropped instead of being handled, so a writer recovering from an outage,
+// like a network writer, won't be flooded with hours-old debug and info records in the backlog.
+// Records in warn level or higher are always handled, and records dropped are counted as dropped.
+// It only works with WithAsync, and zero ttl means records never expire.
+func WithAsyncTTL(ttl time.Duration) Option {
+	return func(conf *config) {
+		conf.asyncTTL = ttl
+	}
+}
+
+// WithHooks adds hooks which will be called around handling records, see Hook.
+// Hooks get records masked by WithMasking, and records suppressed by WithDedup won't be passed to hooks.
+func WithHooks(hooks ...Hook) Option {
+	return func(conf *config) {
+		for _, hook := range hooks {
+			if hook != nil {
+				conf.hooks = append(conf.hooks, hook)
+			}
+		}
+	}
+}
+
+// WithEncryptedAttrs encrypts values of attrs having one of keys with AES-GCM.
+// Keys are matched regardless of groups, so attrs in groups will be encrypted too.
+// The encrypted value is like "id:base64" where id is returned by provider and base64 is the sealed value with nonce.
+// All values will be encrypted in string form, so use DecryptAttr to recover them if you have the key.
+// See KeyProvider.
+func WithEncryptedAttrs(provider KeyProvider, keys ...string) Option {
+	encryptor := newAttrEncryptor(provider, keys)
+
+	return func(conf *config) {
+		conf.attrReplacers = append(conf.attrReplacers, encryptor.replaceAttr)
+	}
+}
+
+// WithMasking replaces sensitive data with "***" before handling logs, so it never hits the writer.
+// A rule only having letters, digits, '_', '.' and '-' is a key, and values of attrs having the key are replaced.
+// Keys are case-insensitive and matched regardless of groups.
+// Other rules are patterns in regexp syntax, and substrings matching them in messages and string values are replaced.
+// Values like errors and structs are checked in string form, and they're replaced with the masked strings if matched.
+// Creating a logger will fail if one of patterns is invalid.
+//
+//	WithMasking("password", "token", `\d{16}`)
+func WithMasking(rules ...string) Option {
+	return func(conf *config) {
+		conf.maskRules = append(conf.maskRules, rules...)
+	}
+}
+
+// WithFilters suppresses records matching one of rules before handling them, which is useful for muting noisy components.
+// A rule matches records whose messages match the pattern, having all attrs required and none of attrs forbidden.
+// Records higher than the max level of rules are always kept, so the errors of muted components won't be lost.
+// Creating a logger will fail if one of messages is an invalid pattern.
+//
+//	WithFilters(FilterRule{Attrs: map[string]string{"component": "grpc"}, MaxLevel: slog.LevelInfo})
+func WithFilters(rules ...FilterRule) Option {
+	return func(conf *config) {
+		conf.filterRules = append(conf.filterRules, rules...)
+	}
+}
+
+// WithBlobOffload stores values of attrs bigger than threshold bytes in store, which keeps logs small while big payloads are still accessible.
+// The values are replaced with groups of attrs named "blob_ref", "blob_hash" and "blob_size", which are the reference, sha256 and size of values.
+// Only strings, bytes and json.RawMessage are offloaded, and values are kept if storing them failed, see defaults.HandleError.
+// Values are stored when handling records, so using WithAsync moves storing to the background.
+//
+//	WithBlobOffload(NewFileBlobStore("./blobs"), 64*1024)
+func WithBlobOffload(store BlobStore, threshold int) Option {
+	return func(conf *config) {
+		conf.blobStore = store
+		conf.blobThreshold = threshold
+	}
+}
+
+// WithDedup collapses records having the same level, message and attrs in window, which stops error storms flooding the disk.
+// Attrs added by With and WithGroup are included, but the pid is ignored so records from restarted processes are still the same.
+// The first record after window carries the count of records suppressed before it in an attr named "repeated".
+// If no same records come, the count is logged in a summary record after window or when closing the logger.
+// The state will be saved to stateFile if it's not empty, so a crash-looping process won't emit the same records again and again.
+// The state is saved when the first record comes, at most once per second after that, and when closing the logger.
+// Notice that the window is shared by all loggers derived by With and WithGroup.
+func WithDedup(window time.Duration, stateFile string) Option {
+	return func(conf *config) {
+		conf.dedupWindow = window
+		conf.dedupFile = stateFile
+	}
+}
+
+// WithHashedAttrs replaces values of attrs having one of keys with their HMAC-SHA256 hashes in hex.
+// The same value always has the same hash with the same secret, so logs are still joinable without exposing the real value.
+// Keys are matched regardless of groups, so attrs in groups will be hashed too.
+func WithHashedAttrs(secret []byte, keys ...string) Option {
+	hasher := newAttrHasher(secret, keys)
+
+	return func(conf *config) {
+		conf.attrReplacers = append(conf.attrReplacers, hasher.replaceAttr)
+	}
+}
+
+// WithCoercedAttrs coerces values of attrs to the kinds in kinds, whose key is the key of attrs.
+// It keeps a key having the same type in all logs, which prevents mapping conflicts in systems like elasticsearch.
+// Only slog.KindString, slog.KindInt64, slog.KindFloat64 and slog.KindBool are supported,
+// and durations will be coerced to numbers in nanoseconds.
+// A value which can't be coerced will be kept and the error will be passed to defaults.HandleError.
+// Keys are matched regardless of groups, so attrs in groups will be coerced too.
+func WithCoercedAttrs(kinds map[string]slog.Kind) Option {
+	coercer := newAttrCoercer(kinds)
+
+	return func(conf *config) {
+		conf.attrReplacers = append(conf.attrReplacers, coercer.replaceAttr)
+	}
+}
+
 // WithSource sets withSource=true to config.
 // All logs will carry their caller information like file and line.
 func WithSource() Option {
@@ -205,6 +641,38 @@ func WithSource() Option {
 	}
 }
 
+// WithCallerDepth adds delta to the depth of caller, which is useful if you wrap logger in your own package.
+// For example, if you call logger in a helper function, use WithCallerDepth(1) so the source of logs is the caller of helper.
+// See Logger.WithCallerSkip.
+func WithCallerDepth(delta int) Option {
+	return func(conf *config) {
+		conf.callerSkip = delta
+	}
+}
+
+// WithSourceRoot trims root from the file of sources, so sources are relative to root like "internal/db/repo.go".
+// An empty root means the root of the main module, which is the directory of go.mod found from the working directory up,
+// or the path of the main module if binaries are built with -trimpath.
+// It works only if WithSource is set.
+func WithSourceRoot(root string) Option {
+	return func(conf *config) {
+		if root == "" {
+			conf.sourceRoots = moduleRoots()
+			return
+		}
+
+		conf.sourceRoots = []string{filepath.ToSlash(root)}
+	}
+}
+
+// WithSourceSegments keeps only the last segments of the file of sources, like "db/repo.go" if segments is 2.
+// It works only if WithSource is set, and it's applied after WithSourceRoot.
+func WithSourceSegments(segments int) Option {
+	return func(conf *config) {
+		conf.sourceSegments = segments
+	}
+}
+
 // WithPID sets withPID=true to config.
 // All logs will carry the process id.
 func WithPID() Option {
@@ -213,6 +681,103 @@ func WithPID() Option {
 	}
 }
 
+// WithTraceAttrs sets withTrace=true to config.
+// Logs with a context from StartSpan or ContextWithTraceParent will carry the trace id and span id.
+// It's a lightweight fallback for correlating logs across services before a full tracer is adopted,
+// and TraceParent returns the header value you should set to outgoing requests.
+func WithTraceAttrs() Option {
+	return func(conf *config) {
+		conf.withTrace = true
+	}
+}
+
+// WithContextExtractor adds extractors which extract attrs from context to logs with context,
+// like TraceContext, FatalContext and the context methods of the logger returned by Logger.Slog.
+// It's useful for values like request id, tenant id and user id stored in context, so they don't need to be passed as args.
+// Attrs are appended to the record in order of extractors, and they're in the groups added by WithGroup like other args.
+// It can be used many times and extractors will be appended in order. See ContextExtractor and ExtractContextKeys.
+func WithContextExtractor(extractors ...ContextExtractor) Option {
+	return func(conf *config) {
+		for _, extractor := range extractors {
+			if extractor != nil {
+				conf.contextExtractors = append(conf.contextExtractors, extractor)
+			}
+		}
+	}
+}
+
+// WithContextMerge sets mergeContext=true to config.
+// NewContext will merge the logger with the logger already in context instead of replacing it,
+// which means the new logger inherits attrs and groups added to the parent logger by With and WithGroup.
+// It's useful for nested middlewares adding attrs to loggers in contexts.
+// Both loggers should be created with this option, or there is nothing to merge.
+func WithContextMerge() Option {
+	return func(conf *config) {
+		conf.mergeContext = true
+	}
+}
+
+// WithDepthWarning sets maxDepth to config.
+// A warning will be logged once if a logger is derived by With or WithGroup more than maxDepth times,
+// which is usually caused by deriving a logger from a derived logger repeatedly, like in every request.
+// The warning carries the caller of With or WithGroup, so you can find where the logger is derived.
+func WithDepthWarning(maxDepth int) Option {
+	return func(conf *config) {
+		conf.maxDepth = maxDepth
+	}
+}
+
+// WithWriteStats enables stats of writes and flushes in the buffer or batch writer.
+// The stats include the distribution of write sizes and the reasons of flushes, so you can tune buffer size or batch size from data.
+// See Logger.WriteStats, WithBuffer and WithBatch.
+func WithWriteStats() Option {
+	return func(conf *config) {
+		conf.writeStats = true
+	}
+}
+
+// WithStats enables stats of records like the counts of written and dropped records.
+// It's useful for alerting when logs are lost silently, like when the queue of async handler is full.
+// See Logger.Stats and WithAsync.
+func WithStats() Option {
+	return func(conf *config) {
+		conf.stats = new(recordStats)
+	}
+}
+
+// WithHandleTimeStats enables stats like WithStats and measures the time of handling each record, including encoding and writing it.
+// It's useful for quantifying the overhead of logging and tuning buffer size or batch size with data.
+// Notice that measuring time costs a little for each record, see Stats.HandleTime and Stats.HandleTimePercentile.
+func WithHandleTimeStats() Option {
+	return func(conf *config) {
+		if conf.stats == nil {
+			conf.stats = new(recordStats)
+		}
+
+		conf.handleTimeStats = true
+	}
+}
+
+// WithStrictOrder forces a strict global order of records across goroutines, which is only for tests.
+// Records are stamped with sequences when logging and held in memory, and they're handled in order of sequences when syncing or closing.
+// So integration tests asserting on the order of logs won't be flaky under the race detector, even with WithAsync or WithParallelBatch.
+// Notice that records won't be written until syncing or closing the logger, and memory grows with records held, so don't use it in production.
+func WithStrictOrder() Option {
+	return func(conf *config) {
+		conf.strictOrder = true
+	}
+}
+
+// WithDryRun creates everything of the logger like opening files and parsing options, and then closes them without keeping resources.
+// NewLoggerGracefully returns an error if anything is misconfigured, so you can fail fast on startup with a flag like "--check-logging".
+// The logger returned is closed, so logs will be discarded and ErrLoggerClosed will be passed to defaults.HandleError once.
+// Notice that files will be created if they don't exist, because creating them is the way to check they're writable.
+func WithDryRun() Option {
+	return func(conf *config) {
+		conf.dryRun = true
+	}
+}
+
 // WithSyncTimer sets a sync timer duration to config.
 // It will call Sync() so it depends on the handler used by logger.
 func WithSyncTimer(d time.Duration) Option {
@@ -18,16 +18,107 @@ import (
 	"bytes"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/FishGoddess/logit/defaults"
 	"github.com/FishGoddess/logit/handler"
 	"github.com/FishGoddess/logit/rotate"
 	"github.com/FishGoddess/logit/writer"
 )
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevel$
+func TestWithLevel(t *testing.T) {
+	conf := &config{level: slog.LevelError}
+	WithLevel(slog.LevelInfo + 2).applyTo(conf)
+
+	if conf.level != slog.LevelInfo+2 {
+		t.Fatalf("conf.level %+v != slog.LevelInfo+2", conf.level)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLeveler$
+func TestWithLeveler(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+
+	conf := &config{leveler: nil}
+	WithLeveler(levelVar).applyTo(conf)
+
+	if conf.leveler != levelVar {
+		t.Fatalf("conf.leveler %v != levelVar %v", conf.leveler, levelVar)
+	}
+
+	logger1 := NewLogger(WithWriter(bytes.NewBuffer(nil)), WithLeveler(levelVar), WithErrorLevel())
+	logger2 := NewLogger(WithWriter(bytes.NewBuffer(nil)), WithLeveler(levelVar))
+
+	levelVar.Set(slog.LevelWarn)
+
+	for _, logger := range []*Logger{logger1, logger2} {
+		if logger.InfoEnabled() || !logger.WarnEnabled() {
+			t.Fatal("logger level is wrong")
+		}
+	}
+
+	levelVar.Set(slog.LevelDebug)
+
+	for _, logger := range []*Logger{logger1, logger2} {
+		if !logger.DebugEnabled() {
+			t.Fatal("logger should enable debug")
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevelVar$
+func TestWithLevelVar(t *testing.T) {
+	levelVar := NewLevelVar(slog.LevelInfo)
+
+	conf := &config{leveler: nil}
+	WithLevelVar(nil).applyTo(conf)
+
+	if conf.leveler != nil {
+		t.Fatalf("conf.leveler %v should be nil", conf.leveler)
+	}
+
+	WithLevelVar(levelVar).applyTo(conf)
+
+	if conf.leveler != levelVar {
+		t.Fatalf("conf.leveler %v != levelVar %v", conf.leveler, levelVar)
+	}
+
+	loggers := make([]*Logger, 0, 4)
+	for i := 0; i < cap(loggers); i++ {
+		loggers = append(loggers, NewLogger(WithWriter(bytes.NewBuffer(nil)), WithLevelVar(levelVar)))
+	}
+
+	for _, logger := range loggers {
+		if logger.DebugEnabled() || !logger.InfoEnabled() {
+			t.Fatal("logger level is wrong")
+		}
+	}
+
+	levelVar.Set(slog.LevelError)
+
+	for _, logger := range loggers {
+		if logger.WarnEnabled() || !logger.ErrorEnabled() {
+			t.Fatal("logger level is wrong")
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTraceLevel$
+func TestWithTraceLevel(t *testing.T) {
+	conf := &config{level: slog.LevelError}
+	WithTraceLevel().applyTo(conf)
+
+	if conf.level != LevelTrace {
+		t.Fatalf("conf.level %+v != LevelTrace", conf.level)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDebugLevel$
 func TestWithDebugLevel(t *testing.T) {
 	conf := &config{level: slog.LevelError}
@@ -83,6 +174,78 @@ func TestWithWriter(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithWriters$
+func TestWithWriters(t *testing.T) {
+	conf := &config{newWriter: nil}
+
+	buffer1 := bytes.NewBuffer(make([]byte, 0, 64))
+	buffer2 := bytes.NewBuffer(make([]byte, 0, 64))
+	WithWriters(buffer1, buffer2).applyTo(conf)
+
+	w, err := conf.newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := w.(*writer.FanoutWriter); !ok {
+		t.Fatalf("w %T isn't *writer.FanoutWriter", w)
+	}
+
+	logger := NewLogger(WithWriters(buffer1, buffer2), WithBatch(16))
+	logger.Info("msg")
+
+	if buffer1.Len() != 0 || buffer2.Len() != 0 {
+		t.Fatalf("buffer1 %s or buffer2 %s should be empty before syncing", buffer1, buffer2)
+	}
+
+	logger.Close()
+
+	if !strings.HasSuffix(buffer1.String(), "¦ msg\n") || buffer1.String() != buffer2.String() {
+		t.Fatalf("buffer1 %s or buffer2 %s is wrong", buffer1, buffer2)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithErrorWriter$
+func TestWithErrorWriter(t *testing.T) {
+	conf := &config{errorWriter: nil}
+
+	errorBuffer := bytes.NewBuffer(make([]byte, 0, 64))
+	WithErrorWriter(errorBuffer).applyTo(conf)
+
+	if conf.errorWriter != errorBuffer {
+		t.Fatalf("conf.errorWriter %v != errorBuffer %v", conf.errorWriter, errorBuffer)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 64))
+	logger := NewLogger(WithWriter(buffer), WithErrorWriter(errorBuffer), WithBatch(16))
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	if buffer.Len() != 0 || errorBuffer.Len() != 0 {
+		t.Fatalf("buffer %s or errorBuffer %s should be empty before syncing", buffer, errorBuffer)
+	}
+
+	logger.Close()
+
+	if got := buffer.String(); !strings.HasSuffix(got, "¦ info\n") || strings.Count(got, "\n") != 1 {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if got := errorBuffer.String(); !strings.Contains(got, "¦ warn\n") || !strings.HasSuffix(got, "¦ error\n") {
+		t.Fatalf("got %s of errorBuffer is wrong", got)
+	}
+
+	logger = NewLogger(WithWriter(buffer), WithErrorWriter(os.Stderr))
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stderr.Stat(); err != nil {
+		t.Fatalf("stderr is closed: %+v", err)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStdout$
 func TestWithStdout(t *testing.T) {
 	conf := &config{newWriter: nil}
@@ -185,6 +348,88 @@ func TestWithRotateFile(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevelFiles$
+func TestWithLevelFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	conf := &config{levelWriters: nil}
+	WithLevelFiles(path).applyTo(conf)
+
+	if len(conf.levelWriters) != 4 {
+		t.Fatalf("len(conf.levelWriters) %d != 4", len(conf.levelWriters))
+	}
+
+	logger := NewLogger(WithLevelFiles(path), WithHandler("text"), WithLevel(LevelTrace))
+	logger.Trace("trace")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"app.debug.log": {"msg=trace"},
+		"app.info.log":  {"msg=info"},
+		"app.warn.log":  {"msg=warn"},
+		"app.error.log": {"msg=error"},
+	}
+
+	for name, wants := range testCases {
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := string(data)
+		if strings.Count(got, "\n") != len(wants) {
+			t.Fatalf("got %s of %s is wrong", got, name)
+		}
+
+		for _, want := range wants {
+			if !strings.Contains(got, want) {
+				t.Fatalf("got %s of %s doesn't contain %s", got, name, want)
+			}
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithJournal$
+func TestWithJournal(t *testing.T) {
+	journalSocket := defaults.JournalSocket
+	defer func() {
+		defaults.JournalSocket = journalSocket
+	}()
+
+	defaults.JournalSocket = filepath.Join(t.TempDir(), "journal.socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: defaults.JournalSocket, Net: "unixgram"})
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer conn.Close()
+
+	logger := NewLogger(WithJournal())
+	defer logger.Close()
+
+	logger.Info("msg", "user_id", 123)
+
+	datagram := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	n, err := conn.Read(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "MESSAGE=msg\nPRIORITY=6\nLEVEL=INFO\nUSER_ID=123\n"
+	if got := string(datagram[:n]); got != want {
+		t.Fatalf("got %q != want %q", got, want)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBuffer$
 func TestWithBuffer(t *testing.T) {
 	conf := &config{wrapWriter: nil}
@@ -229,6 +474,76 @@ func TestWithBuffer(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAtomicWrites$
+func TestWithAtomicWrites(t *testing.T) {
+	conf := &config{atomicWriter: nil}
+	WithAtomicWrites(writer.PipeBuf).applyTo(conf)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 128))
+	w := conf.atomicWriter(buffer)
+
+	if _, ok := w.(*writer.AtomicWriter); !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	if _, err := w.Write([]byte("log\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "log\n"; buffer.String() != want {
+		t.Fatalf("buffer.String() %q != want %q", buffer.String(), want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithFraming$
+func TestWithFraming(t *testing.T) {
+	conf := &config{frameWriter: nil}
+	WithFraming(writer.LengthPrefixFramer()).applyTo(conf)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 128))
+	w := conf.frameWriter(buffer)
+
+	if _, ok := w.(*writer.FrameWriter); !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	if _, err := w.Write([]byte("log\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "\x00\x00\x00\x04log\n"; buffer.String() != want {
+		t.Fatalf("buffer.String() %q != want %q", buffer.String(), want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithParallelBatch$
+func TestWithParallelBatch(t *testing.T) {
+	conf := &config{wrapWriter: nil}
+	WithParallelBatch(4, 2).applyTo(conf)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 256))
+	w := conf.wrapWriter(writer.Atomic(buffer, writer.PipeBuf))
+
+	bw, ok := w.(*writer.BatchWriter)
+	if !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := bw.Write([]byte("abc\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := bw.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(buffer.String(), "abc\n"); got != 10 {
+		t.Fatalf("got %d != 10", got)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBatch$
 func TestWithBatch(t *testing.T) {
 	conf := &config{wrapWriter: nil}
@@ -325,6 +640,180 @@ func TestWithJsonHandler(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithConsoleHandler$
+func TestWithConsoleHandler(t *testing.T) {
+	conf := &config{handler: ""}
+	WithConsoleHandler().applyTo(conf)
+
+	if conf.handler != handler.Console {
+		t.Fatal("conf.handler is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAutoHandler$
+func TestWithAutoHandler(t *testing.T) {
+	conf := &config{handler: ""}
+	WithAutoHandler().applyTo(conf)
+
+	if conf.handler != handler.Auto {
+		t.Fatal("conf.handler is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithConsoleLevelColors$
+func TestWithConsoleLevelColors(t *testing.T) {
+	colors := map[slog.Level]handler.Color{slog.LevelInfo: handler.Color256(28)}
+
+	conf := &config{consoleColors: nil}
+	WithConsoleLevelColors(colors).applyTo(conf)
+
+	if fmt.Sprint(conf.consoleColors) != fmt.Sprint(colors) {
+		t.Fatalf("conf.consoleColors %+v is wrong", conf.consoleColors)
+	}
+
+	// Buffer isn't a terminal so logs shouldn't be colored.
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithConsoleHandler(), WithConsoleLevelColors(colors))
+	logger.Info("msg")
+
+	if got := buffer.String(); !strings.Contains(got, " INFO  msg\n") || strings.Contains(got, "\033[") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithConsoleLevelIcons$
+func TestWithConsoleLevelIcons(t *testing.T) {
+	icons := map[slog.Level]string{slog.LevelError: "❌"}
+
+	conf := &config{consoleIcons: nil}
+	WithConsoleLevelIcons(icons).applyTo(conf)
+
+	if fmt.Sprint(conf.consoleIcons) != fmt.Sprint(icons) {
+		t.Fatalf("conf.consoleIcons %+v is wrong", conf.consoleIcons)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithConsoleHandler(), WithConsoleLevelIcons(icons))
+	logger.Info("info")
+	logger.Error("error")
+
+	if got := buffer.String(); !strings.Contains(got, " INFO  info\n") || !strings.Contains(got, " ❌ ERROR error\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	// Icons are only written by console handler.
+	buffer.Reset()
+	logger = NewLogger(WithWriter(buffer), WithConsoleLevelIcons(icons))
+	logger.Error("error")
+
+	if got := buffer.String(); strings.Contains(got, "❌") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithConsoleColumns$
+func TestWithConsoleColumns(t *testing.T) {
+	conf := &config{consoleLevelWidth: 0, consoleLoggerWidth: 0, consoleMessageWidth: 0}
+	WithConsoleColumns(4, 8, 16).applyTo(conf)
+
+	if conf.consoleLevelWidth != 4 || conf.consoleLoggerWidth != 8 || conf.consoleMessageWidth != 16 {
+		t.Fatalf("conf %d %d %d is wrong", conf.consoleLevelWidth, conf.consoleLoggerWidth, conf.consoleMessageWidth)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithConsoleHandler(), WithConsoleColumns(4, 8, 16))
+	logger.Named("app").Named("db").Info("msg", "k", "v")
+	logger.Error("msg")
+
+	got := buffer.String()
+	if !strings.Contains(got, " INFO app.db   msg              k=v\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+
+	if !strings.Contains(got, " ERRO          msg\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithColor$
+func TestWithColor(t *testing.T) {
+	conf := &config{color: false}
+	WithColor().applyTo(conf)
+
+	if !conf.color {
+		t.Fatal("conf.color is wrong")
+	}
+
+	// Buffer isn't a terminal so logs shouldn't be colored.
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithColor())
+	logger.Info("msg", "k", "v")
+
+	if got := buffer.String(); strings.Contains(got, "\033[") {
+		t.Fatalf("got %q is colored", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithEscapeMode$
+func TestWithEscapeMode(t *testing.T) {
+	conf := &config{escape: ""}
+	WithEscapeMode(handler.EscapeNone).applyTo(conf)
+
+	if conf.escape != handler.EscapeNone {
+		t.Fatalf("conf.escape %s is wrong", conf.escape)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithEscapeMode(handler.EscapeFull))
+	logger.Info("msg", "k", "a b")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `k="a b"`+"\n") {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithCSVHandler$
+func TestWithCSVHandler(t *testing.T) {
+	conf := &config{handler: ""}
+	WithCSVHandler("level", "msg").applyTo(conf)
+
+	if conf.handler != handler.CSV {
+		t.Fatal("conf.handler is wrong")
+	}
+
+	if fmt.Sprint(conf.csvColumns) != "[level msg]" {
+		t.Fatalf("conf.csvColumns %+v is wrong", conf.csvColumns)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithCSVHandler("level", "msg", "user.id"))
+	logger.WithGroup("user").Info("csv", "id", 123)
+
+	if got := buffer.String(); got != "level,msg,user.id\nINFO,csv,123\n" {
+		t.Fatalf("got %q is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithProtobufHandler$
+func TestWithProtobufHandler(t *testing.T) {
+	conf := &config{handler: ""}
+	WithProtobufHandler().applyTo(conf)
+
+	if conf.handler != handler.Protobuf {
+		t.Fatal("conf.handler is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithJournalExportHandler$
+func TestWithJournalExportHandler(t *testing.T) {
+	conf := &config{handler: ""}
+	WithJournalExportHandler().applyTo(conf)
+
+	if conf.handler != handler.JournalExport {
+		t.Fatal("conf.handler is wrong")
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithReplaceAttr$
 func TestWithReplaceAttr(t *testing.T) {
 	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr { return slog.Attr{} }
@@ -337,6 +826,58 @@ func TestWithReplaceAttr(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTimeFormat$
+func TestWithTimeFormat(t *testing.T) {
+	conf := &config{timeFormat: ""}
+	WithTimeFormat(TimeFormatUnixMilli).applyTo(conf)
+
+	if conf.timeFormat != TimeFormatUnixMilli {
+		t.Fatalf("conf.timeFormat %s != %s", conf.timeFormat, TimeFormatUnixMilli)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithUTC$
+func TestWithUTC(t *testing.T) {
+	conf := &config{utc: false}
+	WithUTC().applyTo(conf)
+
+	if !conf.utc {
+		t.Fatal("conf.utc is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithEncryptedAttrsOption$
+func TestWithEncryptedAttrsOption(t *testing.T) {
+	provider := func() (string, []byte, error) { return "", nil, nil }
+
+	conf := &config{attrReplacers: nil}
+	WithEncryptedAttrs(provider, "k").applyTo(conf)
+
+	if len(conf.attrReplacers) != 1 {
+		t.Fatalf("len(conf.attrReplacers) %d != 1", len(conf.attrReplacers))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHashedAttrsOption$
+func TestWithHashedAttrsOption(t *testing.T) {
+	conf := &config{attrReplacers: nil}
+	WithHashedAttrs([]byte("secret"), "k").applyTo(conf)
+
+	if len(conf.attrReplacers) != 1 {
+		t.Fatalf("len(conf.attrReplacers) %d != 1", len(conf.attrReplacers))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithCoercedAttrsOption$
+func TestWithCoercedAttrsOption(t *testing.T) {
+	conf := &config{attrReplacers: nil}
+	WithCoercedAttrs(map[string]slog.Kind{"k": slog.KindInt64}).applyTo(conf)
+
+	if len(conf.attrReplacers) != 1 {
+		t.Fatalf("len(conf.attrReplacers) %d != 1", len(conf.attrReplacers))
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSource$
 func TestWithSource(t *testing.T) {
 	conf := &config{withSource: false}
@@ -347,6 +888,32 @@ func TestWithSource(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSourceRoot$
+func TestWithSourceRoot(t *testing.T) {
+	conf := &config{sourceRoots: nil}
+	WithSourceRoot("/home/app").applyTo(conf)
+
+	if fmt.Sprint(conf.sourceRoots) != "[/home/app]" {
+		t.Fatalf("conf.sourceRoots %+v is wrong", conf.sourceRoots)
+	}
+
+	WithSourceRoot("").applyTo(conf)
+
+	if fmt.Sprint(conf.sourceRoots) != fmt.Sprint(moduleRoots()) {
+		t.Fatalf("conf.sourceRoots %+v is wrong", conf.sourceRoots)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSourceSegments$
+func TestWithSourceSegments(t *testing.T) {
+	conf := &config{sourceSegments: 0}
+	WithSourceSegments(2).applyTo(conf)
+
+	if conf.sourceSegments != 2 {
+		t.Fatalf("conf.sourceSegments %d is wrong", conf.sourceSegments)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithPID$
 func TestWithPID(t *testing.T) {
 	conf := &config{withPID: false}
@@ -357,6 +924,275 @@ func TestWithPID(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTraceAttrs$
+func TestWithTraceAttrs(t *testing.T) {
+	conf := &config{withTrace: false}
+	WithTraceAttrs().applyTo(conf)
+
+	if !conf.withTrace {
+		t.Fatal("conf.withTrace is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithContextMerge$
+func TestWithContextMerge(t *testing.T) {
+	conf := &config{mergeContext: false}
+	WithContextMerge().applyTo(conf)
+
+	if !conf.mergeContext {
+		t.Fatal("conf.mergeContext is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithCallerDepth$
+func TestWithCallerDepth(t *testing.T) {
+	conf := &config{callerSkip: 0}
+	WithCallerDepth(2).applyTo(conf)
+
+	if conf.callerSkip != 2 {
+		t.Fatalf("conf.callerSkip %d != 2", conf.callerSkip)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDepthWarning$
+func TestWithDepthWarning(t *testing.T) {
+	conf := &config{maxDepth: 0}
+	WithDepthWarning(16).applyTo(conf)
+
+	if conf.maxDepth != 16 {
+		t.Fatalf("conf.maxDepth %d != 16", conf.maxDepth)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAttrHooks$
+func TestWithAttrHooks(t *testing.T) {
+	conf := &config{attrReplacers: nil}
+	WithAttrHooks(DropAttrs("password"), nil).applyTo(conf)
+	WithAttrHooks(RenameAttrs(map[string]string{"uid": "user_id"})).applyTo(conf)
+
+	if len(conf.attrReplacers) != 2 {
+		t.Fatalf("len(conf.attrReplacers) %d is wrong", len(conf.attrReplacers))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithFilters$
+func TestWithFilters(t *testing.T) {
+	conf := &config{filterRules: nil}
+
+	rule1 := FilterRule{Message: "^health"}
+	rule2 := FilterRule{Attrs: map[string]string{"component": "noisy"}}
+	WithFilters(rule1).applyTo(conf)
+	WithFilters(rule2).applyTo(conf)
+
+	if len(conf.filterRules) != 2 || conf.filterRules[0].Message != rule1.Message || conf.filterRules[1].Attrs["component"] != "noisy" {
+		t.Fatalf("conf.filterRules %+v is wrong", conf.filterRules)
+	}
+
+	if _, err := NewLoggerGracefully(WithFilters(FilterRule{Message: "[a-"})); err == nil {
+		t.Fatal("creating a logger with invalid filter should fail")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBlobOffload$
+func TestWithBlobOffload(t *testing.T) {
+	store := NewFileBlobStore(t.TempDir())
+
+	conf := &config{blobStore: nil, blobThreshold: 0}
+	WithBlobOffload(store, 1024).applyTo(conf)
+
+	if conf.blobStore != store {
+		t.Fatalf("conf.blobStore %v != store %v", conf.blobStore, store)
+	}
+
+	if conf.blobThreshold != 1024 {
+		t.Fatalf("conf.blobThreshold %d != 1024", conf.blobThreshold)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDedup$
+func TestWithDedup(t *testing.T) {
+	conf := &config{dedupWindow: 0, dedupFile: ""}
+	WithDedup(time.Minute, "dedup.state").applyTo(conf)
+
+	if conf.dedupWindow != time.Minute {
+		t.Fatalf("conf.dedupWindow %s is wrong", conf.dedupWindow)
+	}
+
+	if conf.dedupFile != "dedup.state" {
+		t.Fatalf("conf.dedupFile %s is wrong", conf.dedupFile)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSampling$
+func TestWithSampling(t *testing.T) {
+	conf := &config{sampleInitial: 0, sampleThereafter: 0, sampleInterval: 0}
+	WithSampling(100, 10, time.Second).applyTo(conf)
+
+	if conf.sampleInitial != 100 || conf.sampleThereafter != 10 || conf.sampleInterval != time.Second {
+		t.Fatalf("conf %+v is wrong", conf)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithRateLimit$
+func TestWithRateLimit(t *testing.T) {
+	conf := &config{rateLimit: 0, rateBurst: 0}
+	WithRateLimit(1000, 100).applyTo(conf)
+
+	if conf.rateLimit != 1000 || conf.rateBurst != 100 {
+		t.Fatalf("conf %+v is wrong", conf)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithInterning$
+func TestWithInterning(t *testing.T) {
+	conf := &config{internSize: 0}
+	WithInterning(1024).applyTo(conf)
+
+	if conf.internSize != 1024 {
+		t.Fatalf("conf.internSize %d is wrong", conf.internSize)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithInterning(16))
+	logger.Info("msg", "status", "ok")
+	logger.Info("msg", "status", "ok")
+
+	if got := strings.Count(buffer.String(), "¦ msg ¦ status=ok\n"); got != 2 {
+		t.Fatalf("got %d != 2", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAsync$
+func TestWithAsync(t *testing.T) {
+	conf := &config{asyncSize: 0, asyncPolicy: ""}
+	WithAsync(1024, DropPolicyOldest).applyTo(conf)
+
+	if conf.asyncSize != 1024 || conf.asyncPolicy != DropPolicyOldest {
+		t.Fatalf("conf %+v is wrong", conf)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAsyncTTL$
+func TestWithAsyncTTL(t *testing.T) {
+	conf := &config{asyncTTL: 0}
+	WithAsyncTTL(time.Minute).applyTo(conf)
+
+	if conf.asyncTTL != time.Minute {
+		t.Fatal("conf.asyncTTL is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStaticAttrs$
+func TestWithStaticAttrs(t *testing.T) {
+	conf := &config{staticAttrs: nil}
+	WithStaticAttrs(slog.String("env", "prod")).applyTo(conf)
+	WithStaticAttrs(slog.String("region", "cn")).applyTo(conf)
+
+	want := []slog.Attr{slog.String("env", "prod"), slog.String("region", "cn")}
+	if fmt.Sprintf("%+v", conf.staticAttrs) != fmt.Sprintf("%+v", want) {
+		t.Fatalf("conf.staticAttrs %+v != want %+v", conf.staticAttrs, want)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithStaticAttrs(want...))
+	logger.WithGroup("group").Info("msg", "key", "value")
+
+	if got := buffer.String(); !strings.HasSuffix(got, `"msg":"msg","env":"prod","region":"cn","group":{"key":"value"}}`+"\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithContextExtractor$
+func TestWithContextExtractor(t *testing.T) {
+	conf := &config{contextExtractors: nil}
+	WithContextExtractor(ExtractContextKeys("request_id"), nil).applyTo(conf)
+	WithContextExtractor(ExtractContextKeys("user_id")).applyTo(conf)
+
+	if len(conf.contextExtractors) != 2 {
+		t.Fatalf("len(conf.contextExtractors) %d != 2", len(conf.contextExtractors))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHooks$
+func TestWithHooks(t *testing.T) {
+	conf := &config{hooks: nil}
+	WithHooks(failedHook{}, nil).applyTo(conf)
+	WithHooks(failedHook{}).applyTo(conf)
+
+	if len(conf.hooks) != 2 {
+		t.Fatalf("len(conf.hooks) %d is wrong", len(conf.hooks))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithMasking$
+func TestWithMasking(t *testing.T) {
+	conf := &config{maskRules: nil}
+	WithMasking("password", `\d{16}`).applyTo(conf)
+	WithMasking("token").applyTo(conf)
+
+	if fmt.Sprint(conf.maskRules) != `[password \d{16} token]` {
+		t.Fatalf("conf.maskRules %+v is wrong", conf.maskRules)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithWriteStats$
+func TestWithWriteStats(t *testing.T) {
+	conf := &config{writeStats: false}
+	WithWriteStats().applyTo(conf)
+
+	if !conf.writeStats {
+		t.Fatal("conf.writeStats is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStats$
+func TestWithStats(t *testing.T) {
+	conf := &config{stats: nil}
+	WithStats().applyTo(conf)
+
+	if conf.stats == nil {
+		t.Fatal("conf.stats is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHandleTimeStats$
+func TestWithHandleTimeStats(t *testing.T) {
+	conf := &config{stats: nil, handleTimeStats: false}
+	WithHandleTimeStats().applyTo(conf)
+
+	if conf.stats == nil || !conf.handleTimeStats {
+		t.Fatalf("conf.stats %v or conf.handleTimeStats %v is wrong", conf.stats, conf.handleTimeStats)
+	}
+
+	stats := conf.stats
+	WithHandleTimeStats().applyTo(conf)
+
+	if conf.stats != stats {
+		t.Fatalf("conf.stats %p != stats %p", conf.stats, stats)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStrictOrder$
+func TestWithStrictOrder(t *testing.T) {
+	conf := &config{strictOrder: false}
+	WithStrictOrder().applyTo(conf)
+
+	if !conf.strictOrder {
+		t.Fatal("conf.strictOrder is wrong")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDryRun$
+func TestWithDryRun(t *testing.T) {
+	conf := &config{dryRun: false}
+	WithDryRun().applyTo(conf)
+
+	if !conf.dryRun {
+		t.Fatal("conf.dryRun is wrong")
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSyncTimer$
 func TestWithSyncTimer(t *testing.T) {
 	conf := &config{syncTimer: 0}
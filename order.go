@@ -0,0 +1,105 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+type orderedRecord struct {
+	handler slog.Handler
+	ctx     context.Context
+	record  slog.Record
+}
+
+// orderer holds records in order of sequences and handles them in the same order when flushing.
+// The sequence of a record is its position in records, so it's stamped and appended atomically.
+type orderer struct {
+	records []orderedRecord
+	lock    sync.Mutex
+
+	// flushLock serializes flushing, so records from different flushes won't be interleaved.
+	flushLock sync.Mutex
+}
+
+func newOrderer() *orderer {
+	return new(orderer)
+}
+
+func (o *orderer) handle(ctx context.Context, handler slog.Handler, record slog.Record) {
+	// The record is handled later, so clone it in case the caller modifies it.
+	ordered := orderedRecord{handler: handler, ctx: ctx, record: record.Clone()}
+
+	o.lock.Lock()
+	o.records = append(o.records, ordered)
+	o.lock.Unlock()
+}
+
+// flush handles all records held in order of sequences.
+func (o *orderer) flush() error {
+	o.flushLock.Lock()
+	defer o.flushLock.Unlock()
+
+	o.lock.Lock()
+	records := o.records
+	o.records = nil
+	o.lock.Unlock()
+
+	var errs []error
+	for _, ordered := range records {
+		if err := ordered.handler.Handle(ordered.ctx, ordered.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sync handles all records held in order of sequences.
+func (o *orderer) Sync() error {
+	return o.flush()
+}
+
+// Close handles all records held in order of sequences.
+func (o *orderer) Close() error {
+	return o.flush()
+}
+
+// orderedHandler stamps records with sequences and holds them in orderer until flushing.
+type orderedHandler struct {
+	slog.Handler
+
+	orderer *orderer
+}
+
+func newOrderedHandler(handler slog.Handler, orderer *orderer) slog.Handler {
+	return orderedHandler{Handler: handler, orderer: orderer}
+}
+
+func (oh orderedHandler) Handle(ctx context.Context, record slog.Record) error {
+	oh.orderer.handle(ctx, oh.Handler, record)
+	return nil
+}
+
+func (oh orderedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newOrderedHandler(oh.Handler.WithAttrs(attrs), oh.orderer)
+}
+
+func (oh orderedHandler) WithGroup(name string) slog.Handler {
+	return newOrderedHandler(oh.Handler.WithGroup(name), oh.orderer)
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestOrderer$
+func TestOrderer(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := slog.NewTextHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey || attr.Key == slog.LevelKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}})
+
+	orderer := newOrderer()
+	for i := 1; i <= 3; i++ {
+		orderer.handle(context.Background(), handler, slog.NewRecord(time.Now(), slog.LevelInfo, fmt.Sprint(i), 0))
+	}
+
+	if err := orderer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "msg=1\nmsg=2\nmsg=3\n"
+	if got := buffer.String(); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	if len(orderer.records) != 0 {
+		t.Fatalf("len(orderer.records) %d != 0", len(orderer.records))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestOrdererConcurrently$
+func TestOrdererConcurrently(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 64*1024))
+	handler := slog.NewTextHandler(buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey || attr.Key == slog.LevelKey {
+			return slog.Attr{}
+		}
+
+		return attr
+	}})
+
+	const goroutines = 16
+	const n = 200
+
+	// Each record carries the count of records handled before logging it, and handled records are saved in order.
+	// So the records handled before logging one should be written before it.
+	orderer := newOrderer()
+	handled := make([]int, goroutines*n)
+
+	var count atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < n; i++ {
+				id := g*n + i
+				before := count.Load()
+
+				record := slog.NewRecord(time.Now(), slog.LevelInfo, strconv.Itoa(id), 0)
+				record.AddAttrs(slog.Int64("before", before))
+				orderer.handle(context.Background(), handler, record)
+
+				handled[count.Add(1)-1] = id
+			}
+		}(g)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for count.Load() < goroutines*n {
+			if err := orderer.Sync(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	wg.Wait()
+	<-done
+
+	if err := orderer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != goroutines*n {
+		t.Fatalf("len(lines) %d != %d", len(lines), goroutines*n)
+	}
+
+	positions := make([]int, goroutines*n)
+	befores := make([]int, goroutines*n)
+	for pos, line := range lines {
+		var id, before int
+		if _, err := fmt.Sscanf(line, "msg=%d before=%d", &id, &before); err != nil {
+			t.Fatalf("line %d %s is wrong: %+v", pos, line, err)
+		}
+
+		positions[id] = pos
+		befores[id] = before
+	}
+
+	// maxPositions[k] is the max position of the first k records handled.
+	maxPositions := make([]int, goroutines*n+1)
+	maxPositions[0] = -1
+
+	for k, id := range handled {
+		maxPositions[k+1] = max(maxPositions[k], positions[id])
+	}
+
+	for id, pos := range positions {
+		if got := maxPositions[befores[id]]; got >= pos {
+			t.Fatalf("record %d at %d is written before records handled before it at %d", id, pos, got)
+		}
+
+		if id%n > 0 && positions[id-1] >= pos {
+			t.Fatalf("record %d at %d is written before record %d at %d", id, pos, id-1, positions[id-1])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestOrderedHandler$
+func TestOrderedHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithAsync(16, DropPolicyBlock), WithStrictOrder())
+
+	const n = 10
+	signals := make([]chan struct{}, n+1)
+	for i := range signals {
+		signals[i] = make(chan struct{})
+	}
+
+	// Goroutines are started in reverse order, but each one logs after the previous one.
+	var wg sync.WaitGroup
+	for i := n - 1; i >= 0; i-- {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			<-signals[i]
+			logger.With("i", i).Info("msg")
+			close(signals[i+1])
+		}(i)
+	}
+
+	close(signals[0])
+	wg.Wait()
+
+	if got := buffer.String(); got != "" {
+		t.Fatalf("got %s should be empty before syncing", got)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != n {
+		t.Fatalf("len(lines) %d != n %d", len(lines), n)
+	}
+
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("¦ msg ¦ i=%d", i)) {
+			t.Fatalf("line %d %s is wrong", i, line)
+		}
+	}
+}
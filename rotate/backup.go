@@ -30,6 +30,7 @@ const (
 type backup struct {
 	path string
 	t    time.Time
+	size uint64
 }
 
 func (b backup) before(t time.Time) bool {
@@ -49,8 +50,8 @@ func backupPrefixAndExt(path string) (prefix string, ext string) {
 	return prefix, ext
 }
 
-func backupPath(path string, timeFormat string) string {
-	now := defaults.CurrentTime()
+func backupPath(path string, timeFormat string, location *time.Location) string {
+	now := defaults.CurrentTime().In(location)
 	name, ext := backupPrefixAndExt(path)
 
 	if timeFormat != "" {
@@ -60,11 +61,11 @@ func backupPath(path string, timeFormat string) string {
 	return name + strconv.FormatInt(now.Unix(), 10) + ext
 }
 
-func parseBackupTime(filename string, prefix string, ext string, timeFormat string) (time.Time, error) {
+func parseBackupTime(filename string, prefix string, ext string, timeFormat string, location *time.Location) (time.Time, error) {
 	ts := filename[len(prefix) : len(filename)-len(ext)]
 
 	if timeFormat != "" {
-		return time.Parse(timeFormat, ts)
+		return time.ParseInLocation(timeFormat, ts, location)
 	}
 
 	seconds, err := strconv.ParseInt(ts, 10, 64)
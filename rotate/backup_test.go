@@ -74,11 +74,17 @@ func TestBackupPath(t *testing.T) {
 		return time.Unix(1, 0).In(time.UTC)
 	}
 
-	path := backupPath("test.log", "20060102150405")
+	path := backupPath("test.log", "20060102150405", time.UTC)
 	want := "test.19700101000001.log"
 	if path != want {
 		t.Fatalf("path %s != want %s", path, want)
 	}
+
+	path = backupPath("test.log", "20060102150405", time.FixedZone("CST", 8*60*60))
+	want = "test.19700101080001.log"
+	if path != want {
+		t.Fatalf("path %s != want %s", path, want)
+	}
 }
 
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestParseBackupTime$
@@ -92,7 +98,16 @@ func TestParseBackupTime(t *testing.T) {
 	ext := ".log"
 	timeFormat := "20060102150405"
 
-	backupTime, err := parseBackupTime(filename, prefix, ext, timeFormat)
+	backupTime, err := parseBackupTime(filename, prefix, ext, timeFormat, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if backupTime.Unix() != 1 {
+		t.Fatalf("backupTime.Unix() %d != 1", backupTime.Unix())
+	}
+
+	backupTime, err = parseBackupTime("test.19700101080001.log", prefix, ext, timeFormat, time.FixedZone("CST", 8*60*60))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,155 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"os"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// ReasonMaxBackups means the backup exceeds max backups.
+	ReasonMaxBackups = "max_backups"
+
+	// ReasonMaxAge means the backup reaches max age.
+	ReasonMaxAge = "max_age"
+
+	// ReasonMaxTotalSize means the total size of backups exceeds max total size.
+	ReasonMaxTotalSize = "max_total_size"
+)
+
+// Backup is a backup of file.
+type Backup struct {
+	// Path is the path of backup.
+	Path string
+
+	// Time is the time of backup parsed from its path.
+	Time time.Time
+
+	// Size is the size of backup.
+	Size uint64
+
+	// Reason is the reason why the backup is removed, which is empty if the backup is kept.
+	Reason string
+}
+
+// CleanReport reports what will be kept and removed in backups.
+type CleanReport struct {
+	// Kept is the backups kept, sorted from the oldest to the newest.
+	Kept []Backup
+
+	// Removed is the backups removed, sorted from the oldest to the newest.
+	Removed []Backup
+}
+
+// staleReasons returns the reasons of backups which should be removed.
+// Backups should be sorted from the oldest to the newest, and an empty reason means the backup should be kept.
+// A backup will be removed if it breaks any limit, which means a backup is kept only if it satisfies all limits.
+// Max backups is checked first, then max age, then max total size which only counts the backups kept.
+// Finally, the newest backups in retainAtLeast are kept whatever the limits are.
+func (c *config) staleReasons(backups []backup, now time.Time) []string {
+	reasons := make([]string, len(backups))
+
+	if c.maxBackups > 0 {
+		exceeds := len(backups) - int(c.maxBackups)
+		for i := 0; i < exceeds; i++ {
+			reasons[i] = ReasonMaxBackups
+		}
+	}
+
+	if c.maxAge > 0 {
+		deadline := now.Add(-c.maxAge)
+
+		for i, backup := range backups {
+			if !backup.before(deadline) {
+				break
+			}
+
+			if reasons[i] == "" {
+				reasons[i] = ReasonMaxAge
+			}
+		}
+	}
+
+	if c.maxTotalSize > 0 {
+		totalSize := uint64(0)
+
+		for i := len(backups) - 1; i >= 0; i-- {
+			if reasons[i] != "" {
+				continue
+			}
+
+			totalSize += backups[i].size
+			if totalSize > c.maxTotalSize {
+				reasons[i] = ReasonMaxTotalSize
+			}
+		}
+	}
+
+	retained := len(backups) - int(c.retainAtLeast)
+	for i := len(backups) - 1; i >= 0 && i >= retained; i-- {
+		reasons[i] = ""
+	}
+
+	return reasons
+}
+
+func (f *File) planClean() (CleanReport, error) {
+	backups, err := f.listBackups()
+	if err != nil {
+		return CleanReport{}, err
+	}
+
+	report := CleanReport{}
+	reasons := f.staleReasons(backups, defaults.CurrentTime())
+
+	for i, backup := range backups {
+		b := Backup{
+			Path:   backup.path,
+			Time:   backup.t,
+			Size:   backup.size,
+			Reason: reasons[i],
+		}
+
+		if b.Reason == "" {
+			report.Kept = append(report.Kept, b)
+		} else {
+			report.Removed = append(report.Removed, b)
+		}
+	}
+
+	return report, nil
+}
+
+func (f *File) clean() {
+	report, err := f.planClean()
+	if err != nil {
+		return
+	}
+
+	for _, backup := range report.Removed {
+		os.Remove(backup.Path)
+	}
+}
+
+// PlanClean reports which backups of path will be kept or removed with opts, without removing anything.
+// It's useful for validating a combination of max age, max backups and max total size before using it.
+// The file in path won't be created or opened, so it's safe to run on a directory of a running program.
+func PlanClean(path string, opts ...Option) (CleanReport, error) {
+	f := newFile(path, opts)
+	return f.planClean()
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigStaleReasons$
+func TestConfigStaleReasons(t *testing.T) {
+	now := time.Unix(100, 0)
+
+	backups := []backup{
+		{path: "1", t: time.Unix(10, 0), size: 10},
+		{path: "2", t: time.Unix(20, 0), size: 10},
+		{path: "3", t: time.Unix(70, 0), size: 10},
+		{path: "4", t: time.Unix(80, 0), size: 10},
+		{path: "5", t: time.Unix(90, 0), size: 10},
+	}
+
+	testCases := []struct {
+		config config
+		want   []string
+	}{
+		{
+			config: config{},
+			want:   []string{"", "", "", "", ""},
+		},
+		{
+			config: config{maxBackups: 4},
+			want:   []string{ReasonMaxBackups, "", "", "", ""},
+		},
+		{
+			config: config{maxBackups: 4, maxAge: 50 * time.Second},
+			want:   []string{ReasonMaxBackups, ReasonMaxAge, "", "", ""},
+		},
+		{
+			config: config{maxTotalSize: 25},
+			want:   []string{ReasonMaxTotalSize, ReasonMaxTotalSize, ReasonMaxTotalSize, "", ""},
+		},
+		{
+			config: config{maxBackups: 4, maxAge: 50 * time.Second, maxTotalSize: 20},
+			want:   []string{ReasonMaxBackups, ReasonMaxAge, ReasonMaxTotalSize, "", ""},
+		},
+		{
+			config: config{maxAge: time.Second, retainAtLeast: 2},
+			want:   []string{ReasonMaxAge, ReasonMaxAge, ReasonMaxAge, "", ""},
+		},
+		{
+			config: config{maxBackups: 1, maxAge: time.Second, maxTotalSize: 5, retainAtLeast: 3},
+			want:   []string{ReasonMaxBackups, ReasonMaxBackups, "", "", ""},
+		},
+		{
+			config: config{maxBackups: 2, retainAtLeast: 4},
+			want:   []string{ReasonMaxBackups, "", "", "", ""},
+		},
+		{
+			config: config{maxAge: time.Second, retainAtLeast: 10},
+			want:   []string{"", "", "", "", ""},
+		},
+	}
+
+	for i, testCase := range testCases {
+		reasons := testCase.config.staleReasons(backups, now)
+
+		if strings.Join(reasons, ",") != strings.Join(testCase.want, ",") {
+			t.Fatalf("case %d: reasons %v != want %v", i, reasons, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestPlanClean$
+func TestPlanClean(t *testing.T) {
+	defaults.CurrentTime = func() time.Time {
+		return time.Unix(100, 0)
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	files := map[string]string{
+		"test.log":    "current",
+		"test.10.log": "1234567890",
+		"test.20.log": "1234567890",
+		"test.70.log": "1234567890",
+		"test.80.log": "1234567890",
+		"test.90.log": "1234567890",
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := PlanClean(path, WithTimeFormat(""), WithMaxBackups(4), WithMaxAge(50*time.Second), WithMaxTotalSize(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantKept := []string{"test.80.log", "test.90.log"}
+	if len(report.Kept) != len(wantKept) {
+		t.Fatalf("len(report.Kept) %d != len(wantKept) %d", len(report.Kept), len(wantKept))
+	}
+
+	for i, backup := range report.Kept {
+		if backup.Path != filepath.Join(dir, wantKept[i]) || backup.Size != 10 || backup.Reason != "" {
+			t.Fatalf("backup %+v is wrong", backup)
+		}
+
+		if wantTime := time.Unix(int64(80+10*i), 0); !backup.Time.Equal(wantTime) {
+			t.Fatalf("backup.Time %v != wantTime %v", backup.Time, wantTime)
+		}
+	}
+
+	wantRemoved := []string{"test.10.log", "test.20.log", "test.70.log"}
+	wantReasons := []string{ReasonMaxBackups, ReasonMaxAge, ReasonMaxTotalSize}
+	if len(report.Removed) != len(wantRemoved) {
+		t.Fatalf("len(report.Removed) %d != len(wantRemoved) %d", len(report.Removed), len(wantRemoved))
+	}
+
+	for i, backup := range report.Removed {
+		if backup.Path != filepath.Join(dir, wantRemoved[i]) || backup.Reason != wantReasons[i] {
+			t.Fatalf("backup %+v is wrong", backup)
+		}
+	}
+
+	if count := countFiles(dir); count != len(files) {
+		t.Fatalf("count %d != len(files) %d", count, len(files))
+	}
+}
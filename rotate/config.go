@@ -27,6 +27,10 @@ type config struct {
 	// timeFormat is the time format of backup path.
 	timeFormat string
 
+	// timeLocation is the location of the time in backup path.
+	// It's UTC by default so backups of hosts in different zones are sorted correctly by their names.
+	timeLocation *time.Location
+
 	// maxSize is the max size of file.
 	// If size of data in one write is bigger than maxSize, then file will rotate and write it,
 	// which means file and its backup may be bigger than maxSize in size.
@@ -38,13 +42,22 @@ type config struct {
 
 	// maxBackups is the max count of backups.
 	maxBackups uint32
+
+	// maxTotalSize is the max total size of backups.
+	// The oldest backups will be cleaned if total size exceeds it, and 0 means no limit.
+	maxTotalSize uint64
+
+	// retainAtLeast is the min count of backups which will be kept.
+	// The newest retainAtLeast backups won't be cleaned even if they exceed maxBackups, reach maxAge or exceed maxTotalSize.
+	retainAtLeast uint32
 }
 
 func newDefaultConfig() config {
 	return config{
-		timeFormat: "20060102150405",
-		maxSize:    128 * MB,
-		maxAge:     60 * Day,
-		maxBackups: 90,
+		timeFormat:   "20060102150405",
+		timeLocation: time.UTC,
+		maxSize:      128 * MB,
+		maxAge:       60 * Day,
+		maxBackups:   90,
 	}
 }
@@ -16,6 +16,7 @@ package rotate
 
 import (
 	"testing"
+	"time"
 )
 
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestNewDefaultConfig$
@@ -23,10 +24,11 @@ func TestNewDefaultConfig(t *testing.T) {
 	c := newDefaultConfig()
 
 	want := config{
-		timeFormat: "20060102150405",
-		maxSize:    128 * MB,
-		maxAge:     60 * Day,
-		maxBackups: 90,
+		timeFormat:   "20060102150405",
+		timeLocation: time.UTC,
+		maxSize:      128 * MB,
+		maxAge:       60 * Day,
+		maxBackups:   90,
 	}
 
 	if c != want {
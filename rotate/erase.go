@@ -0,0 +1,235 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// EraseFunc decides how to erase a record, which is a line in file without the line break.
+// Return nil to remove the record, a masked record to replace it or the record itself to keep it.
+// The record must not be modified in place, and it's reused after the function returns.
+type EraseFunc func(record []byte) []byte
+
+// EraseReport reports what has been erased in a file.
+type EraseReport struct {
+	// Path is the path of file.
+	Path string
+
+	// Records is the count of records in file before erasing.
+	Records uint64
+
+	// Removed is the count of records removed.
+	Removed uint64
+
+	// Masked is the count of records replaced with masked records.
+	Masked uint64
+}
+
+// Changed returns if the file has been rewritten.
+func (er EraseReport) Changed() bool {
+	return er.Removed > 0 || er.Masked > 0
+}
+
+func eraseRecords(reader *bufio.Reader, writer io.Writer, erase EraseFunc, report *EraseReport) error {
+	for {
+		line, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// The record is too long to fit in the buffer, so read the remained part.
+			// The line should be cloned before reading because the buffer will be overwritten.
+			line = bytes.Clone(line)
+
+			var remained []byte
+			remained, err = reader.ReadBytes('\n')
+			line = append(line, remained...)
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if len(line) == 0 {
+			return nil
+		}
+
+		record := bytes.TrimSuffix(line, []byte{'\n'})
+		hasLineBreak := len(record) < len(line)
+		report.Records++
+
+		erased := erase(record)
+		if erased == nil {
+			report.Removed++
+		} else {
+			if !bytes.Equal(erased, record) {
+				report.Masked++
+			}
+
+			if _, werr := writer.Write(erased); werr != nil {
+				return werr
+			}
+
+			if hasLineBreak {
+				if _, werr := writer.Write([]byte{'\n'}); werr != nil {
+					return werr
+				}
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// eraseFile erases records in path and replaces it only if something has been erased.
+func eraseFile(path string, erase EraseFunc) (report EraseReport, err error) {
+	report.Path = path
+
+	file, err := os.Open(path)
+	if err != nil {
+		return report, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return report, err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".erasing-*")
+	if err != nil {
+		return report, err
+	}
+
+	tempPath := tempFile.Name()
+	renamed := false
+
+	defer func() {
+		if !renamed {
+			tempFile.Close()
+			os.Remove(tempPath)
+		}
+	}()
+
+	writer := bufio.NewWriter(tempFile)
+	if err = eraseRecords(bufio.NewReader(file), writer, erase, &report); err != nil {
+		return report, err
+	}
+
+	if !report.Changed() {
+		return report, nil
+	}
+
+	if err = writer.Flush(); err != nil {
+		return report, err
+	}
+
+	if err = tempFile.Chmod(info.Mode().Perm()); err != nil {
+		return report, err
+	}
+
+	if err = tempFile.Sync(); err != nil {
+		return report, err
+	}
+
+	if err = tempFile.Close(); err != nil {
+		return report, err
+	}
+
+	if err = os.Rename(tempPath, path); err != nil {
+		return report, err
+	}
+
+	renamed = true
+	return report, nil
+}
+
+// Erase erases records in all backups and the current file with erase, which is useful for right-to-erasure requests.
+// Files are erased from the oldest backup to the current file, and the order of records is preserved.
+// A file will be rewritten only if some records in it are removed or masked.
+// It returns reports of all files erased, including the ones unchanged, so you can keep them for auditing.
+// Writing is blocked during erasing the current file.
+func (f *File) Erase(erase EraseFunc) ([]EraseReport, error) {
+	reports := make([]EraseReport, 0, 16)
+	erased := make(map[string]struct{}, 16)
+
+	eraseBackups := func() error {
+		backups, err := f.listBackups()
+		if err != nil {
+			return err
+		}
+
+		for _, backup := range backups {
+			if _, ok := erased[backup.path]; ok {
+				continue
+			}
+
+			report, err := eraseFile(backup.path, erase)
+			if os.IsNotExist(err) {
+				// The backup has been cleaned.
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			reports = append(reports, report)
+			erased[backup.path] = struct{}{}
+		}
+
+		return nil
+	}
+
+	if err := eraseBackups(); err != nil {
+		return reports, err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// The file may rotate before locking, so erase the new backups.
+	if err := eraseBackups(); err != nil {
+		return reports, err
+	}
+
+	if err := f.file.Sync(); err != nil {
+		return reports, err
+	}
+
+	report, err := eraseFile(f.path, erase)
+	if err != nil {
+		return reports, err
+	}
+
+	reports = append(reports, report)
+
+	if !report.Changed() {
+		return reports, nil
+	}
+
+	// The file has been replaced so we should reopen it.
+	if err = f.file.Close(); err != nil {
+		return reports, err
+	}
+
+	return reports, f.openNewFile()
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+func eraseUser(record []byte) []byte {
+	if bytes.Contains(record, []byte("user_id=1 ")) {
+		return nil
+	}
+
+	if bytes.Contains(record, []byte("email=fish")) {
+		return bytes.ReplaceAll(record, []byte("email=fish"), []byte("email=***"))
+	}
+
+	return record
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEraseRecords$
+func TestEraseRecords(t *testing.T) {
+	long := strings.Repeat("x", 8192)
+	data := "a user_id=1 \nb email=fish\nc " + long + "\nd user_id=1 \ne"
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	report := EraseReport{}
+
+	reader := bufio.NewReaderSize(strings.NewReader(data), 16)
+	if err := eraseRecords(reader, buffer, eraseUser, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "b email=***\nc " + long + "\ne"
+	if buffer.String() != want {
+		t.Fatalf("buffer %q != want %q", buffer.String(), want)
+	}
+
+	if report.Records != 5 || report.Removed != 2 || report.Masked != 1 {
+		t.Fatalf("report %+v is wrong", report)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFileErase$
+func TestFileErase(t *testing.T) {
+	second := int64(0)
+	defaults.CurrentTime = func() time.Time {
+		second++
+		return time.Unix(second, 0)
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	f, err := New(path, WithMaxSize(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	f.timeFormat = ""
+	records := []string{
+		"1 user_id=1 \n", "2 user_id=2 \n", "3 email=fish\n",
+		"4 user_id=2 \n", "5 user_id=1 \n", "6 user_id=3 \n",
+	}
+
+	for _, record := range records {
+		if _, err = f.Write([]byte(record)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := f.Erase(eraseUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reports) != 3 {
+		t.Fatalf("len(reports) %d != 3", len(reports))
+	}
+
+	if reports[len(reports)-1].Path != path {
+		t.Fatalf("reports[last].Path %s != %s", reports[len(reports)-1].Path, path)
+	}
+
+	var content strings.Builder
+	var removed, masked uint64
+
+	for _, report := range reports {
+		read, err := os.ReadFile(report.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content.Write(read)
+		removed += report.Removed
+		masked += report.Masked
+	}
+
+	want := "2 user_id=2 \n3 email=***\n4 user_id=2 \n6 user_id=3 \n"
+	if content.String() != want {
+		t.Fatalf("content %q != want %q", content.String(), want)
+	}
+
+	if removed != 2 || masked != 1 {
+		t.Fatalf("removed %d or masked %d is wrong", removed, masked)
+	}
+
+	if _, err = f.Write([]byte("7\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(string(read), "6 user_id=3 \n7\n") {
+		t.Fatalf("read %q is wrong", read)
+	}
+
+	if files := countFiles(dir); files != 3 {
+		t.Fatalf("files %d != 3", files)
+	}
+}
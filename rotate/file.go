@@ -15,6 +15,7 @@
 package rotate
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -37,6 +38,9 @@ type File struct {
 	file *os.File
 	ch   chan struct{}
 
+	// closed is true if file has been closed, so closing it again won't close ch twice.
+	closed bool
+
 	lock sync.Mutex
 }
 
@@ -109,15 +113,22 @@ func (f *File) listBackups() ([]backup, error) {
 			continue
 		}
 
-		t, err := parseBackupTime(filename, prefix, ext, f.timeFormat)
+		t, err := parseBackupTime(filename, prefix, ext, f.timeFormat, f.timeLocation)
 		if err != nil {
 			defaults.HandleError("rotate.parseBackupTime", err)
 			continue
 		}
 
+		info, err := file.Info()
+		if err != nil {
+			// The backup may have been removed.
+			continue
+		}
+
 		backups = append(backups, backup{
 			path: filepath.Join(dir, filename),
 			t:    t,
+			size: uint64(info.Size()),
 		})
 	}
 
@@ -125,42 +136,6 @@ func (f *File) listBackups() ([]backup, error) {
 	return backups, nil
 }
 
-func (f *File) removeStaleBackups(backups []backup) {
-	staleBackups := make(map[string]struct{}, 16)
-
-	if f.maxBackups > 0 {
-		exceeds := len(backups) - int(f.maxBackups)
-		for i := 0; i < exceeds; i++ {
-			staleBackups[backups[i].path] = struct{}{}
-		}
-	}
-
-	if f.maxAge > 0 {
-		deadline := defaults.CurrentTime().Add(-f.maxAge)
-
-		for _, backup := range backups {
-			if !backup.before(deadline) {
-				break
-			}
-
-			staleBackups[backup.path] = struct{}{}
-		}
-	}
-
-	for backup := range staleBackups {
-		os.Remove(backup)
-	}
-}
-
-func (f *File) clean() {
-	backups, err := f.listBackups()
-	if err != nil {
-		return
-	}
-
-	f.removeStaleBackups(backups)
-}
-
 func (f *File) runCleanTask() {
 	for range f.ch {
 		f.clean()
@@ -192,7 +167,7 @@ func (f *File) openNewFile() error {
 }
 
 func (f *File) nextBackupPath() (string, error) {
-	backupPath := backupPath(f.path, f.timeFormat)
+	backupPath := backupPath(f.path, f.timeFormat, f.timeLocation)
 
 	_, err := os.Stat(backupPath)
 	if os.IsNotExist(err) {
@@ -244,10 +219,15 @@ func (f *File) rotate() error {
 }
 
 // Write writes len(p) bytes from p to the underlying data stream.
+// Writing a closed file returns os.ErrClosed.
 func (f *File) Write(p []byte) (n int, err error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
 	writeSize := uint64(len(p))
 	if f.size+writeSize > f.maxSize {
 		// Ignore rotating error so this p won't be discarded.
@@ -271,14 +251,20 @@ func (f *File) Sync() error {
 }
 
 // Close closes file and returns an error if failed.
+// The file will be closed even if syncing failed, and closing a closed file does nothing.
 func (f *File) Close() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
-	if err := f.file.Sync(); err != nil {
-		return err
+	if f.closed {
+		return nil
 	}
 
+	f.closed = true
+
+	syncErr := f.file.Sync()
 	close(f.ch)
-	return f.file.Close()
+	closeErr := f.file.Close()
+
+	return errors.Join(syncErr, closeErr)
 }
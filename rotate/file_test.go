@@ -15,6 +15,7 @@
 package rotate
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -185,7 +186,7 @@ func TestFileRotate(t *testing.T) {
 
 	var bs []byte
 	for second > 1 {
-		backup := backupPath(path, f.timeFormat)
+		backup := backupPath(path, f.timeFormat, f.timeLocation)
 		if bs, err = os.ReadFile(backup); err != nil {
 			t.Fatal(err)
 		}
@@ -197,3 +198,27 @@ func TestFileRotate(t *testing.T) {
 		t.Fatalf("string(read) %s != '!!!bursttest'", read)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFileCloseTwice$
+func TestFileCloseTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	f, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writing a closed file shouldn't panic even if it needs rotating.
+	f.maxSize = 1
+	if _, err = f.Write([]byte("test")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+}
@@ -45,3 +45,39 @@ func WithMaxBackups(backups uint32) Option {
 		c.maxBackups = backups
 	}
 }
+
+// WithMaxTotalSize sets max total size of backups to config.
+func WithMaxTotalSize(size uint64) Option {
+	return func(c *config) {
+		c.maxTotalSize = size
+	}
+}
+
+// WithRetainAtLeast sets the min count of backups to keep to config.
+// The newest backups in this count are always kept, which has a higher priority than max backups, max age and max total size.
+// So it keeps more backups than max backups if it's greater than max backups.
+func WithRetainAtLeast(backups uint32) Option {
+	return func(c *config) {
+		c.retainAtLeast = backups
+	}
+}
+
+// WithTimeFormat sets time format of backup path to config.
+// The format is a layout like "20060102150405", and an empty format means using unix seconds.
+func WithTimeFormat(format string) Option {
+	return func(c *config) {
+		c.timeFormat = format
+	}
+}
+
+// WithTimeLocation sets the location of the time in backup path to config.
+// It's UTC by default, and a nil location means UTC.
+func WithTimeLocation(location *time.Location) Option {
+	return func(c *config) {
+		if location == nil {
+			location = time.UTC
+		}
+
+		c.timeLocation = location
+	}
+}
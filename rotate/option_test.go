@@ -63,3 +63,65 @@ func TestWithMaxBackups(t *testing.T) {
 		t.Fatalf("c %+v != want %+v", c, want)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTimeFormat$
+func TestWithTimeFormat(t *testing.T) {
+	c := newDefaultConfig()
+	c.timeFormat = ""
+
+	WithTimeFormat("2006-01-02T15-04-05").apply(&c)
+
+	want := newDefaultConfig()
+	want.timeFormat = "2006-01-02T15-04-05"
+
+	if c != want {
+		t.Fatalf("c %+v != want %+v", c, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithTimeLocation$
+func TestWithTimeLocation(t *testing.T) {
+	location := time.FixedZone("CST", 8*60*60)
+
+	c := newDefaultConfig()
+	WithTimeLocation(location).apply(&c)
+
+	want := newDefaultConfig()
+	want.timeLocation = location
+
+	if c != want {
+		t.Fatalf("c %+v != want %+v", c, want)
+	}
+
+	WithTimeLocation(nil).apply(&c)
+
+	if c.timeLocation != time.UTC {
+		t.Fatalf("c.timeLocation %+v != time.UTC", c.timeLocation)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithMaxTotalSize$
+func TestWithMaxTotalSize(t *testing.T) {
+	c := newDefaultConfig()
+	WithMaxTotalSize(1024).apply(&c)
+
+	want := newDefaultConfig()
+	want.maxTotalSize = 1024
+
+	if c != want {
+		t.Fatalf("c %+v != want %+v", c, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithRetainAtLeast$
+func TestWithRetainAtLeast(t *testing.T) {
+	c := newDefaultConfig()
+	WithRetainAtLeast(3).apply(&c)
+
+	want := newDefaultConfig()
+	want.retainAtLeast = 3
+
+	if c != want {
+		t.Fatalf("c %+v != want %+v", c, want)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+const (
+	// tailChunkSize is the size of chunks read backward from the end of file.
+	tailChunkSize = 4 * 1024
+)
+
+// tail reads the last n lines of file with size from the end, and returns them in order without line breaks.
+// Chunks are read backward and joined once at the end, so data won't be copied again for every chunk.
+func tail(file io.ReaderAt, size int64, n int) ([][]byte, error) {
+	var chunks [][]byte
+
+	offset := size
+	total := 0
+	lines := 0
+
+	// Read one more line break than n because the last one may end the last record.
+	for offset > 0 && lines <= n {
+		readSize := int64(tailChunkSize)
+		if offset < readSize {
+			readSize = offset
+		}
+
+		offset -= readSize
+
+		chunk := make([]byte, readSize)
+		read, err := file.ReadAt(chunk, offset)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		chunk = chunk[:read]
+		chunks = append(chunks, chunk)
+		total += read
+		lines += bytes.Count(chunk, []byte{'\n'})
+	}
+
+	data := make([]byte, 0, total)
+	for i := len(chunks) - 1; i >= 0; i-- {
+		data = append(data, chunks[i]...)
+	}
+
+	// The last line break ends the last record, so it shouldn't split out an empty record.
+	data = bytes.TrimSuffix(data, []byte{'\n'})
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	records := bytes.Split(data, []byte{'\n'})
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+
+	return records, nil
+}
+
+// Tail returns the last n records of the current file, and records are separated by line breaks.
+// It reads the file backward from the end, so it's efficient even if the file is large.
+// Records in backups won't be returned, and it returns os.ErrClosed if file is closed.
+func (f *File) Tail(n int) ([][]byte, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	file, size, err := f.openTail()
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return tail(file, size, n)
+}
+
+// openTail opens the current file for reading and returns its size.
+// The lock is held only for opening, and the file is read without the lock, so writers won't wait for reading.
+// Files are only appended, so data before the size won't change even if the file is written or rotated later.
+func (f *File) openTail() (*os.File, int64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return nil, 0, os.ErrClosed
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	return file, info.Size(), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTail$
+func TestTail(t *testing.T) {
+	var builder strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&builder, "record %d\n", i)
+	}
+
+	data := []byte(builder.String())
+
+	testCases := []struct {
+		data []byte
+		n    int
+		want []string
+	}{
+		{data: data, n: 3, want: []string{"record 997", "record 998", "record 999"}},
+		{data: data, n: 1, want: []string{"record 999"}},
+		{data: data[:len(data)-1], n: 2, want: []string{"record 998", "record 999"}},
+		{data: []byte("a\nb\n"), n: 5, want: []string{"a", "b"}},
+		{data: []byte("\n\na\n"), n: 2, want: []string{"", "a"}},
+		{data: nil, n: 5, want: nil},
+	}
+
+	for _, testCase := range testCases {
+		records, err := tail(bytes.NewReader(testCase.data), int64(len(testCase.data)), testCase.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([]string, 0, len(records))
+		for _, record := range records {
+			got = append(got, string(record))
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(testCase.want) {
+			t.Fatalf("got %q != want %q", got, testCase.want)
+		}
+	}
+
+	records, err := tail(bytes.NewReader(data), int64(len(data)), 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 500 || string(records[0]) != "record 500" {
+		t.Fatalf("records %d %q is wrong", len(records), records[0])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFileTail$
+func TestFileTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	f, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if records, err := f.Tail(3); err != nil || len(records) != 0 {
+		t.Fatalf("records %q or err %+v is wrong", records, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(f, "record %d\n", i)
+	}
+
+	records, err := f.Tail(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 || string(records[0]) != "record 8" || string(records[1]) != "record 9" {
+		t.Fatalf("records %q is wrong", records)
+	}
+
+	if records, err = f.Tail(0); err != nil || records != nil {
+		t.Fatalf("records %q or err %+v is wrong", records, err)
+	}
+
+	f.Close()
+
+	if _, err = f.Tail(1); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFileTailConcurrently$
+func TestFileTailConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	f, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 2000; i++ {
+			fmt.Fprintf(f, "record %d\n", i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		records, err := f.Tail(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, record := range records {
+			if !strings.HasPrefix(string(record), "record ") {
+				t.Fatalf("record %q is wrong", record)
+			}
+		}
+	}
+
+	wg.Wait()
+
+	records, err := f.Tail(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || string(records[0]) != "record 1999" {
+		t.Fatalf("records %q is wrong", records)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/FishGoddess/logit/handler"
+	"github.com/FishGoddess/logit/writer"
+)
+
+// route is the writer of records not lower than level, see routeHandler.
+type route struct {
+	level  slog.Level
+	writer io.Writer
+}
+
+// levelWriter creates the writer of records not lower than level, see WithLevelFiles.
+type levelWriter struct {
+	level     slog.Level
+	newWriter func() (io.Writer, error)
+}
+
+// levelFilePath returns the path of file of level, which inserts the name of level before the extension of path.
+// For example, the path of file of info level is "app.info.log" if path is "app.log".
+func levelFilePath(path string, level slog.Level) string {
+	ext := filepath.Ext(path)
+	name := strings.ToLower(level.String())
+
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// routeHandler routes records to handlers by level, see WithErrorWriter and WithLevelFiles.
+// Levels are in ascending order and a record is handled by the handler of the highest level not higher than its level.
+// Records lower than all levels are handled by the first handler.
+type routeHandler struct {
+	levels   []slog.Level
+	handlers []slog.Handler
+}
+
+func newRouteHandler(levels []slog.Level, handlers []slog.Handler) slog.Handler {
+	return routeHandler{levels: levels, handlers: handlers}
+}
+
+func (rh routeHandler) route(level slog.Level) slog.Handler {
+	for i := len(rh.levels) - 1; i > 0; i-- {
+		if level >= rh.levels[i] {
+			return rh.handlers[i]
+		}
+	}
+
+	return rh.handlers[0]
+}
+
+func (rh routeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return rh.route(level).Enabled(ctx, level)
+}
+
+func (rh routeHandler) Handle(ctx context.Context, record slog.Record) error {
+	return rh.route(record.Level).Handle(ctx, record)
+}
+
+func (rh routeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(rh.handlers))
+	for _, handler := range rh.handlers {
+		handlers = append(handlers, handler.WithAttrs(attrs))
+	}
+
+	return newRouteHandler(rh.levels, handlers)
+}
+
+func (rh routeHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(rh.handlers))
+	for _, handler := range rh.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+
+	return newRouteHandler(rh.levels, handlers)
+}
+
+// LevelRoute is a child handler of RouteHandler, which handles records not lower than Level.
+type LevelRoute struct {
+	// Level is the min level of records handled by the route.
+	Level slog.Level
+
+	// NewHandler creates the child handler with the writer and the options of logger.
+	NewHandler handler.NewHandlerFunc
+
+	// Writer is the writer of the child handler, and the writer of logger is used if it's nil.
+	// It will be synced and closed with the logger except os.Stdout and os.Stderr.
+	Writer io.Writer
+}
+
+// routedHandler is the handler created by RouteHandler, which syncs and closes writers of routes.
+type routedHandler struct {
+	slog.Handler
+
+	writer *writer.FanoutWriter
+}
+
+func (rh routedHandler) Sync() error {
+	return rh.writer.Sync()
+}
+
+func (rh routedHandler) Close() error {
+	return rh.writer.Close()
+}
+
+// RouteHandler returns a handler func routing records to child handlers by ranges of levels, like console for debug and json to a file for info and above.
+// A record is handled by the route of the highest level not higher than its level, and records lower than all levels are handled by the route of the lowest level.
+// The handler func can be registered by handler.Register, so configs can use it by name.
+// Notice that routes must not be empty or a panic will happen.
+//
+//	newJsonHandler, _ := handler.Get(handler.Json)
+//	newHandler := logit.RouteHandler(
+//		logit.LevelRoute{Level: slog.LevelDebug, NewHandler: handler.NewConsoleHandler},
+//		logit.LevelRoute{Level: slog.LevelInfo, NewHandler: newJsonHandler, Writer: file},
+//	)
+//
+//	handler.Register("routed", newHandler)
+func RouteHandler(routes ...LevelRoute) handler.NewHandlerFunc {
+	if len(routes) <= 0 {
+		panic("logit: RouteHandler needs at least one route")
+	}
+
+	routes = slices.Clone(routes)
+	slices.SortStableFunc(routes, func(route1 LevelRoute, route2 LevelRoute) int {
+		return cmp.Compare(route1.Level, route2.Level)
+	})
+
+	newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		levels := make([]slog.Level, 0, len(routes))
+		handlers := make([]slog.Handler, 0, len(routes))
+		writers := make([]io.Writer, 0, len(routes))
+
+		for _, route := range routes {
+			routeWriter := w
+			if route.Writer != nil {
+				routeWriter = route.Writer
+				writers = append(writers, route.Writer)
+			}
+
+			levels = append(levels, route.Level)
+			handlers = append(handlers, route.NewHandler(routeWriter, opts))
+		}
+
+		return routedHandler{Handler: newRouteHandler(levels, handlers), writer: writer.Fanout(writers...)}
+	}
+
+	return newHandler
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+type testRouteWriter struct {
+	bytes.Buffer
+
+	synced bool
+	closed bool
+}
+
+func (trw *testRouteWriter) Sync() error {
+	trw.synced = true
+	return nil
+}
+
+func (trw *testRouteWriter) Close() error {
+	trw.closed = true
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelFilePath$
+func TestLevelFilePath(t *testing.T) {
+	testCases := []struct {
+		path  string
+		level slog.Level
+		want  string
+	}{
+		{path: "app.log", level: slog.LevelInfo, want: "app.info.log"},
+		{path: "./logs/app.log", level: slog.LevelError, want: "./logs/app.error.log"},
+		{path: "./logs/app", level: slog.LevelWarn, want: "./logs/app.warn"},
+	}
+
+	for _, testCase := range testCases {
+		if got := levelFilePath(testCase.path, testCase.level); got != testCase.want {
+			t.Fatalf("got %s != want %s", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewRouteHandler$
+func TestNewRouteHandler(t *testing.T) {
+	infoBuffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	warnBuffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	errorBuffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	levels := []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+	handlers := []slog.Handler{
+		slog.NewTextHandler(infoBuffer, opts),
+		slog.NewTextHandler(warnBuffer, opts),
+		slog.NewTextHandler(errorBuffer, opts),
+	}
+
+	handler := newRouteHandler(levels, handlers)
+
+	ctx := context.Background()
+	if handler.Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("debug shouldn't be enabled")
+	}
+
+	if !handler.Enabled(ctx, slog.LevelInfo) || !handler.Enabled(ctx, slog.LevelError) {
+		t.Fatal("info and error should be enabled")
+	}
+
+	logger := slog.New(handler).WithGroup("group").With("key", "value")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Log(ctx, slog.LevelWarn+2, "warn+2")
+	logger.Error("error")
+	logger.Log(ctx, LevelFatal, "fatal")
+
+	testCases := map[*bytes.Buffer][]string{
+		infoBuffer:  {"msg=info group.key=value"},
+		warnBuffer:  {"msg=warn group.key=value", "msg=warn+2 group.key=value"},
+		errorBuffer: {"msg=error group.key=value", "msg=fatal group.key=value"},
+	}
+
+	for buffer, wants := range testCases {
+		got := buffer.String()
+		if strings.Count(got, "\n") != len(wants) {
+			t.Fatalf("got %s is wrong", got)
+		}
+
+		for _, want := range wants {
+			if !strings.Contains(got, want) {
+				t.Fatalf("got %s doesn't contain %s", got, want)
+			}
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRouteHandler$
+func TestRouteHandler(t *testing.T) {
+	newJsonHandler, err := handler.Get(handler.Json)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routeWriter := new(testRouteWriter)
+	newHandler := RouteHandler(
+		LevelRoute{Level: slog.LevelInfo, NewHandler: newJsonHandler, Writer: routeWriter},
+		LevelRoute{Level: slog.LevelDebug, NewHandler: handler.NewConsoleHandler},
+	)
+
+	if err = handler.Register(t.Name(), newHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithHandler(t.Name()), WithDebugLevel())
+	logger.Debug("debug", "key", "value")
+	logger.Info("info", "key", "value")
+	logger.Error("error", "key", "value")
+
+	if got := buffer.String(); !strings.Contains(got, "debug") || strings.Contains(got, "info") || strings.Contains(got, "error") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	got := routeWriter.String()
+	if strings.Contains(got, "debug") || !strings.Contains(got, `"msg":"info","key":"value"`) || !strings.Contains(got, `"msg":"error","key":"value"`) {
+		t.Fatalf("got %s of routeWriter is wrong", got)
+	}
+
+	if err = logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !routeWriter.synced {
+		t.Fatal("routeWriter should be synced")
+	}
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !routeWriter.closed {
+		t.Fatal("routeWriter should be closed")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("RouteHandler without routes should panic")
+		}
+	}()
+
+	RouteHandler()
+}
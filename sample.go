@@ -0,0 +1,138 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// keyDropped is the key of the attr carrying the count of records dropped by sampling or rate limit.
+	keyDropped = "dropped"
+
+	// keySampled and keySampleRate are keys of attrs carried by records sampled after the first initial ones.
+	// Records are logged in the rate, so downstream analytics can re-weight counts by them.
+	keySampled    = "sampled"
+	keySampleRate = "sample_rate"
+)
+
+type sampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// incr increases the count in current interval and returns it.
+// The count is reset if the interval passed, and races around resetting only make the count a little inaccurate.
+func (sc *sampleCounter) incr(now int64, interval time.Duration) uint64 {
+	if now > sc.resetAt.Load() {
+		sc.count.Store(1)
+		sc.resetAt.Store(now + int64(interval))
+		return 1
+	}
+
+	return sc.count.Add(1)
+}
+
+// sampler samples records in each level like zap's sampler.
+// The first initial records in each interval are sampled, and then every thereafter record is sampled.
+type sampler struct {
+	initial    uint64
+	thereafter uint64
+	interval   time.Duration
+	counters   sync.Map
+}
+
+func newSampler(initial int, thereafter int, interval time.Duration) *sampler {
+	return &sampler{
+		initial:    uint64(max(initial, 0)),
+		thereafter: uint64(max(thereafter, 0)),
+		interval:   interval,
+	}
+}
+
+func (s *sampler) counter(level slog.Level) *sampleCounter {
+	if counter, ok := s.counters.Load(level); ok {
+		return counter.(*sampleCounter)
+	}
+
+	counter, _ := s.counters.LoadOrStore(level, new(sampleCounter))
+	return counter.(*sampleCounter)
+}
+
+// check reports whether the record in level should be sampled, the count of records dropped before it and its sample rate.
+// The rate is 1 for the first initial records in each interval and 1/thereafter for records sampled after them.
+func (s *sampler) check(level slog.Level) (bool, uint64, float64) {
+	counter := s.counter(level)
+
+	n := counter.incr(defaults.CurrentTime().UnixNano(), s.interval)
+	if n <= s.initial {
+		return true, counter.dropped.Swap(0), 1
+	}
+
+	if s.thereafter > 0 && (n-s.initial)%s.thereafter == 0 {
+		return true, counter.dropped.Swap(0), 1 / float64(s.thereafter)
+	}
+
+	counter.dropped.Add(1)
+	return false, 0, 0
+}
+
+// sampleHandler drops records not sampled and adds the dropped count to the record sampled after them.
+// Records sampled after the first initial ones also carry sampled=true and their sample rate.
+type sampleHandler struct {
+	slog.Handler
+
+	sampler *sampler
+}
+
+func newSampleHandler(handler slog.Handler, sampler *sampler) slog.Handler {
+	return sampleHandler{Handler: handler, sampler: sampler}
+}
+
+func (sh sampleHandler) Handle(ctx context.Context, record slog.Record) error {
+	sampled, dropped, rate := sh.sampler.check(record.Level)
+	if !sampled {
+		return nil
+	}
+
+	if dropped > 0 || rate < 1 {
+		record = record.Clone()
+	}
+
+	if dropped > 0 {
+		record.AddAttrs(slog.Uint64(keyDropped, dropped))
+	}
+
+	if rate < 1 {
+		record.AddAttrs(slog.Bool(keySampled, true), slog.Float64(keySampleRate, rate))
+	}
+
+	return sh.Handler.Handle(ctx, record)
+}
+
+func (sh sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newSampleHandler(sh.Handler.WithAttrs(attrs), sh.sampler)
+}
+
+func (sh sampleHandler) WithGroup(name string) slog.Handler {
+	return newSampleHandler(sh.Handler.WithGroup(name), sh.sampler)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSampler$
+func TestSampler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	s := newSampler(2, 3, time.Second)
+
+	var got []bool
+	for i := 0; i < 8; i++ {
+		sampled, _, _ := s.check(slog.LevelDebug)
+		got = append(got, sampled)
+	}
+
+	want := []bool{true, true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %+v != want %+v", got, want)
+		}
+	}
+
+	if sampled, dropped, rate := s.check(slog.LevelInfo); !sampled || dropped != 0 || rate != 1 {
+		t.Fatalf("sampled %+v or dropped %d or rate %f is wrong", sampled, dropped, rate)
+	}
+
+	s.check(slog.LevelDebug)
+	s.check(slog.LevelDebug)
+
+	if sampled, dropped, rate := s.check(slog.LevelDebug); !sampled || dropped != 2 || rate != 1.0/3 {
+		t.Fatalf("sampled %+v or dropped %d or rate %f is wrong", sampled, dropped, rate)
+	}
+
+	s.check(slog.LevelDebug)
+	now = now.Add(2 * time.Second)
+
+	if sampled, dropped, rate := s.check(slog.LevelDebug); !sampled || dropped != 1 || rate != 1 {
+		t.Fatalf("sampled %+v or dropped %d or rate %f is wrong", sampled, dropped, rate)
+	}
+
+	s = newSampler(1, 0, time.Second)
+	s.check(slog.LevelDebug)
+
+	for i := 0; i < 100; i++ {
+		if sampled, _, _ := s.check(slog.LevelDebug); sampled {
+			t.Fatal("records after initial ones should be dropped")
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSampleHandler$
+func TestSampleHandler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	defaults.CurrentTime = func() time.Time {
+		return now
+	}
+
+	defer func() {
+		defaults.CurrentTime = time.Now
+	}()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithSampling(1, 10, time.Second))
+	for i := 0; i < 20; i++ {
+		logger.With("i", i).Debug("debug")
+	}
+
+	logger.Info("info")
+
+	got := buffer.String()
+	if strings.Count(got, "sampled=true") != 1 {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if strings.Count(got, "¦ debug ¦") != 2 || strings.Count(got, "¦ info") != 1 {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if !strings.Contains(got, "¦ i=10 ¦ dropped=9 ¦ sampled=true ¦ sample_rate=0.1\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// moduleRoots returns the possible roots of source files in the main module.
+// One is the directory of go.mod found from the working directory up, which works in development.
+// The other is the path of the main module, which works if binaries are built with -trimpath.
+func moduleRoots() []string {
+	var roots []string
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				roots = append(roots, filepath.ToSlash(dir))
+				break
+			}
+
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+
+			dir = parent
+		}
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" {
+		roots = append(roots, info.Main.Path)
+	}
+
+	return roots
+}
+
+// trimSourceRoot returns file relative to the first root which is a prefix of it.
+func trimSourceRoot(file string, roots []string) string {
+	for _, root := range roots {
+		root = strings.TrimSuffix(root, "/")
+
+		if len(file) > len(root) && strings.HasPrefix(file, root) && file[len(root)] == '/' {
+			return file[len(root)+1:]
+		}
+	}
+
+	return file
+}
+
+// trimSourceSegments returns the last segments of file, like "logit/logger.go" if segments is 2.
+func trimSourceSegments(file string, segments int) string {
+	index := len(file)
+	for i := 0; i < segments; i++ {
+		index = strings.LastIndexByte(file[:index], '/')
+		if index < 0 {
+			return file
+		}
+	}
+
+	return file[index+1:]
+}
+
+// newSourceTrimmer returns a replacer trimming the file of sources with roots and segments.
+// Roots are trimmed first and segments will be ignored if it's not positive.
+func newSourceTrimmer(roots []string, segments int) func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) > 0 || attr.Key != slog.SourceKey {
+			return attr
+		}
+
+		source, ok := attr.Value.Any().(*slog.Source)
+		if !ok || source == nil {
+			return attr
+		}
+
+		// Copy the source so the original one won't be modified.
+		trimmed := *source
+		trimmed.File = trimSourceRoot(trimmed.File, roots)
+
+		if segments > 0 {
+			trimmed.File = trimSourceSegments(trimmed.File, segments)
+		}
+
+		attr.Value = slog.AnyValue(&trimmed)
+		return attr
+	}
+}
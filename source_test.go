@@ -0,0 +1,109 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestModuleRoots$
+func TestModuleRoots(t *testing.T) {
+	roots := moduleRoots()
+	if len(roots) < 2 {
+		t.Fatalf("roots %+v is wrong", roots)
+	}
+
+	if _, err := os.Stat(filepath.Join(roots[0], "go.mod")); err != nil {
+		t.Fatalf("roots[0] %s is wrong: %+v", roots[0], err)
+	}
+
+	if roots[len(roots)-1] != "github.com/FishGoddess/logit" {
+		t.Fatalf("module path %s is wrong", roots[len(roots)-1])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTrimSource$
+func TestTrimSource(t *testing.T) {
+	roots := []string{"/home/app/", "github.com/acme/app"}
+
+	rootCases := map[string]string{
+		"/home/app/internal/db/repo.go":            "internal/db/repo.go",
+		"github.com/acme/app/main.go":              "main.go",
+		"github.com/acme/application/main.go":      "github.com/acme/application/main.go",
+		"/home/other/main.go":                      "/home/other/main.go",
+		"/go/pkg/mod/github.com/lib/lib@v1/lib.go": "/go/pkg/mod/github.com/lib/lib@v1/lib.go",
+	}
+
+	for file, want := range rootCases {
+		if got := trimSourceRoot(file, roots); got != want {
+			t.Fatalf("file %s: got %s != want %s", file, got, want)
+		}
+	}
+
+	segmentCases := map[int]string{
+		1: "repo.go",
+		2: "db/repo.go",
+		4: "app/internal/db/repo.go",
+		5: "home/app/internal/db/repo.go",
+		9: "/home/app/internal/db/repo.go",
+	}
+
+	for segments, want := range segmentCases {
+		if got := trimSourceSegments("/home/app/internal/db/repo.go", segments); got != want {
+			t.Fatalf("segments %d: got %s != want %s", segments, got, want)
+		}
+	}
+
+	source := &slog.Source{File: "/home/app/internal/db/repo.go", Line: 12}
+	attr := newSourceTrimmer(roots, 2)(nil, slog.Any(slog.SourceKey, source))
+
+	trimmed, ok := attr.Value.Any().(*slog.Source)
+	if !ok || trimmed.File != "db/repo.go" || trimmed.Line != 12 {
+		t.Fatalf("trimmed %+v is wrong", trimmed)
+	}
+
+	if source.File != "/home/app/internal/db/repo.go" {
+		t.Fatalf("source %+v is modified", source)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSourceRoot$
+func TestLoggerSourceRoot(t *testing.T) {
+	handlers := []Option{WithTapeHandler(), WithTextHandler(), WithJsonHandler(), WithConsoleHandler()}
+	wants := []string{"source=source_test.go:", "source=source_test.go:", `"file":"source_test.go"`, " source_test.go:"}
+
+	for i, withHandler := range handlers {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		logger := NewLogger(withHandler, WithWriter(buffer), WithSource(), WithSourceRoot(""))
+		logger.Info("msg")
+
+		if got := buffer.String(); !strings.Contains(got, wants[i]) {
+			t.Fatalf("got %s doesn't contain %s", got, wants[i])
+		}
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithSource(), WithSourceSegments(1))
+	logger.Info("msg")
+
+	if got := buffer.String(); !strings.Contains(got, "source=source_test.go:") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/writer"
+)
+
+const (
+	// HandleTimeBuckets is the count of buckets in the distribution of handle time.
+	HandleTimeBuckets = 40
+)
+
+// Stats is the stats of records in logger, which is useful for alerting when logs are lost silently.
+// Records suppressed by WithDedup, WithSampling and WithRateLimit aren't counted as dropped,
+// because they're dropped on purpose and their counts are logged already.
+type Stats struct {
+	// Enqueued is the count of records pushed to the queue of async handler, see WithAsync.
+	Enqueued uint64
+
+	// Written is the count of records handled by the handler without errors.
+	Written uint64
+
+	// Dropped is the count of records dropped because the queue of async handler was full or the logger was closed.
+	Dropped uint64
+
+	// HandleErrors is the count of records failed to be handled, like failing to write them.
+	HandleErrors uint64
+
+	// SyncErrors is the count of failed syncs, including syncs by the sync timer and closing.
+	SyncErrors uint64
+
+	// HandleTime is the total time spent in handling records, including encoding and writing them.
+	// It's only measured with WithHandleTimeStats.
+	HandleTime time.Duration
+
+	// HandleTimes is the distribution of handle time of records.
+	// HandleTimes[0] is the count of records handled in less than 1ns and HandleTimes[i] is the count in [2^(i-1), 2^i) ns.
+	// The last bucket also counts all records handled in more time than it.
+	HandleTimes [HandleTimeBuckets]uint64
+}
+
+// HandleTimePercentile returns the handle time of records at percentile in (0, 100], like 99 for p99.
+// The time is the upper bound of the bucket in HandleTimes, so it may be at most twice as the real time.
+// It returns 0 if handle time isn't measured, see WithHandleTimeStats.
+func (s Stats) HandleTimePercentile(percentile float64) time.Duration {
+	var total uint64
+	for _, count := range s.HandleTimes {
+		total += count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(float64(total) * percentile / 100))
+	rank = min(max(rank, 1), total)
+
+	var counted uint64
+	for bucket, count := range s.HandleTimes {
+		counted += count
+
+		if counted >= rank {
+			return handleTimeBound(bucket)
+		}
+	}
+
+	return handleTimeBound(HandleTimeBuckets - 1)
+}
+
+// handleTimeBucket returns the bucket of cost in Stats.HandleTimes.
+func handleTimeBucket(cost time.Duration) int {
+	bucket := bits.Len64(uint64(max(cost, 0)))
+	if bucket >= HandleTimeBuckets {
+		bucket = HandleTimeBuckets - 1
+	}
+
+	return bucket
+}
+
+// handleTimeBound returns the upper bound of bucket in Stats.HandleTimes.
+func handleTimeBound(bucket int) time.Duration {
+	if bucket <= 0 {
+		return 0
+	}
+
+	return time.Duration(1) << bucket
+}
+
+// recordStats counts records in logger and it's shared by all loggers derived from the same logger.
+// All methods are safe to call on a nil stats, so loggers without stats still work.
+type recordStats struct {
+	enqueued     atomic.Uint64
+	written      atomic.Uint64
+	dropped      atomic.Uint64
+	handleErrors atomic.Uint64
+	syncErrors   atomic.Uint64
+	handleTime   atomic.Int64
+	handleTimes  [HandleTimeBuckets]atomic.Uint64
+}
+
+func (rs *recordStats) recordEnqueued() {
+	if rs != nil {
+		rs.enqueued.Add(1)
+	}
+}
+
+func (rs *recordStats) recordHandled(err error) {
+	if rs == nil {
+		return
+	}
+
+	if err != nil {
+		rs.handleErrors.Add(1)
+	} else {
+		rs.written.Add(1)
+	}
+}
+
+func (rs *recordStats) recordHandleTime(cost time.Duration) {
+	if rs != nil {
+		rs.handleTime.Add(int64(cost))
+		rs.handleTimes[handleTimeBucket(cost)].Add(1)
+	}
+}
+
+func (rs *recordStats) recordDropped() {
+	if rs != nil {
+		rs.dropped.Add(1)
+	}
+}
+
+func (rs *recordStats) recordSynced(err error) {
+	if rs != nil && err != nil {
+		rs.syncErrors.Add(1)
+	}
+}
+
+func (rs *recordStats) snapshot() Stats {
+	if rs == nil {
+		return Stats{}
+	}
+
+	stats := Stats{
+		Enqueued:     rs.enqueued.Load(),
+		Written:      rs.written.Load(),
+		Dropped:      rs.dropped.Load(),
+		HandleErrors: rs.handleErrors.Load(),
+		SyncErrors:   rs.syncErrors.Load(),
+		HandleTime:   time.Duration(rs.handleTime.Load()),
+	}
+
+	for i := range rs.handleTimes {
+		stats.HandleTimes[i] = rs.handleTimes[i].Load()
+	}
+
+	return stats
+}
+
+// statsHandler counts records handled by the handler it wraps, so it should wrap the handler writing records directly.
+// The time of handling records is measured if timed is true, see WithHandleTimeStats.
+type statsHandler struct {
+	slog.Handler
+
+	stats *recordStats
+	timed bool
+}
+
+func newStatsHandler(handler slog.Handler, stats *recordStats, timed bool) slog.Handler {
+	return statsHandler{Handler: handler, stats: stats, timed: timed}
+}
+
+func (sh statsHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !sh.timed {
+		err := sh.Handler.Handle(ctx, record)
+		sh.stats.recordHandled(err)
+
+		return err
+	}
+
+	begin := time.Now()
+	err := sh.Handler.Handle(ctx, record)
+	sh.stats.recordHandleTime(time.Since(begin))
+	sh.stats.recordHandled(err)
+
+	return err
+}
+
+func (sh statsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newStatsHandler(sh.Handler.WithAttrs(attrs), sh.stats, sh.timed)
+}
+
+func (sh statsHandler) WithGroup(name string) slog.Handler {
+	return newStatsHandler(sh.Handler.WithGroup(name), sh.stats, sh.timed)
+}
+
+type statsWriter interface {
+	Stats() (writer.WriteStats, bool)
+}
+
+// writeStats returns the stats of the first writer having stats in syncer.
+func writeStats(syncer Syncer) (writer.WriteStats, bool) {
+	if ms, ok := syncer.(multiSyncer); ok {
+		for _, syncer := range ms {
+			if stats, ok := writeStats(syncer); ok {
+				return stats, true
+			}
+		}
+
+		return writer.WriteStats{}, false
+	}
+
+	if sw, ok := syncer.(statsWriter); ok {
+		return sw.Stats()
+	}
+
+	return writer.WriteStats{}, false
+}
+
+// syncWithReason syncs syncer with reason if it's a writer.ReasonSyncer, or just syncs it.
+func syncWithReason(syncer Syncer, reason writer.FlushReason) error {
+	if rs, ok := syncer.(writer.ReasonSyncer); ok {
+		return rs.SyncWithReason(reason)
+	}
+
+	return syncer.Sync()
+}
+
+// WriteStats returns the stats of writes and flushes in the buffer or batch writer of logger.
+// It reports false if stats isn't enabled or logger doesn't have a buffer or batch writer.
+// Flushes by the sync timer are recorded as writer.FlushInterval.
+// See WithWriteStats.
+func (l *Logger) WriteStats() (writer.WriteStats, bool) {
+	return writeStats(l.syncer)
+}
+
+// Stats returns the stats of records in logger and reports false if stats isn't enabled.
+// Derived loggers share the stats with the logger they're derived from.
+// See Stats and WithStats.
+func (l *Logger) Stats() (Stats, bool) {
+	return l.stats.snapshot(), l.stats != nil
+}
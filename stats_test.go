@@ -0,0 +1,249 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/writer"
+)
+
+type testStatsWriter struct {
+	bytes.Buffer
+
+	err error
+}
+
+func (tsw *testStatsWriter) Write(p []byte) (n int, err error) {
+	if strings.Contains(string(p), "fail") {
+		return 0, tsw.err
+	}
+
+	return tsw.Buffer.Write(p)
+}
+
+func (tsw *testStatsWriter) Sync() error {
+	return tsw.err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRecordStats$
+func TestRecordStats(t *testing.T) {
+	var nilStats *recordStats
+	nilStats.recordEnqueued()
+	nilStats.recordHandled(nil)
+	nilStats.recordDropped()
+	nilStats.recordSynced(errors.New("sync"))
+	nilStats.recordHandleTime(time.Millisecond)
+
+	if got := nilStats.snapshot(); got != (Stats{}) {
+		t.Fatalf("got %+v != want %+v", got, Stats{})
+	}
+
+	stats := new(recordStats)
+	stats.recordEnqueued()
+	stats.recordHandled(nil)
+	stats.recordHandled(nil)
+	stats.recordHandled(errors.New("handle"))
+	stats.recordDropped()
+	stats.recordSynced(nil)
+	stats.recordSynced(errors.New("sync"))
+
+	want := Stats{Enqueued: 1, Written: 2, Dropped: 1, HandleErrors: 1, SyncErrors: 1}
+	if got := stats.snapshot(); got != want {
+		t.Fatalf("got %+v != want %+v", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerStats$
+func TestLoggerStats(t *testing.T) {
+	statsWriter := &testStatsWriter{err: errors.New("fail")}
+
+	logger := NewLogger(WithWriter(bytes.NewBuffer(nil)))
+	logger.Info("msg")
+
+	if _, ok := logger.Stats(); ok {
+		t.Fatal("stats should be disabled")
+	}
+
+	logger = NewLogger(WithWriter(statsWriter), WithStats())
+	logger.Info("msg")
+	logger.Info("fail")
+
+	derived := logger.With("key", "value")
+	derived.Info("msg")
+
+	if err := logger.Sync(); err == nil {
+		t.Fatal("sync should fail")
+	}
+
+	logger.Close()
+	logger.Info("msg")
+
+	want := Stats{Written: 2, Dropped: 1, HandleErrors: 1, SyncErrors: 2}
+	if got, _ := logger.Stats(); got != want {
+		t.Fatalf("got %+v != want %+v", got, want)
+	}
+
+	if got, _ := derived.Stats(); got != want {
+		t.Fatalf("got %+v != want %+v", got, want)
+	}
+
+	logger = NewLogger(WithWriter(bytes.NewBuffer(nil)), WithAsync(16, DropPolicyBlock), WithStats())
+	logger.Info("msg")
+	logger.Info("msg")
+	logger.Close()
+
+	want = Stats{Enqueued: 2, Written: 2}
+	if got, _ := logger.Stats(); got != want {
+		t.Fatalf("got %+v != want %+v", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestStatsHandleTimePercentile$
+func TestStatsHandleTimePercentile(t *testing.T) {
+	stats := new(recordStats)
+	if got := stats.snapshot().HandleTimePercentile(99); got != 0 {
+		t.Fatalf("got %d != 0", got)
+	}
+
+	for i := 0; i < 90; i++ {
+		stats.recordHandleTime(100 * time.Nanosecond)
+	}
+
+	for i := 0; i < 9; i++ {
+		stats.recordHandleTime(3 * time.Microsecond)
+	}
+
+	stats.recordHandleTime(time.Hour)
+	stats.recordHandleTime(-time.Second)
+
+	snapshot := stats.snapshot()
+	if want := 90*100*time.Nanosecond + 9*3*time.Microsecond + time.Hour - time.Second; snapshot.HandleTime != want {
+		t.Fatalf("snapshot.HandleTime %d != want %d", snapshot.HandleTime, want)
+	}
+
+	if snapshot.HandleTimes[0] != 1 || snapshot.HandleTimes[7] != 90 || snapshot.HandleTimes[12] != 9 || snapshot.HandleTimes[HandleTimeBuckets-1] != 1 {
+		t.Fatalf("snapshot.HandleTimes %v is wrong", snapshot.HandleTimes)
+	}
+
+	testCases := map[float64]time.Duration{
+		0:   0,
+		1:   128 * time.Nanosecond,
+		50:  128 * time.Nanosecond,
+		90:  128 * time.Nanosecond,
+		91:  4096 * time.Nanosecond,
+		99:  4096 * time.Nanosecond,
+		100: 1 << (HandleTimeBuckets - 1),
+		200: 1 << (HandleTimeBuckets - 1),
+	}
+
+	for percentile, want := range testCases {
+		if got := snapshot.HandleTimePercentile(percentile); got != want {
+			t.Fatalf("percentile %.0f: got %d != want %d", percentile, got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerHandleTimeStats$
+func TestLoggerHandleTimeStats(t *testing.T) {
+	logger := NewLogger(WithWriter(bytes.NewBuffer(nil)), WithStats())
+	logger.Info("msg")
+
+	if stats, _ := logger.Stats(); stats.HandleTime != 0 || stats.HandleTimes != ([HandleTimeBuckets]uint64{}) {
+		t.Fatalf("stats %+v shouldn't measure handle time", stats)
+	}
+
+	logger = NewLogger(WithWriter(bytes.NewBuffer(nil)), WithHandleTimeStats())
+	logger.Info("msg")
+	logger.With("key", "value").Info("msg")
+	logger.Close()
+
+	stats, ok := logger.Stats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	var handled uint64
+	for _, count := range stats.HandleTimes {
+		handled += count
+	}
+
+	if stats.Written != 2 || handled != 2 || stats.HandleTime <= 0 || stats.HandleTimePercentile(100) <= 0 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWriteStats$
+func TestLoggerWriteStats(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	logger := NewLogger(WithWriter(buffer), WithBuffer(4096))
+	defer logger.Close()
+
+	if _, ok := logger.WriteStats(); ok {
+		t.Fatal("stats should be disabled")
+	}
+
+	logger = NewLogger(WithWriter(buffer), WithBuffer(4096), WithWriteStats(), WithSyncTimer(10*time.Millisecond))
+	defer logger.Close()
+
+	logger.Info("msg")
+	time.Sleep(100 * time.Millisecond)
+
+	logger.Info("msg")
+	logger.Sync()
+
+	stats, ok := logger.WriteStats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if stats.Writes != 2 || stats.Flushes[writer.FlushInterval] != 1 || stats.Flushes[writer.FlushSync] != 1 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriteStats$
+func TestWriteStats(t *testing.T) {
+	batch := writer.Batch(bytes.NewBuffer(nil), 16)
+	batch.EnableStats()
+	batch.Write([]byte("abc"))
+
+	syncers := []Syncer{nilSyncer{}, multiSyncer{nilSyncer{}}, multiSyncer{nilSyncer{}, batch}}
+	wants := []bool{false, false, true}
+
+	for i, syncer := range syncers {
+		stats, ok := writeStats(syncer)
+		if ok != wants[i] {
+			t.Fatalf("syncer %d: ok %+v != want %+v", i, ok, wants[i])
+		}
+
+		if ok && stats.Writes != 1 {
+			t.Fatalf("syncer %d: stats %+v is wrong", i, stats)
+		}
+	}
+
+	if err := syncWithReason(multiSyncer{nilSyncer{}, batch}, writer.FlushInterval); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats, _ := batch.Stats(); stats.Flushes[writer.FlushInterval] != 1 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
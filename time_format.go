@@ -0,0 +1,87 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// TimeFormatUnix formats time to seconds since the unix epoch.
+	TimeFormatUnix = "unix"
+
+	// TimeFormatUnixMilli formats time to milliseconds since the unix epoch.
+	TimeFormatUnixMilli = "unix_ms"
+
+	// TimeFormatUnixMicro formats time to microseconds since the unix epoch.
+	TimeFormatUnixMicro = "unix_us"
+
+	// TimeFormatUnixNano formats time to nanoseconds since the unix epoch.
+	TimeFormatUnixNano = "unix_ns"
+
+	// TimeFormatRFC3339 formats time with layout time.RFC3339.
+	TimeFormatRFC3339 = "rfc3339"
+
+	// TimeFormatRFC3339Nano formats time with layout time.RFC3339Nano.
+	TimeFormatRFC3339Nano = "rfc3339nano"
+)
+
+// newTimeFormatter returns a function formatting time to a value in format.
+// The format can be one of the TimeFormatXxx constants or a layout like "2006-01-02 15:04:05".
+func newTimeFormatter(format string) func(t time.Time) slog.Value {
+	switch format {
+	case TimeFormatUnix:
+		return func(t time.Time) slog.Value { return slog.Int64Value(t.Unix()) }
+	case TimeFormatUnixMilli:
+		return func(t time.Time) slog.Value { return slog.Int64Value(t.UnixMilli()) }
+	case TimeFormatUnixMicro:
+		return func(t time.Time) slog.Value { return slog.Int64Value(t.UnixMicro()) }
+	case TimeFormatUnixNano:
+		return func(t time.Time) slog.Value { return slog.Int64Value(t.UnixNano()) }
+	case TimeFormatRFC3339:
+		format = time.RFC3339
+	case TimeFormatRFC3339Nano:
+		format = time.RFC3339Nano
+	}
+
+	return func(t time.Time) slog.Value {
+		return slog.StringValue(t.Format(format))
+	}
+}
+
+// newTimeReplacer returns a replacer formatting the time of records in format.
+// The time will be converted to UTC first if utc is true, and an empty format means keeping the time as it is,
+// so handlers can still format it in their own ways.
+func newTimeReplacer(format string, utc bool) func(groups []string, attr slog.Attr) slog.Attr {
+	formatTime := slog.TimeValue
+	if format != "" {
+		formatTime = newTimeFormatter(format)
+	}
+
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) > 0 || attr.Key != slog.TimeKey || attr.Value.Kind() != slog.KindTime {
+			return attr
+		}
+
+		t := attr.Value.Time()
+		if utc {
+			t = t.UTC()
+		}
+
+		attr.Value = formatTime(t)
+		return attr
+	}
+}
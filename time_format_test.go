@@ -0,0 +1,133 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewTimeFormatter$
+func TestNewTimeFormatter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	tests := []struct {
+		format string
+		want   slog.Value
+	}{
+		{format: TimeFormatUnix, want: slog.Int64Value(now.Unix())},
+		{format: TimeFormatUnixMilli, want: slog.Int64Value(now.UnixMilli())},
+		{format: TimeFormatUnixMicro, want: slog.Int64Value(now.UnixMicro())},
+		{format: TimeFormatUnixNano, want: slog.Int64Value(now.UnixNano())},
+		{format: TimeFormatRFC3339, want: slog.StringValue("2024-01-02T03:04:05Z")},
+		{format: TimeFormatRFC3339Nano, want: slog.StringValue("2024-01-02T03:04:05.123456789Z")},
+		{format: "2006/01/02", want: slog.StringValue("2024/01/02")},
+	}
+
+	for _, tt := range tests {
+		got := newTimeFormatter(tt.format)(now)
+		if !got.Equal(tt.want) {
+			t.Fatalf("format %s got %v != want %v", tt.format, got, tt.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewTimeReplacer$
+func TestNewTimeReplacer(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	replaceAttr := newTimeReplacer(TimeFormatUnix, false)
+
+	attr := replaceAttr(nil, slog.Time(slog.TimeKey, now))
+	if attr.Value.Kind() != slog.KindInt64 || attr.Value.Int64() != 1700000000 {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	attrs := []struct {
+		groups []string
+		attr   slog.Attr
+	}{
+		{groups: []string{"group"}, attr: slog.Time(slog.TimeKey, now)},
+		{groups: nil, attr: slog.Time("other", now)},
+		{groups: nil, attr: slog.String(slog.TimeKey, "value")},
+	}
+
+	for _, a := range attrs {
+		if got := replaceAttr(a.groups, a.attr); !got.Equal(a.attr) {
+			t.Fatalf("got %+v != attr %+v", got, a.attr)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerTimeFormat$
+func TestLoggerTimeFormat(t *testing.T) {
+	handlers := []Option{WithTapeHandler(), WithTextHandler(), WithJsonHandler()}
+	wants := []string{"1700000000000 ¦ INFO ¦ msg", "time=1700000000000 level=INFO msg=msg", `{"time":1700000000000,"level":"INFO","msg":"msg"}`}
+
+	for i, withHandler := range handlers {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		logger := NewLogger(withHandler, WithWriter(buffer), WithTimeFormat(TimeFormatUnixMilli))
+
+		record := slog.NewRecord(time.UnixMilli(1700000000000), slog.LevelInfo, "msg", 0)
+		logger.handler.Handle(context.Background(), record)
+
+		if got := strings.TrimSpace(buffer.String()); got != wants[i] {
+			t.Fatalf("got %s != want %s", got, wants[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewTimeReplacerUTC$
+func TestNewTimeReplacerUTC(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CST", 8*60*60))
+
+	attr := newTimeReplacer("", true)(nil, slog.Time(slog.TimeKey, now))
+	if attr.Value.Kind() != slog.KindTime || attr.Value.Time().Location() != time.UTC || !attr.Value.Time().Equal(now) {
+		t.Fatalf("attr %+v is wrong", attr)
+	}
+
+	attr = newTimeReplacer(TimeFormatRFC3339, true)(nil, slog.Time(slog.TimeKey, now))
+	if got := attr.Value.String(); got != "2024-01-01T19:04:05Z" {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	attr = newTimeReplacer(TimeFormatRFC3339, false)(nil, slog.Time(slog.TimeKey, now))
+	if got := attr.Value.String(); got != "2024-01-02T03:04:05+08:00" {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerUTC$
+func TestLoggerUTC(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CST", 8*60*60))
+
+	handlers := []Option{WithTapeHandler(), WithTextHandler(), WithJsonHandler()}
+	wants := []string{"2024-01-01 19:04:05.000000 ¦ INFO ¦ msg", "time=2024-01-01T19:04:05.000Z level=INFO msg=msg", `{"time":"2024-01-01T19:04:05Z","level":"INFO","msg":"msg"}`}
+
+	for i, withHandler := range handlers {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		logger := NewLogger(withHandler, WithWriter(buffer), WithUTC())
+
+		record := slog.NewRecord(now, slog.LevelInfo, "msg", 0)
+		logger.handler.Handle(context.Background(), record)
+
+		if got := strings.TrimSpace(buffer.String()); got != wants[i] {
+			t.Fatalf("got %s != want %s", got, wants[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// TraceParentHeader is the header carrying trace ids between services in W3C trace context.
+	TraceParentHeader = "traceparent"
+
+	keyTraceID = "trace_id"
+	keySpanID  = "span_id"
+)
+
+type traceContextKey struct{}
+
+// traceIDs is a lightweight pair of trace id and span id compatible with W3C trace context.
+// It's used as a fallback for correlating logs across services when no tracer is installed.
+type traceIDs struct {
+	traceID [16]byte
+	spanID  [8]byte
+}
+
+func randomBytes(bs []byte) {
+	if _, err := rand.Read(bs); err != nil {
+		defaults.HandleError("rand.Read", err)
+	}
+}
+
+func isZeroBytes(bs []byte) bool {
+	for _, b := range bs {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StartSpan returns a new context with a new span id.
+// The trace id in ctx will be kept, or a new trace id will be generated if ctx doesn't have one or ctx is nil.
+// Use it with WithTraceAttrs so logs with the context will carry the trace id and span id.
+func StartSpan(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ids, ok := ctx.Value(traceContextKey{}).(traceIDs)
+	if !ok {
+		randomBytes(ids.traceID[:])
+	}
+
+	randomBytes(ids.spanID[:])
+	return context.WithValue(ctx, traceContextKey{}, ids)
+}
+
+// TraceIDs returns the trace id and span id in ctx in hex and reports whether they're found.
+func TraceIDs(ctx context.Context) (traceID string, spanID string, ok bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+
+	ids, ok := ctx.Value(traceContextKey{}).(traceIDs)
+	if !ok {
+		return "", "", false
+	}
+
+	return hex.EncodeToString(ids.traceID[:]), hex.EncodeToString(ids.spanID[:]), true
+}
+
+// TraceParent returns the value of traceparent header carrying the trace id and span id in ctx.
+// It returns an empty string if ctx doesn't have them, so you can skip setting the header of outgoing requests.
+func TraceParent(ctx context.Context) string {
+	traceID, spanID, ok := TraceIDs(ctx)
+	if !ok {
+		return ""
+	}
+
+	return "00-" + traceID + "-" + spanID + "-01"
+}
+
+// ContextWithTraceParent parses traceParent from incoming requests and returns a new context with a new span.
+// The trace id in traceParent will be kept and the span id in traceParent is treated as the parent of the new span.
+// It returns an error if traceParent isn't a valid W3C traceparent value.
+func ContextWithTraceParent(ctx context.Context, traceParent string) (context.Context, error) {
+	// The format is version-trace_id-parent_id-flags, like 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01.
+	if len(traceParent) < 55 || traceParent[2] != '-' || traceParent[35] != '-' || traceParent[52] != '-' {
+		return ctx, fmt.Errorf("logit: trace parent %q is invalid", traceParent)
+	}
+
+	// Future versions may append fields after flags, but version 00 can't.
+	if traceParent[:2] == "ff" || (traceParent[:2] == "00" && len(traceParent) != 55) || (len(traceParent) > 55 && traceParent[55] != '-') {
+		return ctx, fmt.Errorf("logit: trace parent %q has invalid version", traceParent)
+	}
+
+	var ids traceIDs
+	if _, err := hex.Decode(ids.traceID[:], []byte(traceParent[3:35])); err != nil {
+		return ctx, fmt.Errorf("logit: trace parent %q has invalid trace id: %w", traceParent, err)
+	}
+
+	var parentID [8]byte
+	if _, err := hex.Decode(parentID[:], []byte(traceParent[36:52])); err != nil {
+		return ctx, fmt.Errorf("logit: trace parent %q has invalid parent id: %w", traceParent, err)
+	}
+
+	if isZeroBytes(ids.traceID[:]) || isZeroBytes(parentID[:]) {
+		return ctx, fmt.Errorf("logit: trace parent %q has zero ids", traceParent)
+	}
+
+	randomBytes(ids.spanID[:])
+	return context.WithValue(ctx, traceContextKey{}, ids), nil
+}
+
+// traceHandler adds the trace id and span id in context to records.
+type traceHandler struct {
+	slog.Handler
+}
+
+func newTraceHandler(handler slog.Handler) slog.Handler {
+	return traceHandler{Handler: handler}
+}
+
+func (th traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if ids, ok := ctx.Value(traceContextKey{}).(traceIDs); ok {
+			traceID := hex.EncodeToString(ids.traceID[:])
+			spanID := hex.EncodeToString(ids.spanID[:])
+			record.AddAttrs(slog.String(keyTraceID, traceID), slog.String(keySpanID, spanID))
+		}
+	}
+
+	return th.Handler.Handle(ctx, record)
+}
+
+func (th traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newTraceHandler(th.Handler.WithAttrs(attrs))
+}
+
+func (th traceHandler) WithGroup(name string) slog.Handler {
+	return newTraceHandler(th.Handler.WithGroup(name))
+}
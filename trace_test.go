@@ -0,0 +1,173 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestStartSpan$
+func TestStartSpan(t *testing.T) {
+	var nilCtx context.Context
+	if _, _, ok := TraceIDs(nilCtx); ok {
+		t.Fatal("nil ctx has trace ids")
+	}
+
+	if _, _, ok := TraceIDs(StartSpan(nilCtx)); !ok {
+		t.Fatal("span started from nil ctx doesn't have trace ids")
+	}
+
+	ctx := context.Background()
+	if _, _, ok := TraceIDs(ctx); ok {
+		t.Fatal("ctx has trace ids")
+	}
+
+	if traceParent := TraceParent(ctx); traceParent != "" {
+		t.Fatalf("traceParent %q isn't empty", traceParent)
+	}
+
+	ctx = StartSpan(ctx)
+
+	traceID, spanID, ok := TraceIDs(ctx)
+	if !ok {
+		t.Fatal("ctx doesn't have trace ids")
+	}
+
+	if len(traceID) != 32 || len(spanID) != 16 {
+		t.Fatalf("traceID %q or spanID %q is wrong", traceID, spanID)
+	}
+
+	childCtx := StartSpan(ctx)
+
+	childTraceID, childSpanID, ok := TraceIDs(childCtx)
+	if !ok {
+		t.Fatal("childCtx doesn't have trace ids")
+	}
+
+	if childTraceID != traceID {
+		t.Fatalf("childTraceID %q != traceID %q", childTraceID, traceID)
+	}
+
+	if childSpanID == spanID {
+		t.Fatalf("childSpanID %q == spanID %q", childSpanID, spanID)
+	}
+
+	want := "00-" + traceID + "-" + spanID + "-01"
+	if traceParent := TraceParent(ctx); traceParent != want {
+		t.Fatalf("traceParent %q != want %q", traceParent, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestContextWithTraceParent$
+func TestContextWithTraceParent(t *testing.T) {
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	ctx, err := ContextWithTraceParent(context.Background(), traceParent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traceID, spanID, ok := TraceIDs(ctx)
+	if !ok {
+		t.Fatal("ctx doesn't have trace ids")
+	}
+
+	if traceID != "0af7651916cd43dd8448eb211c80319c" {
+		t.Fatalf("traceID %q is wrong", traceID)
+	}
+
+	if spanID == "b7ad6b7169203331" || len(spanID) != 16 {
+		t.Fatalf("spanID %q is wrong", spanID)
+	}
+
+	if _, err = ContextWithTraceParent(context.Background(), "01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-future"); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidTraceParents := []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-future",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b716920333x-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00_0af7651916cd43dd8448eb211c80319c_b7ad6b7169203331_01",
+	}
+
+	for _, invalidTraceParent := range invalidTraceParents {
+		if _, err = ContextWithTraceParent(context.Background(), invalidTraceParent); err == nil {
+			t.Fatalf("trace parent %q should be invalid", invalidTraceParent)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTraceHandler$
+func TestTraceHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithLevel(LevelTrace), WithTraceAttrs())
+
+	logger.TraceContext(context.Background(), "no trace")
+
+	ctx := StartSpan(context.Background())
+	logger.With("key", "value").TraceContext(ctx, "trace")
+	logger.Slog().InfoContext(ctx, "slog")
+
+	traceID, spanID, _ := TraceIDs(ctx)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) %d != 3", len(lines))
+	}
+
+	for i, line := range lines {
+		entry := make(map[string]any, 8)
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 0 {
+			if _, ok := entry[keyTraceID]; ok {
+				t.Fatalf("entry %+v has trace id", entry)
+			}
+
+			continue
+		}
+
+		if entry[keyTraceID] != traceID || entry[keySpanID] != spanID {
+			t.Fatalf("entry %+v is wrong", entry)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTraceHandlerNilContext$
+func TestTraceHandlerNilContext(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithTraceAttrs())
+
+	var nilCtx context.Context
+	logger.Slog().InfoContext(nilCtx, "slog")
+	logger.Log(nilCtx, slog.LevelInfo, 0, "log")
+
+	if got := buffer.String(); !strings.Contains(got, "¦ slog\n") || !strings.Contains(got, "¦ log\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
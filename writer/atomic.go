@@ -0,0 +1,118 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// PipeBuf is the max size of data written to a pipe atomically on linux.
+	// Writes not larger than it won't be interleaved with writes of other processes to the same pipe.
+	PipeBuf = 4096
+)
+
+// AtomicWriter is a writer which writes each record to underlying writer in one write call with a lock.
+// Records won't be interleaved or torn by concurrent writes in the same process,
+// and records not larger than max size won't be interleaved by other processes if underlying writer is a pipe
+// or a file opened with O_APPEND.
+type AtomicWriter struct {
+	// writer is the underlying writer to write data.
+	writer io.Writer
+
+	// maxSize is the max size of records which are guaranteed to be written atomically.
+	maxSize int
+
+	// oversized is the count of records larger than max size.
+	oversized uint64
+
+	lock sync.Mutex
+}
+
+// Atomic returns a new atomic writer of writer with specified maxSize, which is usually PipeBuf.
+// Notice that maxSize must be positive or a panic will happen.
+func Atomic(writer io.Writer, maxSize int) *AtomicWriter {
+	if maxSize <= 0 {
+		panic(fmt.Errorf("logit: atomic maxSize %d <= 0", maxSize))
+	}
+
+	if aw, ok := writer.(*AtomicWriter); ok {
+		return aw
+	}
+
+	aw := &AtomicWriter{
+		writer:  writer,
+		maxSize: maxSize,
+	}
+
+	return aw
+}
+
+// Write writes p to underlying writer in one write call.
+// Short writes will be retried with the lock held, so p won't be interleaved by other writes of this writer.
+// Records larger than max size are written too, but they may be interleaved by other processes, see Oversized.
+func (aw *AtomicWriter) Write(p []byte) (n int, err error) {
+	aw.lock.Lock()
+	defer aw.lock.Unlock()
+
+	if len(p) > aw.maxSize {
+		aw.oversized++
+	}
+
+	for n < len(p) {
+		written, err := aw.writer.Write(p[n:])
+		n += written
+
+		if err != nil {
+			return n, err
+		}
+
+		if written <= 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+
+	return n, nil
+}
+
+// Oversized returns the count of records larger than max size, which aren't guaranteed to be written atomically.
+func (aw *AtomicWriter) Oversized() uint64 {
+	aw.lock.Lock()
+	defer aw.lock.Unlock()
+
+	return aw.oversized
+}
+
+// Sync syncs underlying writer if writer implements Sync() error.
+func (aw *AtomicWriter) Sync() error {
+	aw.lock.Lock()
+	defer aw.lock.Unlock()
+
+	return syncWriter(aw.writer)
+}
+
+// Close closes underlying writer if writer implements io.Closer.
+func (aw *AtomicWriter) Close() error {
+	aw.lock.Lock()
+	defer aw.lock.Unlock()
+
+	if closer, ok := aw.writer.(io.Closer); ok && notStdoutAndStderr(aw.writer) {
+		return closer.Close()
+	}
+
+	return nil
+}
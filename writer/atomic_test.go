@@ -0,0 +1,121 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type shortWriter struct {
+	buffer bytes.Buffer
+	limit  int
+	err    error
+}
+
+func (sw *shortWriter) Write(p []byte) (n int, err error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+
+	if len(p) > sw.limit {
+		p = p[:sw.limit]
+	}
+
+	return sw.buffer.Write(p)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAtomic$
+func TestAtomic(t *testing.T) {
+	writer := Atomic(io.Discard, PipeBuf)
+	if writer.maxSize != PipeBuf {
+		t.Fatalf("writer.maxSize %d != PipeBuf %d", writer.maxSize, PipeBuf)
+	}
+
+	if newWriter := Atomic(writer, 1); newWriter != writer {
+		t.Fatal("newWriter is wrong")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Atomic with maxSize 0 should panic")
+		}
+	}()
+
+	Atomic(io.Discard, 0)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAtomicWriter$
+func TestAtomicWriter(t *testing.T) {
+	sw := &shortWriter{limit: 3}
+	writer := Atomic(sw, 8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			record := strings.Repeat(string(rune('a'+i)), 7) + "\n"
+			if i == 0 {
+				record = strings.Repeat("a", 15) + "\n"
+			}
+
+			if n, err := writer.Write([]byte(record)); err != nil || n != len(record) {
+				t.Errorf("n %d or err %+v is wrong", n, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(sw.buffer.String(), "\n"), "\n")
+	if len(lines) != 16 {
+		t.Fatalf("len(lines) %d != 16", len(lines))
+	}
+
+	for _, line := range lines {
+		if strings.Count(line, line[:1]) != len(line) {
+			t.Fatalf("line %s is torn", line)
+		}
+	}
+
+	if writer.Oversized() != 1 {
+		t.Fatalf("writer.Oversized() %d != 1", writer.Oversized())
+	}
+
+	sw.err = errors.New("write failed")
+	if _, err := writer.Write([]byte("x")); err != sw.err {
+		t.Fatalf("err %+v != sw.err %+v", err, sw.err)
+	}
+
+	sw.err = nil
+	sw.limit = 0
+	if _, err := writer.Write([]byte("x")); err != io.ErrShortWrite {
+		t.Fatalf("err %+v != io.ErrShortWrite", err)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,6 +15,7 @@ package writer
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -27,6 +28,8 @@ const (
 )
 
 // BatchWriter is a writer having a buffer inside to reduce times of writing underlying writer.
+// By default, batches are flushed under the lock in order of writes, so logs reach underlying writer in call order.
+// Use BatchParallel to flush batches in parallel if the order doesn't matter, like telemetry streams to network sinks.
 type BatchWriter struct {
 	// writer is the underlying writer to write data.
 	writer io.Writer
@@ -42,6 +45,19 @@ type BatchWriter struct {
 	// so you can pre-write them by Sync() if you want.
 	buffer *bytes.Buffer
 
+	// stats is the stats of writes and flushes, which is nil if stats isn't enabled.
+	stats *WriteStats
+
+	// slots limits the count of batches flushed in parallel, which is nil if batches are flushed in order.
+	slots chan struct{}
+
+	// flushing is the group of batches being flushed in parallel.
+	flushing sync.WaitGroup
+
+	// flushErrs are errors of batches flushed in parallel, which will be returned by the next sync.
+	flushErrs []error
+	errLock   sync.Mutex
+
 	lock sync.Mutex
 }
 
@@ -67,13 +83,33 @@ func Batch(writer io.Writer, batchSize uint64) *BatchWriter {
 	return bw
 }
 
+// BatchParallel returns a new batch writer of writer like Batch, but full batches are flushed in parallel.
+// At most parallelism batches are flushed at the same time, and writes will wait if all of them are being flushed.
+// Batches may reach underlying writer out of order, so underlying writer must be safe in concurrency.
+// Errors of flushing full batches will be returned by the next Sync or Close, which waits for all batches flushed.
+// Notice that parallelism less than 2 means flushing in order, which is the same as Batch.
+func BatchParallel(writer io.Writer, batchSize uint64, parallelism int) *BatchWriter {
+	bw := Batch(writer, batchSize)
+	if parallelism < 2 {
+		return bw
+	}
+
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	bw.slots = make(chan struct{}, parallelism)
+	return bw
+}
+
 // Write writes p to buffer and syncs data to underlying writer first if it needs.
 func (bw *BatchWriter) Write(p []byte) (n int, err error) {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
+	bw.stats.recordWrite(len(p))
+
 	if bw.currentBatches >= bw.maxBatches {
-		bw.sync()
+		bw.flushFull()
 		bw.currentBatches = 0
 	}
 
@@ -81,22 +117,99 @@ func (bw *BatchWriter) Write(p []byte) (n int, err error) {
 	return bw.buffer.Write(p)
 }
 
-func (bw *BatchWriter) sync() error {
+// flushFull flushes the full batch in order or in parallel.
+func (bw *BatchWriter) flushFull() {
+	if bw.slots == nil {
+		bw.sync(FlushFull)
+		return
+	}
+
+	bw.stats.recordFlush(FlushFull, bw.buffer.Len())
+
+	batch := bw.buffer
+	bw.buffer = bytes.NewBuffer(make([]byte, 0, batch.Cap()))
+
+	bw.slots <- struct{}{}
+	bw.flushing.Add(1)
+
+	go func() {
+		defer func() {
+			<-bw.slots
+			bw.flushing.Done()
+		}()
+
+		if _, err := batch.WriteTo(bw.writer); err != nil {
+			bw.errLock.Lock()
+			bw.flushErrs = append(bw.flushErrs, err)
+			bw.errLock.Unlock()
+		}
+	}()
+}
+
+// waitFlushing waits for all batches flushed in parallel and returns their errors.
+func (bw *BatchWriter) waitFlushing() error {
+	if bw.slots == nil {
+		return nil
+	}
+
+	bw.flushing.Wait()
+
+	bw.errLock.Lock()
+	defer bw.errLock.Unlock()
+
+	err := errors.Join(bw.flushErrs...)
+	bw.flushErrs = nil
+
+	return err
+}
+
+func (bw *BatchWriter) sync(reason FlushReason) error {
+	bw.stats.recordFlush(reason, bw.buffer.Len())
+
 	_, err := bw.buffer.WriteTo(bw.writer)
 	return err
 }
 
-// Sync writes data in buffer to underlying writer if buffer has data.
+// Sync writes data in buffer to underlying writer if buffer has data, and then syncs underlying writer.
+// The underlying writer will be synced even if writing data failed, and all errors will be joined.
 // It's safe in concurrency.
 func (bw *BatchWriter) Sync() error {
+	return bw.SyncWithReason(FlushSync)
+}
+
+// SyncWithReason is like Sync but the flush will be recorded with reason in stats.
+func (bw *BatchWriter) SyncWithReason(reason FlushReason) error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
+	flushErr := bw.waitFlushing()
+
+	var err error
 	if bw.buffer.Len() > 0 {
-		return bw.sync()
+		err = bw.sync(reason)
 	}
 
-	return nil
+	return errors.Join(flushErr, err, syncWriter(bw.writer))
+}
+
+// EnableStats enables stats of writes and flushes, so you can tune batch size from data.
+// See Stats.
+func (bw *BatchWriter) EnableStats() {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	if bw.stats == nil {
+		bw.stats = new(WriteStats)
+	}
+}
+
+// Stats returns the stats of writes and flushes and reports whether stats is enabled.
+// See EnableStats.
+func (bw *BatchWriter) Stats() (WriteStats, bool) {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	return bw.stats.snapshot()
 }
 
 func (bw *BatchWriter) close() error {
@@ -108,13 +221,14 @@ func (bw *BatchWriter) close() error {
 }
 
 // Close syncs data and closes underlying writer if writer implements io.Closer.
+// The underlying writer will be closed even if syncing data failed, and all errors will be joined.
 func (bw *BatchWriter) Close() error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
-	if err := bw.sync(); err != nil {
-		return err
-	}
+	flushErr := bw.waitFlushing()
+	syncErr := bw.sync(FlushClose)
+	closeErr := bw.close()
 
-	return bw.close()
+	return errors.Join(flushErr, syncErr, closeErr)
 }
@@ -16,7 +16,11 @@ package writer
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"os"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 )
@@ -115,3 +119,129 @@ func TestBatchWriterClose(t *testing.T) {
 		}
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchWriterAllocs$
+func TestBatchWriterAllocs(t *testing.T) {
+	writer := Batch(io.Discard, 64)
+	defer writer.Close()
+
+	data := []byte("2024-01-01 00:00:00.000000 ¦ INFO ¦ msg ¦ key=value\n")
+
+	// Warm up the buffer so it has grown enough for a full batch.
+	for i := 0; i < 64; i++ {
+		writer.Write(data)
+	}
+
+	allocs := testing.AllocsPerRun(6400, func() {
+		writer.Write(data)
+	})
+
+	if allocs > 0 {
+		t.Fatalf("allocs %.2f > 0", allocs)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchWriterStats$
+func TestBatchWriterStats(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	writer := Batch(buffer, 2)
+
+	if _, ok := writer.Stats(); ok {
+		t.Fatal("stats should be disabled")
+	}
+
+	writer.EnableStats()
+	writer.Write([]byte("abc"))
+	writer.Write([]byte("123"))
+	writer.Write([]byte("xy"))
+	writer.SyncWithReason(FlushInterval)
+	writer.Write([]byte("z"))
+	writer.Close()
+
+	if buffer.String() != "abc123xyz" {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+
+	stats, ok := writer.Stats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if stats.Writes != 4 || stats.WriteBytes != 9 || stats.WriteSizes[2] != 3 || stats.WriteSizes[1] != 1 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	if stats.Flushes[FlushFull] != 1 || stats.FlushBytes[FlushFull] != 6 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	if stats.FlushBytes[FlushInterval] != 2 || stats.FlushBytes[FlushClose] != 1 || stats.Flushes[FlushSync] != 0 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+}
+
+// slowWriter writes slowly and records the max count of concurrent writes.
+type slowWriter struct {
+	buffer      bytes.Buffer
+	writing     int
+	maxWriting  int
+	failedWrite bool
+	lock        sync.Mutex
+}
+
+func (sw *slowWriter) Write(p []byte) (n int, err error) {
+	sw.lock.Lock()
+	sw.writing++
+	sw.maxWriting = max(sw.maxWriting, sw.writing)
+	sw.lock.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	sw.writing--
+	if sw.failedWrite {
+		return 0, io.ErrShortWrite
+	}
+
+	return sw.buffer.Write(p)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchParallel$
+func TestBatchParallel(t *testing.T) {
+	if writer := BatchParallel(os.Stdout, 16, 1); writer.slots != nil {
+		t.Fatal("writer with parallelism 1 should flush in order")
+	}
+
+	sw := new(slowWriter)
+	writer := BatchParallel(sw, 2, 3)
+
+	for i := 0; i < 13; i++ {
+		writer.Write([]byte{'a' + byte(i)})
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sw.maxWriting < 2 || sw.maxWriting > 3 {
+		t.Fatalf("sw.maxWriting %d is wrong", sw.maxWriting)
+	}
+
+	got := []byte(sw.buffer.String())
+	slices.Sort(got)
+
+	if string(got) != "abcdefghijklm" {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	sw.failedWrite = true
+	writer.Write([]byte("x"))
+	writer.Write([]byte("y"))
+	writer.Write([]byte("z"))
+
+	if err := writer.Close(); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+}
@@ -16,6 +16,7 @@ package writer
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -40,6 +41,9 @@ type BufferWriter struct {
 	// so you can pre-write them by Sync() if you need.
 	buffer *bytes.Buffer
 
+	// stats is the stats of writes and flushes, which is nil if stats isn't enabled.
+	stats *WriteStats
+
 	lock sync.Mutex
 }
 
@@ -69,11 +73,17 @@ func (bw *BufferWriter) Write(p []byte) (n int, err error) {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
+	bw.stats.recordWrite(len(p))
+
 	// This p is too large, so we write it directly to avoid copying.
 	needBufferSize := len(p)
 	tooLarge := uint64(needBufferSize) >= bw.maxBufferSize
 	if tooLarge {
-		bw.sync()
+		if bw.buffer.Len() > 0 {
+			bw.sync(FlushFull)
+		}
+
+		bw.stats.recordFlush(FlushFull, len(p))
 		return bw.writer.Write(p)
 	}
 
@@ -81,28 +91,58 @@ func (bw *BufferWriter) Write(p []byte) (n int, err error) {
 	needBufferSize = bw.buffer.Len() + len(p)
 	notEnough := uint64(needBufferSize) >= bw.maxBufferSize
 	if notEnough {
-		bw.sync()
+		bw.sync(FlushFull)
 	}
 
 	return bw.buffer.Write(p)
 }
 
-func (bw *BufferWriter) sync() error {
+func (bw *BufferWriter) sync(reason FlushReason) error {
+	bw.stats.recordFlush(reason, bw.buffer.Len())
+
 	_, err := bw.buffer.WriteTo(bw.writer)
 	return err
 }
 
-// Sync writes data in buffer to underlying writer if buffer has data.
+// Sync writes data in buffer to underlying writer if buffer has data, and then syncs underlying writer.
+// The underlying writer will be synced even if writing data failed, and all errors will be joined.
 // It's safe in concurrency.
 func (bw *BufferWriter) Sync() error {
+	return bw.SyncWithReason(FlushSync)
+}
+
+// SyncWithReason is like Sync but the flush will be recorded with reason in stats.
+func (bw *BufferWriter) SyncWithReason(reason FlushReason) error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
+	var err error
 	if bw.buffer.Len() > 0 {
-		return bw.sync()
+		err = bw.sync(reason)
 	}
 
-	return nil
+	return errors.Join(err, syncWriter(bw.writer))
+}
+
+// EnableStats enables stats of writes and flushes, so you can tune buffer size from data.
+// See Stats.
+func (bw *BufferWriter) EnableStats() {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	if bw.stats == nil {
+		bw.stats = new(WriteStats)
+	}
+}
+
+// Stats returns the stats of writes and flushes and reports whether stats is enabled.
+// A write larger than buffer is written directly, so it's recorded as a full flush.
+// See EnableStats.
+func (bw *BufferWriter) Stats() (WriteStats, bool) {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	return bw.stats.snapshot()
 }
 
 func (bw *BufferWriter) close() error {
@@ -114,13 +154,13 @@ func (bw *BufferWriter) close() error {
 }
 
 // Close syncs data and closes underlying writer if writer implements io.Closer.
+// The underlying writer will be closed even if syncing data failed, and all errors will be joined.
 func (bw *BufferWriter) Close() error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
-	if err := bw.sync(); err != nil {
-		return err
-	}
+	syncErr := bw.sync(FlushClose)
+	closeErr := bw.close()
 
-	return bw.close()
+	return errors.Join(syncErr, closeErr)
 }
@@ -16,6 +16,7 @@ package writer
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -80,3 +81,93 @@ func TestBufferWriterClose(t *testing.T) {
 		}
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBufferWriterCloseJoinErrors$
+func TestBufferWriterCloseJoinErrors(t *testing.T) {
+	errFailed := errors.New("failed")
+	underlying := &testSyncWriter{err: errFailed}
+
+	writer := Buffer(underlying, 1024)
+	writer.Write([]byte("abc"))
+
+	if err := writer.Close(); !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if underlying.closed != 1 {
+		t.Fatal("underlying writer should be closed even if syncing failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBufferWriterStats$
+func TestBufferWriterStats(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	writer := Buffer(buffer, 8)
+
+	if _, ok := writer.Stats(); ok {
+		t.Fatal("stats should be disabled")
+	}
+
+	writer.EnableStats()
+	writer.Write([]byte("abc"))
+	writer.Write([]byte("1234"))
+	writer.Write([]byte("xy"))
+	writer.Write([]byte("0123456789"))
+	writer.Write([]byte("z"))
+	writer.SyncWithReason(FlushInterval)
+	writer.Write([]byte("z"))
+	writer.Sync()
+	writer.Write([]byte("z"))
+	writer.Close()
+
+	if buffer.String() != "abc1234xy0123456789zzz" {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+
+	stats, ok := writer.Stats()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if stats.Writes != 7 || stats.WriteBytes != 22 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	// Flush abc1234 before writing xy, then xy before writing 0123456789 and 0123456789 itself.
+	if stats.Flushes[FlushFull] != 3 || stats.FlushBytes[FlushFull] != 19 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	for _, reason := range []FlushReason{FlushInterval, FlushSync, FlushClose} {
+		if stats.Flushes[reason] != 1 || stats.FlushBytes[reason] != 1 {
+			t.Fatalf("stats %+v of reason %s is wrong", stats, reason)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBufferWriterStatsTooLarge$
+func TestBufferWriterStatsTooLarge(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	writer := Buffer(buffer, 8)
+	writer.EnableStats()
+
+	// The buffer is empty, so writing a large p directly is the only flush.
+	writer.Write([]byte("0123456789"))
+
+	stats, _ := writer.Stats()
+	if stats.Flushes[FlushFull] != 1 || stats.FlushBytes[FlushFull] != 10 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	writer.Write([]byte("abc"))
+	writer.Write([]byte("0123456789"))
+
+	stats, _ = writer.Stats()
+	if stats.Flushes[FlushFull] != 3 || stats.FlushBytes[FlushFull] != 23 {
+		t.Fatalf("stats %+v is wrong", stats)
+	}
+
+	if buffer.String() != "0123456789abc0123456789" {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
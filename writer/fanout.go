@@ -0,0 +1,84 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"io"
+)
+
+// FanoutWriter is a writer writing data to all underlying writers.
+// Unlike io.MultiWriter, it keeps writing the rest writers if one fails,
+// and it syncs and closes all underlying writers so no one will be skipped.
+type FanoutWriter struct {
+	writers []io.Writer
+}
+
+// Fanout returns a new fanout writer of writers.
+// Notice that writers should be safe in concurrency if the fanout writer is used concurrently.
+func Fanout(writers ...io.Writer) *FanoutWriter {
+	fw := &FanoutWriter{
+		writers: writers,
+	}
+
+	return fw
+}
+
+// Write writes p to all underlying writers and returns the joined errors of them.
+func (fw *FanoutWriter) Write(p []byte) (n int, err error) {
+	var errs []error
+	for _, writer := range fw.writers {
+		if _, err = writer.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	return len(p), nil
+}
+
+// Sync syncs all underlying writers implementing Sync() error and returns the joined errors of them.
+// A writer failed to sync won't stop syncing the rest writers.
+func (fw *FanoutWriter) Sync() error {
+	var errs []error
+	for _, writer := range fw.writers {
+		if err := syncWriter(writer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes all underlying writers implementing io.Closer and returns the joined errors of them.
+// A writer failed to close won't stop closing the rest writers.
+func (fw *FanoutWriter) Close() error {
+	var errs []error
+	for _, writer := range fw.writers {
+		closer, ok := writer.(io.Closer)
+		if !ok || !notStdoutAndStderr(writer) {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type testSyncWriter struct {
+	bytes.Buffer
+
+	err    error
+	synced int
+	closed int
+}
+
+func (tsw *testSyncWriter) Write(p []byte) (n int, err error) {
+	if tsw.err != nil {
+		return 0, tsw.err
+	}
+
+	return tsw.Buffer.Write(p)
+}
+
+func (tsw *testSyncWriter) Sync() error {
+	tsw.synced++
+	return tsw.err
+}
+
+func (tsw *testSyncWriter) Close() error {
+	tsw.closed++
+	return tsw.err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFanoutWriter$
+func TestFanoutWriter(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	first := &testSyncWriter{err: errFailed}
+	second := &testSyncWriter{}
+	third := bytes.NewBuffer(make([]byte, 0, 64))
+
+	writer := Fanout(first, second, third)
+
+	n, err := writer.Write([]byte("abc"))
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if n != 0 {
+		t.Fatalf("n %d is wrong", n)
+	}
+
+	if second.String() != "abc" || third.String() != "abc" {
+		t.Fatalf("second %q or third %q is wrong", second.String(), third.String())
+	}
+
+	if err = writer.Sync(); !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if first.synced != 1 || second.synced != 1 {
+		t.Fatalf("first.synced %d or second.synced %d is wrong", first.synced, second.synced)
+	}
+
+	if err = writer.Close(); !errors.Is(err, errFailed) {
+		t.Fatalf("err %+v is wrong", err)
+	}
+
+	if first.closed != 1 || second.closed != 1 {
+		t.Fatalf("first.closed %d or second.closed %d is wrong", first.closed, second.closed)
+	}
+
+	writer = Fanout(second, third)
+	if n, err = writer.Write([]byte("123")); err != nil || n != 3 {
+		t.Fatalf("n %d or err %+v is wrong", n, err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFanoutWriterNested$
+func TestFanoutWriterNested(t *testing.T) {
+	first := &testSyncWriter{}
+	second := &testSyncWriter{}
+
+	// Syncing the outermost writer should sync all writers composed inside.
+	writer := Buffer(Fanout(Frame(first, CRLFFramer()), Batch(second, 16)), 1024)
+	writer.Write([]byte("abc"))
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.String() != "abc\r\n" || second.String() != "abc" {
+		t.Fatalf("first %q or second %q is wrong", first.String(), second.String())
+	}
+
+	if first.synced != 1 || second.synced != 1 {
+		t.Fatalf("first.synced %d or second.synced %d is wrong", first.synced, second.synced)
+	}
+}
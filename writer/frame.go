@@ -0,0 +1,143 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// Framer frames p and appends the frame to dst.
+// Each write of handler is a log, so a log will be framed as a frame.
+type Framer func(dst []byte, p []byte) []byte
+
+// LengthPrefixFramer returns a framer prefixing data with its length in 4 bytes big endian.
+// Data longer than math.MaxUint32 will cause a panic.
+func LengthPrefixFramer() Framer {
+	return func(dst []byte, p []byte) []byte {
+		if uint64(len(p)) > math.MaxUint32 {
+			panic(fmt.Errorf("logit: frame length %d > max length %d", len(p), uint32(math.MaxUint32)))
+		}
+
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(p)))
+		dst = append(dst, p...)
+
+		return dst
+	}
+}
+
+// DelimiterFramer returns a framer ending data with delimiter.
+// The escape byte and the first byte of delimiter in data will be escaped by prefixing escape byte,
+// so delimiter won't appear in data and receivers can unescape data by removing each escape byte and keeping the next byte.
+// Delimiter must not be empty or start with escape byte, or a panic will happen.
+func DelimiterFramer(delimiter []byte, escape byte) Framer {
+	if len(delimiter) <= 0 || delimiter[0] == escape {
+		panic(fmt.Errorf("logit: delimiter %q is empty or starts with escape byte %q", delimiter, escape))
+	}
+
+	delimiter = bytes.Clone(delimiter)
+	first := delimiter[0]
+
+	return func(dst []byte, p []byte) []byte {
+		start := 0
+		for i, b := range p {
+			if b == first || b == escape {
+				dst = append(dst, p[start:i]...)
+				dst = append(dst, escape, b)
+				start = i + 1
+			}
+		}
+
+		dst = append(dst, p[start:]...)
+		dst = append(dst, delimiter...)
+
+		return dst
+	}
+}
+
+// CRLFFramer returns a framer ending data with "\r\n" and escaping with '\\'.
+// See DelimiterFramer.
+func CRLFFramer() Framer {
+	return DelimiterFramer([]byte("\r\n"), '\\')
+}
+
+// FrameWriter is a writer framing data of each write before writing underlying writer.
+// It's useful for sending logs through transports like tcp, which don't keep boundaries of logs.
+type FrameWriter struct {
+	// writer is the underlying writer to write data.
+	writer io.Writer
+
+	// framer frames data of each write.
+	framer Framer
+
+	// frame is reused for framing data.
+	frame []byte
+
+	lock sync.Mutex
+}
+
+// Frame returns a new frame writer of writer with framer.
+func Frame(writer io.Writer, framer Framer) *FrameWriter {
+	fw := &FrameWriter{
+		writer: writer,
+		framer: framer,
+	}
+
+	return fw
+}
+
+// Write frames p and writes the frame to underlying writer.
+// It returns len(p) if the whole frame is written.
+func (fw *FrameWriter) Write(p []byte) (n int, err error) {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+
+	fw.frame = fw.framer(fw.frame[:0], p)
+
+	if _, err = fw.writer.Write(fw.frame); err != nil {
+		return 0, err
+	}
+
+	// Release large frames for reducing memory.
+	if cap(fw.frame) > defaultBufferSize {
+		fw.frame = nil
+	}
+
+	return len(p), nil
+}
+
+// Sync syncs underlying writer if writer implements Sync() error.
+func (fw *FrameWriter) Sync() error {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+
+	return syncWriter(fw.writer)
+}
+
+// Close closes underlying writer if writer implements io.Closer.
+func (fw *FrameWriter) Close() error {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+
+	if closer, ok := fw.writer.(io.Closer); ok && notStdoutAndStderr(fw.writer) {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// unescapeFrames splits data into frames by delimiter and unescapes them.
+func unescapeFrames(data []byte, delimiter []byte, escape byte) [][]byte {
+	var frames [][]byte
+	var frame []byte
+
+	for i := 0; i < len(data); i++ {
+		if data[i] == escape && i+1 < len(data) {
+			frame = append(frame, data[i+1])
+			i++
+			continue
+		}
+
+		if bytes.HasPrefix(data[i:], delimiter) {
+			frames = append(frames, frame)
+			frame = nil
+			i += len(delimiter) - 1
+			continue
+		}
+
+		frame = append(frame, data[i])
+	}
+
+	return frames
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLengthPrefixFramer$
+func TestLengthPrefixFramer(t *testing.T) {
+	framer := LengthPrefixFramer()
+
+	testCases := [][]byte{nil, []byte("\n"), []byte("abc\r\n\x00\xff"), bytes.Repeat([]byte{'x'}, 1024)}
+	for _, testCase := range testCases {
+		frame := framer([]byte("prefix"), testCase)
+
+		if !bytes.HasPrefix(frame, []byte("prefix")) {
+			t.Fatalf("frame %q doesn't have prefix", frame)
+		}
+
+		frame = frame[len("prefix"):]
+		if length := binary.BigEndian.Uint32(frame); int(length) != len(testCase) {
+			t.Fatalf("length %d != len(testCase) %d", length, len(testCase))
+		}
+
+		if !bytes.Equal(frame[4:], testCase) {
+			t.Fatalf("frame[4:] %q != testCase %q", frame[4:], testCase)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDelimiterFramer$
+func TestDelimiterFramer(t *testing.T) {
+	testCases := []struct {
+		delimiter []byte
+		escape    byte
+		data      []byte
+		want      string
+	}{
+		{delimiter: []byte("\r\n"), escape: '\\', data: []byte("abc"), want: "abc\r\n"},
+		{delimiter: []byte("\r\n"), escape: '\\', data: []byte("a\r\nb\\c\n"), want: "a\\\r\nb\\\\c\n\r\n"},
+		{delimiter: []byte{0}, escape: 0x1b, data: []byte{1, 0, 0x1b, 2}, want: "\x01\x1b\x00\x1b\x1b\x02\x00"},
+		{delimiter: []byte("|"), escape: '\\', data: nil, want: "|"},
+	}
+
+	for _, testCase := range testCases {
+		framer := DelimiterFramer(testCase.delimiter, testCase.escape)
+
+		frame := framer(nil, testCase.data)
+		if string(frame) != testCase.want {
+			t.Fatalf("frame %q != testCase.want %q", frame, testCase.want)
+		}
+
+		frames := unescapeFrames(frame, testCase.delimiter, testCase.escape)
+		if len(frames) != 1 || !bytes.Equal(frames[0], testCase.data) {
+			t.Fatalf("frames %q is wrong", frames)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDelimiterFramerPanic$
+func TestDelimiterFramerPanic(t *testing.T) {
+	testCases := []struct {
+		delimiter []byte
+		escape    byte
+	}{
+		{delimiter: nil, escape: '\\'},
+		{delimiter: []byte("\\n"), escape: '\\'},
+	}
+
+	for _, testCase := range testCases {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("delimiter %q with escape %q should panic", testCase.delimiter, testCase.escape)
+				}
+			}()
+
+			DelimiterFramer(testCase.delimiter, testCase.escape)
+		}()
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCRLFFramer$
+func TestCRLFFramer(t *testing.T) {
+	framer := CRLFFramer()
+
+	frame := framer(nil, []byte("line1\nline2\r\n"))
+	if want := "line1\nline2\\\r\n\r\n"; string(frame) != want {
+		t.Fatalf("frame %q != want %q", frame, want)
+	}
+}
+
+type frameTestWriter struct {
+	bytes.Buffer
+	err    error
+	synced bool
+	closed bool
+}
+
+func (ftw *frameTestWriter) Write(p []byte) (n int, err error) {
+	if ftw.err != nil {
+		return 0, ftw.err
+	}
+
+	return ftw.Buffer.Write(p)
+}
+
+func (ftw *frameTestWriter) Sync() error {
+	ftw.synced = true
+	return nil
+}
+
+func (ftw *frameTestWriter) Close() error {
+	ftw.closed = true
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFrameWriter$
+func TestFrameWriter(t *testing.T) {
+	ftw := new(frameTestWriter)
+	writer := Frame(ftw, CRLFFramer())
+
+	logs := []string{"first log\n", "multi\r\nline\r\nlog\n", "binary \x00\xff log"}
+	for _, log := range logs {
+		n, err := writer.Write([]byte(log))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != len(log) {
+			t.Fatalf("n %d != len(log) %d", n, len(log))
+		}
+	}
+
+	frames := unescapeFrames(ftw.Bytes(), []byte("\r\n"), '\\')
+	if len(frames) != len(logs) {
+		t.Fatalf("len(frames) %d != len(logs) %d", len(frames), len(logs))
+	}
+
+	for i, frame := range frames {
+		if string(frame) != logs[i] {
+			t.Fatalf("frame %q != log %q", frame, logs[i])
+		}
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ftw.synced {
+		t.Fatal("ftw isn't synced")
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ftw.closed {
+		t.Fatal("ftw isn't closed")
+	}
+
+	ftw.err = errors.New("write failed")
+	if n, err := writer.Write([]byte("log")); err != ftw.err || n != 0 {
+		t.Fatalf("n %d, err %+v is wrong", n, err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"math/bits"
+)
+
+const (
+	// WriteSizeBuckets is the count of buckets in the distribution of write sizes.
+	WriteSizeBuckets = 32
+)
+
+// FlushReason is the reason why a writer flushes its buffer to the underlying writer.
+type FlushReason uint8
+
+const (
+	// FlushFull means the buffer is full or the batch is complete.
+	FlushFull FlushReason = iota
+
+	// FlushInterval means the buffer is flushed by a sync timer, see logit.WithSyncTimer.
+	FlushInterval
+
+	// FlushSync means the buffer is flushed by calling Sync.
+	FlushSync
+
+	// FlushClose means the buffer is flushed by calling Close.
+	FlushClose
+
+	flushReasons
+)
+
+// String returns the name of reason.
+func (fr FlushReason) String() string {
+	switch fr {
+	case FlushFull:
+		return "full"
+	case FlushInterval:
+		return "interval"
+	case FlushSync:
+		return "sync"
+	case FlushClose:
+		return "close"
+	default:
+		return "unknown"
+	}
+}
+
+// ReasonSyncer is a syncer which can sync with a reason, so the reason can be recorded in stats.
+type ReasonSyncer interface {
+	SyncWithReason(reason FlushReason) error
+}
+
+// WriteStats is the stats of a writer which is useful for tuning buffer size or batch size.
+type WriteStats struct {
+	// Writes is the count of writes.
+	Writes uint64
+
+	// WriteBytes is the total bytes of writes.
+	WriteBytes uint64
+
+	// WriteSizes is the distribution of write sizes.
+	// WriteSizes[0] is the count of empty writes and WriteSizes[i] is the count of writes in [2^(i-1), 2^i) bytes.
+	// The last bucket also counts all writes larger than it.
+	WriteSizes [WriteSizeBuckets]uint64
+
+	// Flushes is the count of flushes indexed by reasons.
+	Flushes [flushReasons]uint64
+
+	// FlushBytes is the total bytes of flushes indexed by reasons.
+	FlushBytes [flushReasons]uint64
+}
+
+// writeSizeBucket returns the bucket of size in WriteStats.WriteSizes.
+func writeSizeBucket(size int) int {
+	bucket := bits.Len64(uint64(size))
+	if bucket >= WriteSizeBuckets {
+		bucket = WriteSizeBuckets - 1
+	}
+
+	return bucket
+}
+
+// recordWrite records a write of size to stats.
+func (ws *WriteStats) recordWrite(size int) {
+	if ws == nil {
+		return
+	}
+
+	ws.Writes++
+	ws.WriteBytes += uint64(size)
+	ws.WriteSizes[writeSizeBucket(size)]++
+}
+
+// recordFlush records a flush of size with reason to stats.
+// Empty flushes won't be recorded because nothing is written to the underlying writer.
+func (ws *WriteStats) recordFlush(reason FlushReason, size int) {
+	if ws == nil || size <= 0 || reason >= flushReasons {
+		return
+	}
+
+	ws.Flushes[reason]++
+	ws.FlushBytes[reason] += uint64(size)
+}
+
+// snapshot returns a copy of stats and reports whether stats is enabled.
+func (ws *WriteStats) snapshot() (WriteStats, bool) {
+	if ws == nil {
+		return WriteStats{}, false
+	}
+
+	return *ws, true
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFlushReason$
+func TestFlushReason(t *testing.T) {
+	testCases := map[FlushReason]string{
+		FlushFull:     "full",
+		FlushInterval: "interval",
+		FlushSync:     "sync",
+		FlushClose:    "close",
+		flushReasons:  "unknown",
+	}
+
+	for reason, want := range testCases {
+		if reason.String() != want {
+			t.Fatalf("reason.String() %s != want %s", reason.String(), want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriteSizeBucket$
+func TestWriteSizeBucket(t *testing.T) {
+	testCases := map[int]int{
+		0:       0,
+		1:       1,
+		2:       2,
+		3:       2,
+		4:       3,
+		1023:    10,
+		1024:    11,
+		1 << 40: WriteSizeBuckets - 1,
+	}
+
+	for size, want := range testCases {
+		if bucket := writeSizeBucket(size); bucket != want {
+			t.Fatalf("size %d: bucket %d != want %d", size, bucket, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriteStats$
+func TestWriteStats(t *testing.T) {
+	var stats *WriteStats
+	stats.recordWrite(1)
+	stats.recordFlush(FlushSync, 1)
+
+	if _, ok := stats.snapshot(); ok {
+		t.Fatal("nil stats should be disabled")
+	}
+
+	stats = new(WriteStats)
+	stats.recordWrite(3)
+	stats.recordWrite(5)
+	stats.recordFlush(FlushSync, 8)
+	stats.recordFlush(FlushClose, 0)
+
+	got, ok := stats.snapshot()
+	if !ok {
+		t.Fatal("stats should be enabled")
+	}
+
+	if got.Writes != 2 || got.WriteBytes != 8 || got.WriteSizes[2] != 1 || got.WriteSizes[3] != 1 {
+		t.Fatalf("got %+v is wrong", got)
+	}
+
+	if got.Flushes[FlushSync] != 1 || got.FlushBytes[FlushSync] != 8 || got.Flushes[FlushClose] != 0 {
+		t.Fatalf("got %+v is wrong", got)
+	}
+}
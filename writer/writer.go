@@ -27,3 +27,13 @@ const (
 func notStdoutAndStderr(w io.Writer) bool {
 	return w != os.Stdout && w != os.Stderr
 }
+
+// syncWriter syncs w if w implements Sync() error and isn't stdout and stderr.
+// Syncing stdout and stderr may fail if they are terminals or pipes, so they are skipped.
+func syncWriter(w io.Writer) error {
+	if syncer, ok := w.(interface{ Sync() error }); ok && notStdoutAndStderr(w) {
+		return syncer.Sync()
+	}
+
+	return nil
+}
@@ -29,3 +29,19 @@ func TestNotStdoutAndStderr(t *testing.T) {
 		t.Fatal("notStdoutAndStderr(os.Stderr) returns true")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSyncWriter$
+func TestSyncWriter(t *testing.T) {
+	if err := syncWriter(os.Stdout); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := &testSyncWriter{}
+	if err := syncWriter(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	if writer.synced != 1 {
+		t.Fatalf("writer.synced %d is wrong", writer.synced)
+	}
+}